@@ -33,6 +33,7 @@ const (
 // All predefined providers are listed here.
 const (
 	MEMORY = "memory"
+	TIERED = "tiered"
 )
 
 type providerFn func(opt interface{}) (Interface, error)
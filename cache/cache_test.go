@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/patrickascher/gofer/cache"
+	"github.com/patrickascher/gofer/cache/memory"
 	"github.com/patrickascher/gofer/cache/mocks"
 	"github.com/patrickascher/gofer/registry"
 	"github.com/stretchr/testify/assert"
@@ -63,3 +64,67 @@ func TestProvider(t *testing.T) {
 	mockProvider.AssertExpectations(t)
 
 }
+
+// TestManager_Codec tests:
+// - If a value is serialized by the JSON and gob codec and round-trips by Scan.
+// - If the value is set as it is, if no codec is defined.
+// - error: destination is no ptr.
+// - error: value type is not assignable to the destination.
+func TestManager_Codec(t *testing.T) {
+	asserts := assert.New(t)
+
+	type user struct {
+		Name string
+		Tags []string
+	}
+
+	err := cache.Register("memoryCodec", memory.New)
+	asserts.NoError(err)
+	mgr, err := cache.New("memoryCodec", nil)
+	asserts.NoError(err)
+
+	// ok: json
+	mgr.SetCodec(cache.JSONCodec)
+	err = mgr.Set("users", "1", user{Name: "John", Tags: []string{"a", "b"}}, cache.NoExpiration)
+	asserts.NoError(err)
+	item, err := mgr.Get("users", "1")
+	asserts.NoError(err)
+	asserts.Equal([]byte(`{"Name":"John","Tags":["a","b"]}`), item.Value())
+	var u user
+	err = mgr.Scan("users", "1", &u)
+	asserts.NoError(err)
+	asserts.Equal(user{Name: "John", Tags: []string{"a", "b"}}, u)
+
+	// ok: gob
+	mgr.SetCodec(cache.GobCodec)
+	err = mgr.Set("users", "2", user{Name: "Doe"}, cache.NoExpiration)
+	asserts.NoError(err)
+	u = user{}
+	err = mgr.Scan("users", "2", &u)
+	asserts.NoError(err)
+	asserts.Equal(user{Name: "Doe"}, u)
+
+	// ok: no codec
+	mgr.SetCodec(nil)
+	err = mgr.Set("users", "3", user{Name: "Foo"}, cache.NoExpiration)
+	asserts.NoError(err)
+	u = user{}
+	err = mgr.Scan("users", "3", &u)
+	asserts.NoError(err)
+	asserts.Equal(user{Name: "Foo"}, u)
+
+	// error: destination is no ptr
+	err = mgr.Scan("users", "3", u)
+	asserts.Error(err)
+	asserts.Equal(fmt.Sprintf(cache.ErrScanDest, "3"), err.Error())
+
+	// error: value is not assignable
+	var s string
+	err = mgr.Scan("users", "3", &s)
+	asserts.Error(err)
+	asserts.Equal(fmt.Sprintf(cache.ErrScanType, "3", "cache_test.user", "string"), err.Error())
+
+	// error: item does not exist
+	err = mgr.Scan("users", "4", &u)
+	asserts.Error(err)
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2021 Patrick Ascher <development@fullhouse-productions.com>. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+)
+
+// Codec serializes the cache values.
+// It should be set on the manager if the provider stores serialized bytes (redis, memcached, ...).
+// Other formats (msgpack, ...) can be added by implementing this interface.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// Predefined codecs.
+var (
+	GobCodec  Codec = gobCodec{}
+	JSONCodec Codec = jsonCodec{}
+)
+
+// gobCodec serializes the values with encoding/gob.
+// Interface values must be registered by gob.Register.
+type gobCodec struct{}
+
+// Marshal encodes the value.
+func (gobCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(v)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes the data into v.
+func (gobCodec) Unmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// jsonCodec serializes the values with encoding/json.
+type jsonCodec struct{}
+
+// Marshal encodes the value.
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal decodes the data into v.
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
@@ -6,6 +6,7 @@ package cache
 
 import (
 	"fmt"
+	"reflect"
 	"sync"
 	"time"
 )
@@ -15,6 +16,7 @@ const prefixSeparator = "_"
 // Manager for cache operations.
 type Manager interface {
 	Get(prefix string, name string) (Item, error)
+	Scan(prefix string, name string, dest interface{}) error
 	Prefix(prefix string) ([]Item, error)
 	All() ([]Item, error)
 	Set(prefix string, name string, value interface{}, exp time.Duration) error
@@ -28,12 +30,14 @@ type Manager interface {
 
 	SetDefaultPrefix(string)
 	SetDefaultExpiration(duration time.Duration)
+	SetCodec(codec Codec)
 }
 
 // manager will hold some default values, statistics and prefixes.
 type manager struct {
 	defaultPrefix     string
 	defaultExpiration time.Duration
+	codec             Codec
 
 	sync       sync.Mutex
 	provider   Interface
@@ -62,6 +66,8 @@ func newManager(provider Interface) Manager {
 // Error messages.
 var (
 	ErrNotExist = "cache: item or prefix %s does not exist"
+	ErrScanDest = "cache: destination of %s must be a non-nil ptr"
+	ErrScanType = "cache: value of %s (%s) is not assignable to %s"
 )
 
 // SetDefaultPrefix for cache items.
@@ -74,6 +80,13 @@ func (m *manager) SetDefaultExpiration(exp time.Duration) {
 	m.defaultExpiration = exp
 }
 
+// SetCodec for the cache values.
+// If a codec is set, the values are serialized on Set and can be deserialized by Scan.
+// By default no codec is set and the values are passed as they are to the provider.
+func (m *manager) SetCodec(codec Codec) {
+	m.codec = codec
+}
+
 // Get returns an Item by its prefix and name.
 // Error will return if it does not exist.
 func (m *manager) Get(prefix string, name string) (Item, error) {
@@ -91,6 +104,38 @@ func (m *manager) Get(prefix string, name string) (Item, error) {
 	return i, nil
 }
 
+// Scan will set the value of the item into dest, which must be a ptr.
+// If a codec is set, the serialized value will be deserialized by it.
+// Error will return if the item does not exist or the value can not be set.
+func (m *manager) Scan(prefix string, name string, dest interface{}) error {
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf(ErrScanDest, name)
+	}
+
+	i, err := m.Get(prefix, name)
+	if err != nil {
+		return err
+	}
+
+	if m.codec != nil {
+		if data, ok := i.Value().([]byte); ok {
+			return m.codec.Unmarshal(data, dest)
+		}
+	}
+
+	v := reflect.ValueOf(i.Value())
+	if !v.IsValid() {
+		rv.Elem().Set(reflect.Zero(rv.Elem().Type()))
+		return nil
+	}
+	if !v.Type().AssignableTo(rv.Elem().Type()) {
+		return fmt.Errorf(ErrScanType, name, v.Type(), rv.Elem().Type())
+	}
+	rv.Elem().Set(v)
+	return nil
+}
+
 // Prefix returns all items with that prefix.
 // Error will return if the prefix does not exist.
 func (m *manager) Prefix(prefix string) ([]Item, error) {
@@ -126,6 +171,7 @@ func (m *manager) All() ([]Item, error) {
 // Set an item by its prefix, name, value and lifetime.
 // If a value should not get deleted by the garbage collector, cache.NoExpiration can be used as time.Duration.
 // If the default expiration should be used, use cache.DefaultExpiration.
+// Any negative duration is handled as cache.NoExpiration.
 func (m *manager) Set(prefix string, name string, value interface{}, exp time.Duration) error {
 	// create prefix entry
 	m.addPrefixEntry(prefix, name)
@@ -133,6 +179,18 @@ func (m *manager) Set(prefix string, name string, value interface{}, exp time.Du
 	if exp == DefaultExpiration {
 		exp = m.defaultExpiration
 	}
+	// normalize negative durations.
+	if exp < 0 {
+		exp = NoExpiration
+	}
+	// serialize the value.
+	if m.codec != nil {
+		data, err := m.codec.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("cache: %w", err)
+		}
+		value = data
+	}
 	err := m.provider.Set(m.prefixedName(prefix, name), value, exp)
 	if err != nil {
 		// wrapping the provider err for a better stack
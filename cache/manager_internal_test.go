@@ -58,6 +58,7 @@ func TestManager(t *testing.T) {
 // testingManagerSet is testing:
 // - if cache.DefaultPrefix are set correctly
 // - if cache.NoExpiration is set correctly
+// - if a negative expiration is handled as cache.NoExpiration.
 // - if cache.DefaultExpiration is manipulated correctly.
 // - error handling if provider returns one.
 // - if the prefix struct is created correctly.
@@ -75,6 +76,11 @@ func testingManagerSet(asserts *assert.Assertions, managerStruct *manager, mockP
 	asserts.Error(err)
 	asserts.Equal("an error", errors.Unwrap(err).Error())
 
+	// testing if a negative expiration is handled as no expiration.
+	mockProvider.On("Set", managerStruct.prefixedName(DefaultPrefix, "foo"), "bar", time.Duration(NoExpiration)).Once().Return(nil)
+	err = managerStruct.Set(DefaultPrefix, "foo", "bar", -5*time.Second)
+	asserts.NoError(err)
+
 	// testing set with the default prefix and no expiration time
 	mockProvider.On("Set", managerStruct.prefixedName("names", "john"), "doe", managerStruct.defaultExpiration).Once().Return(nil)
 	err = managerStruct.Set("names", "john", "doe", DefaultExpiration)
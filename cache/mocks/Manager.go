@@ -167,6 +167,20 @@ func (_m *Manager) Prefix(prefix string) ([]cache.Item, error) {
 	return r0, r1
 }
 
+// Scan provides a mock function with given fields: prefix, name, dest
+func (_m *Manager) Scan(prefix string, name string, dest interface{}) error {
+	ret := _m.Called(prefix, name, dest)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, interface{}) error); ok {
+		r0 = rf(prefix, name, dest)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Set provides a mock function with given fields: prefix, name, value, exp
 func (_m *Manager) Set(prefix string, name string, value interface{}, exp time.Duration) error {
 	ret := _m.Called(prefix, name, value, exp)
@@ -181,6 +195,11 @@ func (_m *Manager) Set(prefix string, name string, value interface{}, exp time.D
 	return r0
 }
 
+// SetCodec provides a mock function with given fields: codec
+func (_m *Manager) SetCodec(codec cache.Codec) {
+	_m.Called(codec)
+}
+
 // SetDefaultExpiration provides a mock function with given fields: duration
 func (_m *Manager) SetDefaultExpiration(duration time.Duration) {
 	_m.Called(duration)
@@ -0,0 +1,140 @@
+// Copyright (c) 2021 Patrick Ascher <development@fullhouse-productions.com>. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package tiered implements the cache.Interface and registers a tiered provider.
+// A fast local tier (memory) is backed by a remote tier (redis, memcached, ...).
+// Get checks the local tier first and populates it on a remote hit.
+// Set, Delete and DeleteAll are applied on both tiers.
+package tiered
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/patrickascher/gofer/cache"
+)
+
+// init registers the tiered provider.
+func init() {
+	err := cache.Register(cache.TIERED, New)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// Error messages
+var (
+	ErrOptions      = errors.New("tiered: options Local and Remote are mandatory")
+	ErrNameNotExist = "tiered: name %v does not exist"
+)
+
+// Options for the tiered provider.
+type Options struct {
+	// Local tier, usually a memory cache.
+	Local cache.Interface
+	// Remote tier, usually a shared cache like redis.
+	Remote cache.Interface
+	// LocalExpiration is the maximum lifetime of an item in the local tier.
+	// If zero, the expiration of the item is used.
+	LocalExpiration time.Duration
+}
+
+// New creates a tiered cache by the given options.
+// Error will return if the local or remote tier is missing.
+func New(opt interface{}) (cache.Interface, error) {
+	options, ok := opt.(Options)
+	if !ok || options.Local == nil || options.Remote == nil {
+		return nil, ErrOptions
+	}
+	return &tiered{options: options}, nil
+}
+
+// tiered cache provider.
+type tiered struct {
+	options Options
+}
+
+// Get returns the item of the local tier.
+// If it does not exist or is expired, the remote tier is checked and the local tier will be populated with the remaining lifetime.
+// Error will return if the name does not exist in both tiers.
+func (t *tiered) Get(name string) (cache.Item, error) {
+	if item, err := t.options.Local.Get(name); err == nil && !expired(item) {
+		return item, nil
+	}
+
+	item, err := t.options.Remote.Get(name)
+	if err != nil || expired(item) {
+		return nil, fmt.Errorf(ErrNameNotExist, name)
+	}
+
+	exp := item.Expiration()
+	if exp != cache.NoExpiration {
+		exp -= time.Since(item.Created())
+	}
+	err = t.options.Local.Set(name, item.Value(), t.localExpiration(exp))
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// All returns all items of the remote tier.
+func (t *tiered) All() ([]cache.Item, error) {
+	return t.options.Remote.All()
+}
+
+// Set the item on both tiers.
+// The local tier expiration is limited by the LocalExpiration option.
+func (t *tiered) Set(name string, value interface{}, exp time.Duration) error {
+	err := t.options.Remote.Set(name, value, exp)
+	if err != nil {
+		return err
+	}
+	return t.options.Local.Set(name, value, t.localExpiration(exp))
+}
+
+// Delete removes the item of both tiers.
+// Error will return if the name does not exist in both tiers.
+func (t *tiered) Delete(name string) error {
+	errLocal := t.options.Local.Delete(name)
+	errRemote := t.options.Remote.Delete(name)
+	if errLocal != nil && errRemote != nil {
+		return fmt.Errorf(ErrNameNotExist, name)
+	}
+	return nil
+}
+
+// DeleteAll removes all items of both tiers.
+func (t *tiered) DeleteAll() error {
+	err := t.options.Local.DeleteAll()
+	if err != nil {
+		return err
+	}
+	return t.options.Remote.DeleteAll()
+}
+
+// GC calls the garbage collector of both tiers.
+func (t *tiered) GC() {
+	go t.options.Local.GC()
+	t.options.Remote.GC()
+}
+
+// localExpiration returns the expiration for the local tier.
+func (t *tiered) localExpiration(exp time.Duration) time.Duration {
+	if t.options.LocalExpiration > 0 && (exp == cache.NoExpiration || exp > t.options.LocalExpiration) {
+		return t.options.LocalExpiration
+	}
+	return exp
+}
+
+// expired returns true if the lifetime of the item is exceeded.
+func expired(item cache.Item) bool {
+	if item.Expiration() == cache.NoExpiration {
+		return false
+	}
+	return time.Since(item.Created()) > item.Expiration()
+}
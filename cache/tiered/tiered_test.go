@@ -0,0 +1,112 @@
+// Copyright (c) 2021 Patrick Ascher <development@fullhouse-productions.com>. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package tiered_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/patrickascher/gofer/cache"
+	"github.com/patrickascher/gofer/cache/memory"
+	"github.com/patrickascher/gofer/cache/tiered"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNew tests:
+// - error if the tiers are missing
+func TestNew(t *testing.T) {
+	asserts := assert.New(t)
+
+	// error: no options
+	c, err := tiered.New(nil)
+	asserts.Error(err)
+	asserts.Equal(tiered.ErrOptions, err)
+	asserts.Nil(c)
+
+	// error: remote is missing
+	local, _ := memory.New(nil)
+	c, err = tiered.New(tiered.Options{Local: local})
+	asserts.Error(err)
+	asserts.Nil(c)
+}
+
+// TestTiered tests:
+// - set writes both tiers
+// - remote hit populates the local tier
+// - local expiration is honored
+// - delete and delete all clears both tiers
+func TestTiered(t *testing.T) {
+	asserts := assert.New(t)
+
+	local, _ := memory.New(nil)
+	remote, _ := memory.New(nil)
+	c, err := tiered.New(tiered.Options{Local: local, Remote: remote, LocalExpiration: time.Hour})
+	asserts.NoError(err)
+
+	// ok: set on both tiers
+	err = c.Set("foo", "bar", cache.NoExpiration)
+	asserts.NoError(err)
+	item, err := local.Get("foo")
+	asserts.NoError(err)
+	asserts.Equal("bar", item.Value())
+	asserts.Equal(time.Hour, item.Expiration())
+	item, err = remote.Get("foo")
+	asserts.NoError(err)
+	asserts.Equal(time.Duration(cache.NoExpiration), item.Expiration())
+
+	// ok: remote only hit populates the local tier
+	err = remote.Set("John", "Doe", time.Minute)
+	asserts.NoError(err)
+	_, err = local.Get("John")
+	asserts.Error(err)
+	item, err = c.Get("John")
+	asserts.NoError(err)
+	asserts.Equal("Doe", item.Value())
+	item, err = local.Get("John")
+	asserts.NoError(err)
+	asserts.Equal("Doe", item.Value())
+	asserts.True(item.Expiration() <= time.Minute)
+
+	// ok: expired local item falls back to remote
+	err = local.Set("John", "Expired", time.Nanosecond)
+	asserts.NoError(err)
+	time.Sleep(time.Millisecond)
+	item, err = c.Get("John")
+	asserts.NoError(err)
+	asserts.Equal("Doe", item.Value())
+
+	// error: expired on both tiers
+	err = c.Set("exp", "value", time.Nanosecond)
+	asserts.NoError(err)
+	time.Sleep(time.Millisecond)
+	item, err = c.Get("exp")
+	asserts.Error(err)
+	asserts.Equal(fmt.Sprintf(tiered.ErrNameNotExist, "exp"), err.Error())
+	asserts.Nil(item)
+
+	// ok: delete both tiers
+	err = c.Delete("foo")
+	asserts.NoError(err)
+	_, err = local.Get("foo")
+	asserts.Error(err)
+	_, err = remote.Get("foo")
+	asserts.Error(err)
+
+	// error: name does not exist
+	err = c.Delete("foo")
+	asserts.Error(err)
+	asserts.Equal(fmt.Sprintf(tiered.ErrNameNotExist, "foo"), err.Error())
+
+	// ok: delete all
+	err = c.DeleteAll()
+	asserts.NoError(err)
+	items, err := local.All()
+	asserts.NoError(err)
+	asserts.Nil(items)
+	items, err = remote.All()
+	asserts.NoError(err)
+	asserts.Nil(items)
+}
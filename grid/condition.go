@@ -5,6 +5,7 @@
 package grid
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/patrickascher/gofer/query"
 	"github.com/patrickascher/gofer/query/condition"
+	"github.com/patrickascher/gofer/query/types"
 )
 
 // internal constants.
@@ -20,12 +22,16 @@ const (
 	conditionSortSeparator   = ","
 	conditionFilterPrefix    = "filter_"
 	conditionFilterSeparator = ";"
+	conditionFilterJoinKey   = "filterJoin"
+	conditionFilterJoinOr    = "or"
 )
 
 // Error messages.
 var (
 	ErrFieldPrimary    = "grid: primary is not set for %s"
 	ErrFieldPermission = "grid: field %s id not allowed to %s or does not exist"
+	ErrFilterOperator  = "grid: filter operator %s is not supported by the source"
+	ErrFilterBool      = "grid: filter value %s is not a boolean (%s)"
 )
 
 // conditionFirst returns a condition for one row by the given primary param.
@@ -105,7 +111,12 @@ func (g *grid) userFilter(c condition.Condition) error {
 						break
 					}
 				}
-				g.fields[i].SetRemove(remove).SetHidden(remove)
+				// keep a different export value of the field (screen-only or export-only fields).
+				v := NewValue(remove)
+				if g.fields[i].remove.export != g.fields[i].remove.table {
+					v.SetExport(g.fields[i].remove.export)
+				}
+				g.fields[i].SetRemove(v).SetHidden(remove)
 			}
 		}
 
@@ -155,8 +166,8 @@ func (g *grid) userFilter(c condition.Condition) error {
 				case "LLIKE":
 					c.SetWhere(gridField.filterField+" LIKE ?", "%%"+escape(f.Value.String))
 				case query.CUSTOM, query.CUSTOMLIKE:
-					var argsCustom []interface{}
-					for i := 0; i < strings.Count(gridField.filterField, "?"); i++ {
+					argsCustom := append([]interface{}(nil), gridField.filterArgs...)
+					for i := len(gridField.filterArgs); i < strings.Count(gridField.filterField, "?"); i++ {
 						if gridField.filterCondition == query.CUSTOMLIKE {
 							argsCustom = append(argsCustom, "%%"+escape(f.Value.String)+"%%")
 						} else {
@@ -209,6 +220,10 @@ func (g *grid) userFilter(c condition.Condition) error {
 // conditionAll return a condition for the grid table and export view.
 // If a grid condition exists, this condition will be appended.
 // Sort and filter_ params are checked. (sort=ID,-Name) (filter_ID=1&filter_Name=John;Doe)
+// If no sort or filter param is requested, the config DefaultSort and DefaultFilter will be used.
+// A saved user filter (filter=ID) counts as sorted and filtered, so its sorting is not replaced by the DefaultSort.
+// With the param filterJoin=or, the field filters are chained by OR instead of AND. The grid condition is still chained by AND.
+// Filters on select expression aliases are added as HAVING and always chained by AND.
 // Error will return if the sort/filter_ field does not exist or has no permission.
 func (g *grid) conditionAll() (condition.Condition, error) {
 
@@ -231,9 +246,18 @@ func (g *grid) conditionAll() (condition.Condition, error) {
 		return nil, err
 	}
 
+	// field filters are added to an own condition if they should be chained by OR.
+	fc := c
+	if join, ok := params[conditionFilterJoinKey]; ok && strings.ToLower(join[0]) == conditionFilterJoinOr {
+		fc = condition.New()
+	}
+
 	// check if sort or filter param keys exist.
+	_, filtered := params["filter"]
+	sorted := filtered
 	for key, param := range params {
 		if key == conditionSortKey {
+			sorted = true
 			c.Reset(condition.ORDER)
 			err := addSortCondition(g, param[0], c)
 			if err != nil {
@@ -241,16 +265,46 @@ func (g *grid) conditionAll() (condition.Condition, error) {
 			}
 		}
 		if strings.HasPrefix(key, conditionFilterPrefix) {
-			err := addFilterCondition(g, key[len(conditionFilterPrefix):], param, c)
+			filtered = true
+			err := addFilterCondition(g, key[len(conditionFilterPrefix):], param, fc)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// default sort and filter on first load.
+	if !sorted && g.config.DefaultSort != "" {
+		c.Reset(condition.ORDER)
+		err := addSortCondition(g, g.config.DefaultSort, c)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !filtered {
+		for field, param := range g.config.DefaultFilter {
+			err := addFilterCondition(g, field, param, fc)
 			if err != nil {
 				return nil, err
 			}
 		}
 	}
+	if fc != c {
+		c.SetWhereOr(fc)
+		// HAVING filters can not be chained with the WHERE filters, they are added by AND.
+		for _, h := range fc.Having() {
+			c.SetHaving(h.Condition(), h.Arguments()...)
+		}
+	}
 
 	return c, nil
 }
 
+// capabilities can be implemented by a source to allow provider specific filter operators.
+type capabilities interface {
+	Capabilities() query.Capabilities
+}
+
 // addFilterCondition adds a where condition with the given params.
 // If there is more than one argument, the condition operator IN will be used.
 // Error will return if the field does not exist or the field has no permission for filter.
@@ -258,10 +312,15 @@ func addFilterCondition(g *grid, field string, params []string, c condition.Cond
 
 	if gridField := g.Field(field); gridField.error == nil && gridField.filterAble && !g.config.Filter.Disable {
 
+		// select expression aliases are not allowed in WHERE, the condition is added as HAVING.
+		if gridField.filterHaving {
+			c = havingCondition{Condition: c}
+		}
+
 		args := strings.Split(escape(params[0]), conditionFilterSeparator)
 
 		// TODO what is with not... conditions - taking care of?
-		if len(args) > 1 && gridField.filterCondition != query.IN && gridField.filterCondition != query.NOTIN {
+		if len(args) > 1 && gridField.filterCondition != query.IN && gridField.filterCondition != query.NOTIN && gridField.filterCondition != query.JSONCONTAINS {
 			gridField.filterCondition = query.IN
 		}
 
@@ -379,6 +438,14 @@ func addFilterCondition(g *grid, field string, params []string, c condition.Cond
 		case query.NULL, query.NOTNULL:
 			c.SetWhere(gridField.filterField + " " + gridField.filterCondition)
 		case query.IN, query.NOTIN:
+			if gridField.fType == types.BOOL {
+				values, err := boolArgs(field, args)
+				if err != nil {
+					return err
+				}
+				c.SetWhere(gridField.filterField+" "+gridField.filterCondition, values)
+				break
+			}
 			c.SetWhere(gridField.filterField+" "+gridField.filterCondition, args)
 		case query.RIN, query.RNOTIN:
 			c.SetWhere(gridField.filterCondition+" "+gridField.filterField, args)
@@ -391,9 +458,22 @@ func addFilterCondition(g *grid, field string, params []string, c condition.Cond
 				//TODO check % in text because its escaped by \ from golang.
 				c.SetWhere("UPPER("+gridField.filterField+") = ?", strings.ToUpper(args[0]))
 			}
+		case query.JSONCONTAINS, query.ANY:
+			if src, ok := g.src.(capabilities); !ok || !src.Capabilities().Supports(query.CapJSONContains) {
+				return fmt.Errorf(ErrFilterOperator, gridField.filterCondition)
+			}
+			if gridField.filterCondition == query.ANY {
+				c.SetWhere("? "+gridField.filterCondition+"("+gridField.filterField+")", args[0])
+				break
+			}
+			v, err := json.Marshal(args)
+			if err != nil {
+				return err
+			}
+			c.SetWhere(gridField.filterField+" "+gridField.filterCondition, string(v))
 		case query.CUSTOM, query.CUSTOMLIKE:
-			var argsCustom []interface{}
-			for i := 0; i < strings.Count(gridField.filterField, "?"); i++ {
+			argsCustom := append([]interface{}(nil), gridField.filterArgs...)
+			for i := len(gridField.filterArgs); i < strings.Count(gridField.filterField, "?"); i++ {
 				if gridField.filterCondition == query.CUSTOMLIKE {
 					argsCustom = append(argsCustom, "%%"+args[0]+"%%")
 				} else {
@@ -402,6 +482,14 @@ func addFilterCondition(g *grid, field string, params []string, c condition.Cond
 			}
 			c.SetWhere(gridField.filterField, argsCustom...)
 		default:
+			if gridField.fType == types.BOOL {
+				values, err := boolArgs(field, args)
+				if err != nil {
+					return err
+				}
+				c.SetWhere(gridField.filterField+" "+gridField.filterCondition, values[0])
+				break
+			}
 			c.SetWhere(gridField.filterField+" "+gridField.filterCondition, args[0])
 		}
 
@@ -411,6 +499,35 @@ func addFilterCondition(g *grid, field string, params []string, c condition.Cond
 	return fmt.Errorf(ErrFieldPermission, field, "filter")
 }
 
+// havingCondition adds all WHERE clauses as HAVING clauses.
+// It is used for filters on select expression aliases.
+type havingCondition struct {
+	condition.Condition
+}
+
+// SetWhere adds the clause as HAVING.
+func (h havingCondition) SetWhere(stmt string, args ...interface{}) condition.Condition {
+	return h.Condition.SetHaving(stmt, args...)
+}
+
+// boolArgs converts the filter arguments of a boolean field.
+// The values true/false, 1/0, yes/no, on/off are accepted (case insensitive).
+// Error will return if a value can not be parsed.
+func boolArgs(field string, args []string) ([]interface{}, error) {
+	values := make([]interface{}, len(args))
+	for i, arg := range args {
+		switch strings.ToLower(strings.TrimSpace(arg)) {
+		case "true", "1", "yes", "on":
+			values[i] = true
+		case "false", "0", "no", "off":
+			values[i] = false
+		default:
+			return nil, fmt.Errorf(ErrFilterBool, arg, field)
+		}
+	}
+	return values, nil
+}
+
 // addSortCondition adds an ORDER BY condition with the given controller params.
 // Error will return if the field is not allowed to sort or does not exist.
 func addSortCondition(g *grid, params string, c condition.Condition) error {
@@ -5,6 +5,7 @@
 package grid
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/patrickascher/gofer/query"
 	"github.com/patrickascher/gofer/query/condition"
+	"github.com/patrickascher/gofer/query/types"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -73,14 +75,16 @@ func TestGrid_conditionAll(t *testing.T) {
 	pcondition := condition.New().SetWhere("1=1 AND 2=?", 2)
 
 	var tests = []struct {
-		name     string
-		error    error
-		fieldErr error
-		cond     condition.Condition
-		filterOP string
-		stmt     string
-		args     interface{}
-		req      *http.Request
+		name          string
+		error         error
+		fieldErr      error
+		defaultSort   string
+		defaultFilter map[string][]string
+		cond          condition.Condition
+		filterOP      string
+		stmt          string
+		args          interface{}
+		req           *http.Request
 	}{
 		{name: "no link condition", error: nil, stmt: "", cond: nil, args: nil, req: httptest.NewRequest("GET", "https://example.com", nil)},
 		{name: "no link condition - w. pre cond", error: nil, cond: pcondition, stmt: "WHERE 1=1 AND 2=?", args: []interface{}{2}, req: httptest.NewRequest("GET", "https://example.com", nil)},
@@ -100,6 +104,14 @@ func TestGrid_conditionAll(t *testing.T) {
 		{name: "filter multiple arguments", filterOP: query.NOTLIKE, error: nil, cond: nil, stmt: "WHERE id IN (?, ?)", args: []interface{}{"1", "2"}, req: httptest.NewRequest("GET", "https://example.com?filter_ID="+url.QueryEscape("1;2"), nil)},
 		{name: "filter field err", error: fmt.Errorf(ErrFieldPermission, "ID", "filter"), fieldErr: errors.New("an error"), cond: nil, stmt: "WHERE id IN (?, ?)", args: []interface{}{"1", "2"}, req: httptest.NewRequest("GET", "https://example.com?filter_ID="+url.QueryEscape("1;2"), nil)},
 
+		{name: "default sort", defaultSort: "-Name", error: nil, cond: nil, stmt: "ORDER BY name DESC", args: nil, req: httptest.NewRequest("GET", "https://example.com", nil)},
+		{name: "default sort overwritten", defaultSort: "-Name", error: nil, cond: nil, stmt: "ORDER BY id ASC", args: nil, req: httptest.NewRequest("GET", "https://example.com?sort=ID", nil)},
+		{name: "default filter", defaultFilter: map[string][]string{"ID": {"1"}}, error: nil, cond: nil, stmt: "WHERE id = ?", args: []interface{}{"1"}, req: httptest.NewRequest("GET", "https://example.com", nil)},
+		{name: "default filter overwritten", defaultFilter: map[string][]string{"ID": {"1"}}, error: nil, cond: nil, stmt: "WHERE id IN (?, ?)", args: []interface{}{"2", "3"}, req: httptest.NewRequest("GET", "https://example.com?filter_ID="+url.QueryEscape("2;3"), nil)},
+
+		{name: "filter join or", error: nil, cond: pcondition, stmt: "WHERE 1=1 AND 2=? AND ((id = ?))", args: []interface{}{2, "1"}, req: httptest.NewRequest("GET", "https://example.com?filterJoin=or&filter_ID=1", nil)},
+		{name: "filter join and", error: nil, cond: pcondition, stmt: "WHERE 1=1 AND 2=? AND id = ?", args: []interface{}{2, "1"}, req: httptest.NewRequest("GET", "https://example.com?filterJoin=and&filter_ID=1", nil)},
+
 		{name: "all together", error: nil, cond: pcondition, stmt: "WHERE 1=1 AND 2=? AND id IN (?, ?) ORDER BY id ASC, name DESC", args: []interface{}{2, "1", "2"}, req: httptest.NewRequest("GET", "https://example.com?sort=ID,-Name&filter_ID="+url.QueryEscape("1;2"), nil)},
 	}
 	for _, test := range tests {
@@ -115,6 +127,8 @@ func TestGrid_conditionAll(t *testing.T) {
 			if test.fieldErr != nil {
 				g.(*grid).fields[0].error = test.fieldErr
 			}
+			g.(*grid).config.DefaultSort = test.defaultSort
+			g.(*grid).config.DefaultFilter = test.defaultFilter
 			c, err := g.(*grid).conditionAll()
 
 			if test.error == nil {
@@ -143,3 +157,141 @@ func TestGrid_conditionAll(t *testing.T) {
 		})
 	}
 }
+
+// capabilitySource is used to test provider specific filter operators.
+type capabilitySource struct {
+	Source
+	features []query.Capability
+}
+
+func (s capabilitySource) Capabilities() query.Capabilities {
+	return s
+}
+
+func (s capabilitySource) Supports(feature query.Capability) bool {
+	for _, f := range s.features {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// TestGrid_conditionAll_JSONContains tests:
+// - error if the source does not support the json operators.
+// - ok: json contains binds a json array.
+// - ok: any binds the argument.
+func TestGrid_conditionAll_JSONContains(t *testing.T) {
+	asserts := assert.New(t)
+
+	var tests = []struct {
+		name     string
+		filterOP string
+		filter   string
+		features []query.Capability
+		error    error
+		stmt     string
+		args     []interface{}
+	}{
+		{name: "json contains not supported", filterOP: query.JSONCONTAINS, filter: "1;2", error: fmt.Errorf(ErrFilterOperator, query.JSONCONTAINS)},
+		{name: "any not supported", filterOP: query.ANY, filter: "1", error: fmt.Errorf(ErrFilterOperator, query.ANY)},
+		{name: "json contains", filterOP: query.JSONCONTAINS, filter: "1;2", features: []query.Capability{query.CapJSONContains}, stmt: "WHERE id @> ?", args: []interface{}{`["1","2"]`}},
+		{name: "any", filterOP: query.ANY, filter: "1", features: []query.Capability{query.CapJSONContains}, stmt: "WHERE ? = ANY(id)", args: []interface{}{"1"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "https://example.com?filter_ID="+url.QueryEscape(test.filter), nil)
+			req = req.WithContext(context.WithValue(req.Context(), "router_params", map[string][]string{}))
+			g, _, _, _, _ := mockGrid(t, req)
+			g.(*grid).src = capabilitySource{Source: g.(*grid).src, features: test.features}
+			g.(*grid).fields[0].filterCondition = test.filterOP
+
+			c, err := g.(*grid).conditionAll()
+			if test.error != nil {
+				asserts.Error(err)
+				asserts.Equal(test.error.Error(), err.Error())
+				asserts.Nil(c)
+				return
+			}
+			asserts.NoError(err)
+			stmt, args, err := c.Render(condition.Placeholder{Char: "?"})
+			asserts.NoError(err)
+			asserts.Equal(test.stmt, stmt)
+			asserts.Equal(test.args, args)
+		})
+	}
+}
+
+// TestGrid_conditionAll_Bool tests:
+// - ok: truthy and falsy strings are converted for a boolean field.
+// - ok: multiple values are converted for the IN condition.
+// - error if the value is not a boolean.
+func TestGrid_conditionAll_Bool(t *testing.T) {
+	asserts := assert.New(t)
+
+	var tests = []struct {
+		name   string
+		filter string
+		error  error
+		stmt   string
+		args   []interface{}
+	}{
+		{name: "yes", filter: "yes", stmt: "WHERE id = ?", args: []interface{}{true}},
+		{name: "true", filter: "TRUE", stmt: "WHERE id = ?", args: []interface{}{true}},
+		{name: "0", filter: "0", stmt: "WHERE id = ?", args: []interface{}{false}},
+		{name: "in", filter: "1;off", stmt: "WHERE id IN (?, ?)", args: []interface{}{true, false}},
+		{name: "invalid", filter: "maybe", error: fmt.Errorf(ErrFilterBool, "maybe", "ID")},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "https://example.com?filter_ID="+url.QueryEscape(test.filter), nil)
+			g, _, _, _, _ := mockGrid(t, req)
+			g.(*grid).fields[0].SetType(types.BOOL)
+
+			c, err := g.(*grid).conditionAll()
+			if test.error != nil {
+				asserts.Error(err)
+				asserts.Equal(test.error.Error(), err.Error())
+				asserts.Nil(c)
+				return
+			}
+			asserts.NoError(err)
+			stmt, args, err := c.Render(condition.Placeholder{Char: "?"})
+			asserts.NoError(err)
+			asserts.Equal(test.stmt, stmt)
+			asserts.Equal(test.args, args)
+		})
+	}
+}
+
+// TestGrid_conditionAll_Having tests:
+// - ok: filters on select expression aliases are added as HAVING.
+// - ok: HAVING filters are chained by AND if the filters are joined by OR.
+func TestGrid_conditionAll_Having(t *testing.T) {
+	asserts := assert.New(t)
+
+	var tests = []struct {
+		name  string
+		query string
+		stmt  string
+		args  []interface{}
+	}{
+		{name: "having", query: "filter_Name=" + url.QueryEscape("2;3"), stmt: "HAVING name IN (?, ?)", args: []interface{}{"2", "3"}},
+		{name: "where and having", query: "filter_ID=1&filter_Name=2", stmt: "WHERE id = ? HAVING name IN (?)", args: []interface{}{"1", "2"}},
+		{name: "or", query: "filter_ID=1&filter_Name=2&filterJoin=or", stmt: "WHERE ((id = ?)) HAVING name IN (?)", args: []interface{}{"1", "2"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "https://example.com?"+test.query, nil)
+			g, _, _, _, _ := mockGrid(t, req)
+			g.(*grid).fields[1].filterHaving = true
+
+			c, err := g.(*grid).conditionAll()
+			asserts.NoError(err)
+			stmt, args, err := c.Render(condition.Placeholder{Char: "?"})
+			asserts.NoError(err)
+			asserts.Equal(test.stmt, stmt)
+			asserts.Equal(test.args, args)
+		})
+	}
+}
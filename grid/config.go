@@ -19,6 +19,12 @@ type Config struct {
 	Description  string `json:"description"`
 	Policy       int    `json:"-"`
 
+	// DefaultSort and DefaultFilter are only used if the request has no sort or filter params and no saved filter.
+	// DefaultSort has the same format as the sort param (ID,-Name).
+	// DefaultFilter keys are the field names (ID:{"1;2"}).
+	DefaultSort   string              `json:"-"`
+	DefaultFilter map[string][]string `json:"-"`
+
 	History HistoryConfig `json:"history,omitempty"`
 	Action  Action        `json:"action,omitempty"`
 	Filter  Filter        `json:"filter,omitempty"`
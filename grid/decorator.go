@@ -0,0 +1,119 @@
+// Copyright (c) 2021 Patrick Ascher <development@fullhouse-productions.com>. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package grid
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/patrickascher/gofer/grid/options"
+)
+
+// regDecorator matches the decorator placeholders like {{Name}} or {{Role.Name}}.
+var regDecorator = regexp.MustCompile(`{{\s*([\w.]+)\s*}}`)
+
+// Decorate will render the decorator option of the field with the given value.
+// Placeholders can be a field name or a nested path like {{Role.Name}}, structs and maps are supported.
+// If the value is a slice, every entry is decorated and joined by the defined separator (default ", ").
+// Missing fields will be rendered empty.
+func (f Field) Decorate(value interface{}) string {
+	opt := f.Option(options.DECORATOR)
+	if len(opt) == 0 {
+		return fmt.Sprint(value)
+	}
+
+	decorator := fmt.Sprint(opt[0])
+	separator := ", "
+	if len(opt) > 1 {
+		separator = fmt.Sprint(opt[1])
+	}
+
+	v := indirectValue(reflect.ValueOf(value))
+	if !v.IsValid() {
+		return ""
+	}
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		var rv []string
+		for i := 0; i < v.Len(); i++ {
+			rv = append(rv, decorate(decorator, v.Index(i)))
+		}
+		return strings.Join(rv, separator)
+	}
+
+	return decorate(decorator, v)
+}
+
+// decorate is a helper to replace all placeholders of the decorator with the values.
+func decorate(decorator string, v reflect.Value) string {
+	return regDecorator.ReplaceAllStringFunc(decorator, func(placeholder string) string {
+		return decoratorValue(v, strings.Split(regDecorator.FindStringSubmatch(placeholder)[1], "."))
+	})
+}
+
+// decoratorValue is a helper to resolve the path of the given value.
+// An empty string will return if the path does not exist or the value is nil.
+func decoratorValue(v reflect.Value, path []string) string {
+	for _, name := range path {
+		v = indirectValue(v)
+		switch v.Kind() {
+		case reflect.Struct:
+			v = v.FieldByName(name)
+		case reflect.Map:
+			if v.Type().Key().Kind() != reflect.String {
+				return ""
+			}
+			v = v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+		default:
+			return ""
+		}
+	}
+
+	v = indirectValue(v)
+	if !v.IsValid() {
+		return ""
+	}
+
+	value := v.Interface()
+	if valuer, ok := value.(driver.Valuer); ok {
+		var err error
+		value, err = valuer.Value()
+		if err != nil || value == nil {
+			return ""
+		}
+	}
+
+	return fmt.Sprint(value)
+}
+
+// indirectValue is a helper to dereference pointers and interfaces.
+func indirectValue(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// rowValue is a helper to return the value of the field name of a struct or map row.
+// Nil will return if it does not exist.
+func rowValue(row reflect.Value, name string) interface{} {
+	row = indirectValue(row)
+	var v reflect.Value
+	switch row.Kind() {
+	case reflect.Struct:
+		v = row.FieldByName(name)
+	case reflect.Map:
+		v = row.MapIndex(reflect.ValueOf(name))
+	}
+	if !v.IsValid() {
+		return nil
+	}
+	return v.Interface()
+}
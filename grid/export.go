@@ -0,0 +1,60 @@
+// Copyright (c) 2021 Patrick Ascher <development@fullhouse-productions.com>. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package grid
+
+import (
+	"fmt"
+
+	"github.com/patrickascher/gofer/controller/context"
+)
+
+// ExportFn writes the export to the controller context.
+// The response writer can be accessed by g.Controller().Context().Response.Writer().
+type ExportFn func(g Grid, head []Field, rows interface{}) error
+
+// RegisterExport registers a custom export type (pdf, vendor formats,...).
+// The export is only allowed if it is added to the grid Config.Exports.
+// Error will return if the name is already registered.
+func RegisterExport(name string, fn ExportFn) error {
+	err := context.RegisterRenderer(name, func() (context.Renderer, error) {
+		return &exportWriter{name: name, fn: fn}, nil
+	})
+	if err != nil {
+		return fmt.Errorf(errWrap, err)
+	}
+
+	// add to the already loaded render types.
+	if availableRenderer != nil {
+		availableRenderer[name] = &exportWriter{name: name, fn: fn}
+	}
+	return nil
+}
+
+// exportWriter is the renderer of a custom export.
+type exportWriter struct {
+	name string
+	fn   ExportFn
+}
+
+func (ew *exportWriter) Name() string {
+	return ew.name
+}
+
+func (ew *exportWriter) Icon() string {
+	return "mdi-file-export"
+}
+
+func (ew *exportWriter) Error(r *context.Response, code int, err error) error {
+	r.Writer().WriteHeader(code)
+	_, err = r.Writer().Write([]byte(err.Error()))
+	return err
+}
+
+// Write calls the export function with the grid, head and data of the response.
+func (ew *exportWriter) Write(r *context.Response) error {
+	g, _ := r.Value(ctrlGrid).(Grid)
+	head, _ := r.Value(ctrlHead).([]Field)
+	return ew.fn(g, head, r.Value(ctrlData))
+}
@@ -8,6 +8,7 @@ import (
 	"encoding/csv"
 	"fmt"
 	"github.com/patrickascher/gofer/controller/context"
+	"github.com/patrickascher/gofer/grid/options"
 	"reflect"
 	"time"
 )
@@ -84,6 +85,12 @@ func (cw *csvWriter) Write(r *context.Response) error {
 		var body []string
 
 		for _, head := range header {
+			// decorated relation fields.
+			if head.Option(options.DECORATOR) != nil {
+				body = append(body, head.Decorate(rowValue(rData.Index(i), head.name)))
+				continue
+			}
+
 			if rData.Index(i).Type().Kind().String() == "struct" {
 				if rData.Index(i).FieldByName(head.name).Type().String() == "query.NullString" {
 					body = append(body, fmt.Sprint(rData.Index(i).FieldByName(head.name).FieldByName("String").Interface()))
@@ -0,0 +1,74 @@
+// Copyright (c) 2021 Patrick Ascher <development@fullhouse-productions.com>. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package grid
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/patrickascher/gofer/controller/context"
+	"github.com/patrickascher/gofer/controller/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRegisterExport tests:
+// - If a registered export is invoked with the grid, head and data.
+// - If the export is rejected by the security check if it is not allowed by the config.
+// - If an unknown export type is rejected.
+// - If an error returns on duplicate registration.
+func TestRegisterExport(t *testing.T) {
+	asserts := assert.New(t)
+
+	var called bool
+	err := RegisterExport("gridTestPdf", func(g Grid, head []Field, rows interface{}) error {
+		called = true
+		asserts.NotNil(g)
+		asserts.Equal(1, len(head))
+		asserts.Equal("srcdata", rows)
+		_, err := g.Scope().Controller().Context().Response.Writer().Write([]byte("pdf"))
+		return err
+	})
+	asserts.NoError(err)
+
+	// error: already registered
+	err = RegisterExport("gridTestPdf", nil)
+	asserts.Error(err)
+
+	// ok: export is invoked for its type
+	w := httptest.NewRecorder()
+	ctx := context.New(w, httptest.NewRequest("GET", "https://example.com?mode=export&type=gridTestPdf", nil))
+	mockController := new(mocks.Interface)
+	mockController.On("Context").Return(ctx)
+	g := &grid{controller: mockController}
+
+	id := Field{mode: FeExport}
+	id.SetName("ID")
+	ctx.Response.SetValue(ctrlGrid, g)
+	ctx.Response.SetValue(ctrlHead, []Field{id})
+	ctx.Response.SetValue(ctrlData, "srcdata")
+	err = ctx.Response.Render("gridTestPdf")
+	asserts.NoError(err)
+	asserts.True(called)
+	asserts.Equal("pdf", w.Body.String())
+
+	availableRenderer, err = context.RenderTypes()
+	asserts.NoError(err)
+
+	// error: export type is not allowed by the config
+	asserts.Equal(fmt.Errorf(ErrSecurity, "export-gridTestPdf"), g.security())
+
+	// ok: export type is allowed
+	g.config.Exports = []ExportType{"gridTestPdf"}
+	asserts.NoError(g.security())
+
+	// error: export type is unknown
+	g.config.Exports = []ExportType{"gridTestPdf", "gridTestUnknown"}
+	ctx = context.New(w, httptest.NewRequest("GET", "https://example.com?mode=export&type=gridTestUnknown", nil))
+	mockController = new(mocks.Interface)
+	mockController.On("Context").Return(ctx)
+	g.controller = mockController
+	asserts.Equal(fmt.Errorf(ErrSecurity, "export-gridTestUnknown"), g.security())
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2021 Patrick Ascher <development@fullhouse-productions.com>. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package grid
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+
+	"github.com/patrickascher/gofer/controller/context"
+	"github.com/patrickascher/gofer/grid/options"
+)
+
+// jsonFlushRows defines after how many rows the response gets flushed.
+const jsonFlushRows = 100
+
+func init() {
+	_ = context.RegisterRenderer(JSON, newJSON)
+}
+
+// New satisfies the config.provider interface.
+func newJSON() (context.Renderer, error) {
+	return &jsonWriter{}, nil
+}
+
+type jsonWriter struct {
+}
+
+func (jw *jsonWriter) Name() string {
+	return "JSON"
+}
+
+func (jw *jsonWriter) Icon() string {
+	return "mdi-code-json"
+}
+
+func (jw *jsonWriter) Error(r *context.Response, code int, err error) error {
+	r.Writer().WriteHeader(code)
+	_, err = r.Writer().Write([]byte(err.Error()))
+	return err
+}
+
+// Write streams the data as json array.
+// Every row is marshaled and written separately, the array itself is not buffered.
+func (jw *jsonWriter) Write(r *context.Response) error {
+
+	// Filename
+	filename := "export"
+	if r.Value(FILENAME) != nil {
+		filename = r.Value(FILENAME).(string)
+	}
+
+	r.Writer().Header().Set("Content-Type", "application/json; charset=utf-8")
+	r.Writer().Header().Set("Content-Disposition", "attachment; filename=\""+filename+".json\"")
+
+	var header []Field
+	for _, h := range r.Value("head").([]Field) {
+		if h.Removed() {
+			continue
+		}
+		header = append(header, h)
+	}
+
+	flusher, _ := r.Writer().(http.Flusher)
+
+	if _, err := r.Writer().Write([]byte("[")); err != nil {
+		return err
+	}
+
+	rData := reflect.ValueOf(r.Value("data"))
+	for i := 0; rData.IsValid() && i < rData.Len(); i++ {
+		row := make(map[string]interface{}, len(header))
+		for _, head := range header {
+			// decorated relation fields.
+			if head.Option(options.DECORATOR) != nil {
+				row[head.name] = head.Decorate(rowValue(rData.Index(i), head.name))
+				continue
+			}
+			row[head.name] = rowValue(rData.Index(i), head.name)
+		}
+
+		b, err := json.Marshal(row)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			b = append([]byte(","), b...)
+		}
+		if _, err = r.Writer().Write(b); err != nil {
+			return err
+		}
+
+		if flusher != nil && (i+1)%jsonFlushRows == 0 {
+			flusher.Flush()
+		}
+	}
+
+	_, err := r.Writer().Write([]byte("]"))
+	return err
+}
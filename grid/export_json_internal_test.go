@@ -0,0 +1,72 @@
+// Copyright (c) 2021 Patrick Ascher <development@fullhouse-productions.com>. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package grid
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/patrickascher/gofer/controller/context"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestJsonWriter tests:
+// - If the data is streamed as valid json array.
+// - If removed fields are not exported.
+// - If screen-only fields are not exported and export-only fields are exported.
+// - If the response is flushed while writing.
+func TestJsonWriter(t *testing.T) {
+	asserts := assert.New(t)
+
+	w := httptest.NewRecorder()
+	ctx := context.New(w, httptest.NewRequest("GET", "https://example.com?mode=export&type="+JSON, nil))
+
+	id := Field{mode: FeExport}
+	id.SetName("ID")
+	name := Field{mode: FeExport}
+	name.SetName("Name")
+	secret := Field{mode: FeExport}
+	secret.SetName("Secret").SetRemove(true)
+	screen := Field{mode: FeExport}
+	screen.SetName("Screen").SetRemove(NewValue(false).SetExport(true))
+	export := Field{mode: FeExport}
+	export.SetName("Export").SetRemove(NewValue(true).SetExport(false))
+
+	// ok: screen-only field is shown in the table, export-only field not.
+	screen.mode = FeTable
+	export.mode = FeTable
+	asserts.False(screen.Removed())
+	asserts.True(export.Removed())
+	screen.mode = FeExport
+	export.mode = FeExport
+
+	var data []map[string]interface{}
+	for i := 0; i < 1000; i++ {
+		data = append(data, map[string]interface{}{"ID": i, "Name": "John", "Secret": "x", "Screen": "s", "Export": "e"})
+	}
+	ctx.Response.SetValue("head", []Field{id, name, secret, screen, export})
+	ctx.Response.SetValue("data", data)
+
+	err := ctx.Response.Render(JSON)
+	asserts.NoError(err)
+	asserts.True(w.Flushed)
+	asserts.Equal("application/json; charset=utf-8", w.Header().Get("Content-Type"))
+
+	var rv []map[string]interface{}
+	err = json.Unmarshal(w.Body.Bytes(), &rv)
+	asserts.NoError(err)
+	asserts.Equal(1000, len(rv))
+	asserts.Equal(map[string]interface{}{"ID": float64(999), "Name": "John", "Export": "e"}, rv[999])
+
+	// ok: empty result
+	w = httptest.NewRecorder()
+	ctx = context.New(w, httptest.NewRequest("GET", "https://example.com", nil))
+	ctx.Response.SetValue("head", []Field{id})
+	ctx.Response.SetValue("data", []map[string]interface{}{})
+	err = ctx.Response.Render(JSON)
+	asserts.NoError(err)
+	asserts.Equal("[]", w.Body.String())
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/patrickascher/gofer/controller/context"
+	"github.com/patrickascher/gofer/grid/options"
 	"github.com/xuri/excelize/v2"
 )
 
@@ -84,6 +85,15 @@ func (cw *newExcel) Write(r *context.Response) error {
 				return err
 			}
 
+			// decorated relation fields.
+			if head.Option(options.DECORATOR) != nil {
+				err = f.SetCellValue(sheetName, cell, head.Decorate(rowValue(rData.Index(i), head.name)))
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
 			if rData.Index(i).Type().Kind().String() == "struct" {
 				if rData.Index(i).FieldByName(head.name).Type().String() == "query.NullString" {
 					err = f.SetCellValue(sheetName, cell, fmt.Sprint(rData.Index(i).FieldByName(head.name).FieldByName("String").Interface()))
@@ -11,6 +11,7 @@ import (
 	"github.com/patrickascher/gofer/query/types"
 	"github.com/patrickascher/gofer/slicer"
 	"reflect"
+	"sort"
 	"sync"
 
 	"github.com/patrickascher/gofer/query"
@@ -20,6 +21,7 @@ import (
 var (
 	ErrOperator   = "grid: filter operator %s is not allowed in field %s"
 	ErrFieldValue = "grid: field value must be a %s, given %v"
+	ErrSubGrid    = "grid: sub grid is only allowed on relations (%s)"
 )
 
 var mutex = sync.RWMutex{}
@@ -49,12 +51,16 @@ type Field struct {
 	filterAble      bool
 	filterCondition string
 	filterField     string
+	filterHaving    bool          // filter field is a select expression alias, the condition is added as HAVING.
+	filterArgs      []interface{} // bound arguments of a custom filter, they are added before the filter value.
 
 	groupAble bool
+	sticky    bool
 
 	option map[string][]interface{}
 
 	relation bool
+	subGrid  bool
 	fields   []Field
 
 	error error
@@ -281,6 +287,17 @@ func (f *Field) SetGroupAble(allow bool) *Field {
 	return f
 }
 
+// Sticky identifier.
+func (f Field) Sticky() bool {
+	return f.sticky
+}
+
+// SetSticky defines the column as frozen/sticky in the frontend.
+func (f *Field) SetSticky(sticky bool) *Field {
+	f.sticky = sticky
+	return f
+}
+
 // Options of the field.
 func (f Field) Options() map[string][]interface{} {
 	return f.option
@@ -340,6 +357,23 @@ func (f *Field) SetRelation(r bool) *Field {
 	return f
 }
 
+// SubGrid identifier.
+func (f Field) SubGrid() bool {
+	return f.subGrid
+}
+
+// SetSubGrid defines that the relation is rendered as an editable sub grid in the frontend.
+// The relation fields are added as nested grid config to the head.
+// An error will be set if the field is no relation.
+func (f *Field) SetSubGrid(subGrid bool) *Field {
+	if subGrid && !f.relation {
+		f.error = fmt.Errorf(ErrSubGrid, f.name)
+		return f
+	}
+	f.subGrid = subGrid
+	return f
+}
+
 // Field will return the field by the given name.
 // If it was not found, an error will be set.
 func (f *Field) Field(name string) *Field {
@@ -411,13 +445,23 @@ func (f Field) MarshalJSON() ([]byte, error) {
 	if f.groupAble {
 		rv["groupable"] = f.groupAble
 	}
+	if f.sticky {
+		rv["sticky"] = f.sticky
+	}
 	if f.readOnly {
 		rv["readOnly"] = f.readOnly
 	}
 	if len(f.option) > 0 {
 		rv["options"] = f.option
 	}
-	if len(f.fields) > 0 {
+	if f.subGrid {
+		fields := make([]Field, len(f.fields))
+		copy(fields, f.fields)
+		sort.SliceStable(fields, func(i, j int) bool {
+			return fields[i].Position() < fields[j].Position()
+		})
+		rv["subGrid"] = map[string]interface{}{"fields": fields}
+	} else if len(f.fields) > 0 {
 		rv["fields"] = f.fields
 	}
 
@@ -13,6 +13,7 @@ import (
 	"unsafe"
 
 	"github.com/patrickascher/gofer/grid"
+	"github.com/patrickascher/gofer/grid/options"
 	"github.com/patrickascher/gofer/query"
 	"github.com/stretchr/testify/assert"
 )
@@ -150,6 +151,10 @@ func TestField(t *testing.T) {
 	asserts.IsType(new(grid.Field), field.SetGroupAble(true))
 	asserts.Equal(true, field.GroupAble())
 
+	//Sticky
+	asserts.IsType(new(grid.Field), field.SetSticky(true))
+	asserts.Equal(true, field.Sticky())
+
 	//Options
 	asserts.IsType(new(grid.Field), field.SetOption("testing", true))
 	asserts.Equal(true, field.Option("testing")[0].(bool))
@@ -159,6 +164,11 @@ func TestField(t *testing.T) {
 	asserts.Equal(1, len(field.Options()))
 	asserts.Equal(true, field.Options()["testing"][0].(bool))
 
+	//SubGrid
+	asserts.IsType(new(grid.Field), field.SetSubGrid(true))
+	asserts.Equal(false, field.SubGrid())
+	asserts.Equal(fmt.Sprintf(grid.ErrSubGrid, "Test"), field.Error().Error())
+
 	//Relation
 	asserts.IsType(new(grid.Field), field.SetRelation(true))
 	asserts.Equal(true, field.Relation())
@@ -177,6 +187,63 @@ func TestField(t *testing.T) {
 	//MarshalJSON
 	j, err := json.Marshal(field)
 	asserts.NoError(err)
-	exp := "{\"description\":\"Desc-export\",\"fields\":[{\"name\":\"SubField\",\"position\":0,\"title\":\"\",\"type\":\"\"}],\"filterable\":true,\"groupable\":true,\"hidden\":true,\"name\":\"Test\",\"options\":{\"testing\":[true]},\"position\":10,\"primary\":true,\"readOnly\":true,\"remove\":true,\"sortable\":true,\"title\":\"Title-export\",\"type\":\"Integer\",\"view\":\"custom-export\"}"
+	exp := "{\"description\":\"Desc-export\",\"fields\":[{\"name\":\"SubField\",\"position\":0,\"title\":\"\",\"type\":\"\"}],\"filterable\":true,\"groupable\":true,\"hidden\":true,\"name\":\"Test\",\"options\":{\"testing\":[true]},\"position\":10,\"primary\":true,\"readOnly\":true,\"remove\":true,\"sortable\":true,\"sticky\":true,\"title\":\"Title-export\",\"type\":\"Integer\",\"view\":\"custom-export\"}"
 	asserts.Equal(exp, string(j[:]))
+
+	//MarshalJSON SubGrid
+	subField2 := grid.Field{}
+	subField2.SetName("SubField2")
+	subField.SetPosition(1)
+	for _, sf := range []*grid.Field{&subField, &subField2} {
+		rf := reflect.ValueOf(sf).Elem().FieldByName("mode")
+		reflect.NewAt(rf.Type(), unsafe.Pointer(rf.UnsafeAddr())).Elem().Set(reflect.ValueOf(grid.FeTable))
+	}
+	field.SetFields([]grid.Field{subField, subField2})
+	asserts.IsType(new(grid.Field), field.SetSubGrid(true))
+	asserts.Equal(true, field.SubGrid())
+	j, err = json.Marshal(field)
+	asserts.NoError(err)
+	var head map[string]interface{}
+	asserts.NoError(json.Unmarshal(j, &head))
+	asserts.Nil(head["fields"])
+	asserts.Equal(map[string]interface{}{"fields": []interface{}{
+		map[string]interface{}{"name": "SubField2", "position": float64(0), "title": "", "type": ""},
+		map[string]interface{}{"name": "SubField", "position": float64(1), "title": "", "type": ""},
+	}}, head["subGrid"])
+}
+
+// TestField_Decorate tests:
+// - if multiple and nested fields are rendered.
+// - if missing fields are rendered empty.
+// - if slices are joined by the separator.
+func TestField_Decorate(t *testing.T) {
+	asserts := assert.New(t)
+
+	type Role struct {
+		Name string
+	}
+	type User struct {
+		FirstName string
+		LastName  query.NullString
+		Role      *Role
+	}
+
+	field := grid.Field{}
+
+	// ok: no decorator defined.
+	asserts.Equal("value", field.Decorate("value"))
+
+	// ok: multiple and nested fields.
+	field.SetOption(options.DECORATOR, "{{FirstName}} {{LastName}} ({{Role.Name}})")
+	asserts.Equal("John Doe (Admin)", field.Decorate(User{FirstName: "John", LastName: query.NewNullString("Doe", true), Role: &Role{Name: "Admin"}}))
+	asserts.Equal("John Doe (Admin)", field.Decorate(map[string]interface{}{"FirstName": "John", "LastName": "Doe", "Role": map[string]interface{}{"Name": "Admin"}}))
+
+	// ok: missing fields are empty.
+	asserts.Equal("John  ()", field.Decorate(&User{FirstName: "John"}))
+	field.SetOption(options.DECORATOR, "{{FirstName}} {{Unknown.Name}}")
+	asserts.Equal("John ", field.Decorate(User{FirstName: "John"}))
+
+	// ok: slice with separator.
+	field.SetOption(options.DECORATOR, "{{Name}}", "<br/>")
+	asserts.Equal("Admin<br/>User", field.Decorate([]Role{{Name: "Admin"}, {Name: "User"}}))
 }
@@ -26,6 +26,17 @@ import (
 // availableRenderer will store all defined render types.
 var availableRenderer map[string]context.Renderer
 
+// cacheKeyFunc can be used to customize the grid cache key.
+var cacheKeyFunc func(ctrl string, action string, ctx *context.Context) string
+
+// SetCacheKeyFunc can be used to customize the cache key of the grid.
+// This can be useful if the grid fields vary by role or locale.
+// By default the grid config ID (controller.action) is used.
+// If fn is nil, the default key will be used again.
+func SetCacheKeyFunc(fn func(ctrl string, action string, ctx *context.Context) string) {
+	cacheKeyFunc = fn
+}
+
 // prefix for the cache.
 const prefixCache = "grid_"
 
@@ -42,10 +53,15 @@ const (
 	paramTypeCallback = "callback"
 	paramModeCreate   = "create"
 	paramModeUpdate   = "update"
+	paramModePatch    = "patch"
 	paramModeDetails  = "details"
 	paramModeExport   = "export"
+	paramModeConfig   = "config"
 	paramExportType   = "type"
 	paramOnlyData     = "onlyData" // value can be 1 (only load data) or 2 (load data and pagination)
+	// select callback
+	paramSelectSearch = "q"
+	paramSelectLimit  = "limit"
 	// pagination
 	paginationLimit = "limit"
 	paginationPage  = "page"
@@ -55,11 +71,13 @@ const (
 	ctrlData       = "data"
 	ctrlPrimary    = "id"
 	ctrlConfig     = "config"
+	ctrlGrid       = "grid"
 )
 
 // Pre-defined exports
 const (
-	CSV = "gridCsv"
+	CSV  = "gridCsv"
+	JSON = "gridJson"
 )
 
 // // backend operations
@@ -82,6 +100,7 @@ const (
 	FeExport
 	FeFilter
 	FeHistory
+	FeConfig
 )
 
 // Error messages.
@@ -110,6 +129,8 @@ type Scope interface {
 	PrimaryFields() []Field
 	Controller() controller.Interface
 	SetCondition(condition.Condition)
+	SetFieldsPostProcessor(func([]Field) []Field) error
+	ResolveCondition() (condition.Condition, error)
 }
 
 // Source interface.
@@ -138,6 +159,9 @@ type grid struct {
 	controller   controller.Interface
 	fields       []Field
 
+	cacheKey        string
+	fieldsProcessed bool // the fields post processor was already called.
+
 	config Config
 }
 
@@ -175,8 +199,14 @@ func New(ctrl controller.Interface, src Source, conf ...Config) (Grid, error) {
 		return nil, fmt.Errorf(ErrCache, cfg.ID)
 	}
 
+	// cache key
+	cacheKey := cfg.ID
+	if cacheKeyFunc != nil {
+		cacheKey = cacheKeyFunc(ctrl.Name(), ctrl.Action(), ctrl.Context())
+	}
+
 	var g grid
-	if item, err := cacheMgr.Get(prefixCache, cfg.ID); err == nil {
+	if item, err := cacheMgr.Get(prefixCache, cacheKey); err == nil {
 		g = item.Value().(grid)
 		// set source and init it.
 		g.controller = ctrl
@@ -187,7 +217,7 @@ func New(ctrl controller.Interface, src Source, conf ...Config) (Grid, error) {
 		}
 	} else {
 		// create new grid
-		g = grid{controller: ctrl, src: src, config: cfg}
+		g = grid{controller: ctrl, src: src, config: cfg, cacheKey: cacheKey}
 		err := g.src.Init(&g)
 		if err != nil {
 			return nil, fmt.Errorf(errWrap, err)
@@ -206,7 +236,7 @@ func New(ctrl controller.Interface, src Source, conf ...Config) (Grid, error) {
 		}
 
 		// set cache
-		err = cacheMgr.Set(prefixCache, cfg.ID, g, cache.NoExpiration)
+		err = cacheMgr.Set(prefixCache, cacheKey, g, cache.NoExpiration)
 		if err != nil {
 			return nil, fmt.Errorf(errWrap, err)
 		}
@@ -230,9 +260,11 @@ func New(ctrl controller.Interface, src Source, conf ...Config) (Grid, error) {
 //   - mode create = FeCreate
 //   - mode details = FeDetails
 //   - mode update = FeUpdate
+//   - mode config = FeConfig
 //
 // HTTP.POST: 	SrcCreate
 // HTTP.PUT: 	SrcUpdate
+// HTTP.PATCH: 	SrcUpdate (partial, see isPatch)
 // HTTP.DELETE: SrcDelete
 //
 // Otherwise 0 will return.
@@ -268,6 +300,8 @@ func (g *grid) Mode() int {
 				return FeDetails
 			case paramModeExport:
 				return FeExport
+			case paramModeConfig:
+				return FeConfig
 			}
 		}
 	case http.MethodPost:
@@ -278,7 +312,7 @@ func (g *grid) Mode() int {
 			}
 		}
 		return SrcCreate
-	case http.MethodPut:
+	case http.MethodPut, http.MethodPatch:
 		if m != nil {
 			switch m[0] {
 			case paramModeFilter:
@@ -298,6 +332,17 @@ func (g *grid) Mode() int {
 	return 0
 }
 
+// isPatch returns true if the update request is partial.
+// This is the case for HTTP.PATCH or the mode param patch.
+func isPatch(g Grid) bool {
+	req := g.Scope().Controller().Context().Request
+	if req.Method() == http.MethodPatch {
+		return true
+	}
+	m, err := req.Param(paramModeKey)
+	return err == nil && m[0] == paramModePatch
+}
+
 // Field will return the field by name.
 // Error will be set if the field does not exist.
 // This is used to avoid annoying error handling on defining fields.
@@ -360,6 +405,9 @@ func (g *grid) Scope() Scope {
 // FeHistory
 //   - add all histories to the given primary key and grid id(s).
 //   - get all linked users.
+//
+// FeConfig
+//   - add header and config data. No data is fetched.
 func (g *grid) Render() {
 
 	// update the user config in the source
@@ -447,12 +495,11 @@ func (g *grid) Render() {
 			}
 		}
 
-		// set translated titles to an export
+		// set translated titles.
+		g.translateTitles(g.fields)
 		if g.Mode() == FeExport {
-			for i, f := range g.fields {
-				g.fields[i].title.export = g.controller.T(fmt.Sprint(f.title.export))
-			}
 			g.controller.Set("ctrl", g.Controller())
+			g.controller.Set(ctrlGrid, g)
 		}
 
 		// add header as long as the param noHeader is not given.
@@ -480,7 +527,8 @@ func (g *grid) Render() {
 			return
 		}
 		g.controller.Set(ctrlData, values)
-	case FeCreate:
+	case FeCreate, FeConfig:
+		g.translateTitles(g.fields)
 		g.controller.Set(ctrlConfig, g.config)
 		g.controller.Set(ctrlHead, g.sortFields())
 	case FeDetails, FeUpdate:
@@ -495,6 +543,7 @@ func (g *grid) Render() {
 			g.controller.Error(500, fmt.Errorf(errWrap, err))
 			return
 		}
+		g.translateTitles(g.fields)
 		g.controller.Set(ctrlConfig, g.config)
 		g.controller.Set(ctrlHead, g.sortFields())
 		g.controller.Set(ctrlData, values)
@@ -502,6 +551,7 @@ func (g *grid) Render() {
 		m, errParam := g.controller.Context().Request.Param("id")
 		// get filter headers (GET METHOD without ID)
 		if g.controller.Context().Request.IsGet() && errParam != nil {
+			g.translateTitles(g.fields)
 			g.controller.Set("head", g.sortFields())
 			return
 		}
@@ -636,6 +686,27 @@ func copySlice(fields []Field) []Field {
 	return rv
 }
 
+// translateTitles will translate the field titles recursively by the request locale.
+// The title is used as translation key. If no locale is set or the translation does not exist, the key is kept.
+func (g *grid) translateTitles(fields []Field) {
+	l := g.controller.Context().Request.Locale()
+	if l == nil {
+		return
+	}
+
+	for k := range fields {
+		if title := fields[k].Title(); title != "" {
+			// there can happen an error on translation, but it will still return the best effort.
+			if v, err := l.Translate(title); err == nil || v != "" {
+				fields[k].title.set(v)
+			}
+		}
+		if len(fields[k].fields) > 0 {
+			g.translateTitles(fields[k].fields)
+		}
+	}
+}
+
 // setFieldModeRecursively will set the grid mode recursively to all fields.
 // Additionally the field is set to remove by default if the policy is "WHITELIST".
 func (g *grid) setFieldModeRecursively(mode int, fields []Field) {
@@ -661,6 +732,16 @@ func (g *grid) checkExportTypes() error {
 	return nil
 }
 
+// exportAllowed checks if the export type is defined in the grid config.
+func (g *grid) exportAllowed(name string) bool {
+	for _, e := range g.config.Exports {
+		if string(e) == name {
+			return true
+		}
+	}
+	return false
+}
+
 // security is a helper to check the grid mode and the config definition to avoid un-allowed calls.
 func (g *grid) security() error {
 	switch g.Mode() {
@@ -670,7 +751,7 @@ func (g *grid) security() error {
 			return fmt.Errorf(ErrSecurity, "export")
 		}
 
-		if _, ok := availableRenderer[t[0]]; !ok {
+		if _, ok := availableRenderer[t[0]]; !ok || !g.exportAllowed(t[0]) {
 			return fmt.Errorf(ErrSecurity, "export-"+t[0])
 		}
 
@@ -0,0 +1,82 @@
+// Copyright (c) 2021 Patrick Ascher <development@fullhouse-productions.com>. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package grid
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/patrickascher/gofer/controller/context"
+	"github.com/patrickascher/gofer/controller/mocks"
+	"github.com/patrickascher/gofer/locale/translation"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+)
+
+// translationProvider is a in memory translation provider for testing.
+type translationProvider struct {
+	bundle *i18n.Bundle
+}
+
+func (tp *translationProvider) Bundle() (*i18n.Bundle, error) { return tp.bundle, nil }
+func (tp *translationProvider) Languages() ([]language.Tag, error) {
+	return tp.bundle.LanguageTags(), nil
+}
+func (tp *translationProvider) JSON(path string) error                 { return nil }
+func (tp *translationProvider) AddRawMessage([]i18n.Message) error     { return nil }
+func (tp *translationProvider) DefaultMessage(id string) *i18n.Message { return nil }
+func (tp *translationProvider) SetDefaultLanguage(language.Tag)        {}
+
+// TestGrid_translateTitles tests:
+// - if the titles are translated by the request locale (Accept-Language).
+// - if the key is kept if no translation exists.
+func TestGrid_translateTitles(t *testing.T) {
+	asserts := assert.New(t)
+
+	bundle := i18n.NewBundle(language.English)
+	bundle.MustAddMessages(language.English, &i18n.Message{ID: "ORM.User.Name", Other: "Name"})
+	bundle.MustAddMessages(language.German, &i18n.Message{ID: "ORM.User.Name", Other: "Vorname"})
+	err := translation.Register("gridTest", func(options interface{}) (translation.Provider, error) {
+		return &translationProvider{bundle: bundle}, nil
+	})
+	asserts.NoError(err)
+	_, err = translation.New("gridTest", nil, translation.Config{Controller: true, DefaultLanguage: "en"})
+	asserts.NoError(err)
+
+	context.DefaultLang = "en"
+	defer func() { context.DefaultLang = "" }()
+
+	var tests = []struct {
+		lang  string
+		title string
+	}{
+		{lang: "en", title: "Name"},
+		{lang: "de", title: "Vorname"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.lang, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "https://localhost/users", strings.NewReader(""))
+			req.Header.Set("Accept-Language", test.lang)
+			mockController := new(mocks.Interface)
+			mockController.On("Context").Return(context.New(httptest.NewRecorder(), req))
+			g := &grid{controller: mockController}
+
+			fields := []Field{{mode: FeTable}, {mode: FeTable}, {mode: FeTable, relation: true, fields: []Field{{mode: FeTable}}}}
+			fields[0].SetTitle("ORM.User.Name")
+			fields[1].SetTitle("ORM.User.NotExisting")
+			fields[2].fields[0].SetTitle("ORM.User.Name")
+
+			g.translateTitles(fields)
+			// ok: translated
+			asserts.Equal(test.title, fields[0].Title())
+			asserts.Equal(test.title, fields[2].fields[0].Title())
+			// ok: key is kept
+			asserts.Equal("ORM.User.NotExisting", fields[1].Title())
+		})
+	}
+}
@@ -5,6 +5,7 @@
 package grid_test
 
 import (
+	stdContext "context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -129,6 +130,53 @@ func TestNew(t *testing.T) {
 	mockItem.AssertExpectations(t)
 }
 
+// TestSetCacheKeyFunc tests:
+// - If the custom cache key is used for get and set.
+// - If the default key is used after resetting the func.
+func TestSetCacheKeyFunc(t *testing.T) {
+	asserts := assert.New(t)
+
+	mockCache := new(mocks.Manager)
+	mockController := new(controllerMock.Interface)
+	mockSource := new(gridMock.Source)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "https://localhost/users", strings.NewReader(""))
+	r = r.WithContext(stdContext.WithValue(r.Context(), "router_params", map[string][]string{}))
+	ctx := context.New(w, r)
+	grid.SetCacheKeyFunc(func(ctrl string, action string, ctx *context.Context) string {
+		return ctrl + "." + action + ".admin"
+	})
+	defer grid.SetCacheKeyFunc(nil)
+
+	// ok: custom cache key.
+	mockSource.On("Cache").Once().Return(mockCache, cache.NoExpiration)
+	mockController.On("Name").Return("TestCtrl")
+	mockController.On("Action").Return("TestAction")
+	mockController.On("Context").Return(ctx)
+	mockCache.On("Get", "grid_", "TestCtrl.TestAction.admin").Once().Return(nil, errors.New("does not exist"))
+	mockSource.On("Init", mock.AnythingOfType("*grid.grid")).Once().Return(nil)
+	mockSource.On("Fields", mock.AnythingOfType("*grid.grid")).Once().Return(nil, nil)
+	mockCache.On("Set", "grid_", "TestCtrl.TestAction.admin", mock.AnythingOfType("grid.grid"), time.Duration(cache.NoExpiration)).Once().Return(nil)
+	g, err := grid.New(mockController, mockSource)
+	asserts.NotNil(g)
+	asserts.NoError(err)
+
+	// ok: default cache key.
+	grid.SetCacheKeyFunc(nil)
+	mockSource.On("Cache").Once().Return(mockCache, cache.NoExpiration)
+	mockCache.On("Get", "grid_", "TestCtrl.TestAction").Once().Return(nil, errors.New("does not exist"))
+	mockSource.On("Init", mock.AnythingOfType("*grid.grid")).Once().Return(nil)
+	mockSource.On("Fields", mock.AnythingOfType("*grid.grid")).Once().Return(nil, nil)
+	mockCache.On("Set", "grid_", "TestCtrl.TestAction", mock.AnythingOfType("grid.grid"), time.Duration(cache.NoExpiration)).Once().Return(nil)
+	g, err = grid.New(mockController, mockSource)
+	asserts.NotNil(g)
+	asserts.NoError(err)
+
+	mockSource.AssertExpectations(t)
+	mockCache.AssertExpectations(t)
+}
+
 // TestGrid_Scope tests if the Scope interface will return.
 func TestGrid_Scope(t *testing.T) {
 	asserts := assert.New(t)
@@ -159,6 +207,63 @@ func TestGrid_Scope(t *testing.T) {
 	mockCache.AssertExpectations(t)
 }
 
+// TestGrid_SetFieldsPostProcessor tests:
+// - If the post processor manipulates the fields.
+// - If the result is cached and the post processor is not called again.
+func TestGrid_SetFieldsPostProcessor(t *testing.T) {
+	asserts := assert.New(t)
+
+	mem, err := cache.New("memory", nil)
+	asserts.NoError(err)
+	mockController := new(controllerMock.Interface)
+	mockSource := new(gridMock.Source)
+
+	fields := []grid.Field{{}, {}, {}}
+	fields[0].SetName("ID")
+	fields[1].SetName("Name")
+	fields[2].SetName("Secret")
+	removeSecret := func(fields []grid.Field) []grid.Field {
+		var rv []grid.Field
+		for _, f := range fields {
+			if f.Name() != "Secret" {
+				rv = append(rv, f)
+			}
+		}
+		return rv
+	}
+
+	mockSource.On("Cache").Return(mem)
+	mockController.On("Name").Return("TestCtrl")
+	mockController.On("Action").Return("PostProcessor")
+	r := httptest.NewRequest("GET", "https://localhost/users", strings.NewReader(""))
+	r = r.WithContext(stdContext.WithValue(r.Context(), "router_params", map[string][]string{}))
+	mockController.On("Context").Return(context.New(httptest.NewRecorder(), r))
+	mockSource.On("Init", mock.AnythingOfType("*grid.grid")).Return(nil)
+	mockSource.On("Fields", mock.AnythingOfType("*grid.grid")).Once().Return(fields, nil)
+
+	// ok: field is removed.
+	g, err := grid.New(mockController, mockSource)
+	asserts.NoError(err)
+	asserts.Equal(3, len(g.Scope().Fields()))
+	err = g.Scope().SetFieldsPostProcessor(removeSecret)
+	asserts.NoError(err)
+	asserts.Equal(2, len(g.Scope().Fields()))
+
+	// ok: cached head has no removed field and the post processor is not called again.
+	g, err = grid.New(mockController, mockSource)
+	asserts.NoError(err)
+	asserts.Equal(2, len(g.Scope().Fields()))
+	asserts.Equal("ID", g.Scope().Fields()[0].Name())
+	asserts.Equal("Name", g.Scope().Fields()[1].Name())
+	err = g.Scope().SetFieldsPostProcessor(func(fields []grid.Field) []grid.Field {
+		asserts.Fail("post processor was called again")
+		return fields
+	})
+	asserts.NoError(err)
+
+	mockSource.AssertExpectations(t)
+}
+
 // TestGrid_Mode tests the actual grid mode by http.Request.
 func TestGrid_Mode(t *testing.T) {
 	asserts := assert.New(t)
@@ -175,8 +280,10 @@ func TestGrid_Mode(t *testing.T) {
 		{name: "update", mode: grid.FeUpdate, req: httptest.NewRequest("GET", "https://localhost/users?mode=update", strings.NewReader(""))},
 		{name: "details", mode: grid.FeDetails, req: httptest.NewRequest("GET", "https://localhost/users?mode=details", strings.NewReader(""))},
 		{name: "export", mode: grid.FeExport, req: httptest.NewRequest("GET", "https://localhost/users?mode=export", strings.NewReader(""))},
+		{name: "config", mode: grid.FeConfig, req: httptest.NewRequest("GET", "https://localhost/users?mode=config", strings.NewReader(""))},
 		{name: "create src", mode: grid.SrcCreate, req: httptest.NewRequest("POST", "https://localhost/users", strings.NewReader(""))},
 		{name: "update src", mode: grid.SrcUpdate, req: httptest.NewRequest("PUT", "https://localhost/users", strings.NewReader(""))},
+		{name: "patch src", mode: grid.SrcUpdate, req: httptest.NewRequest("PATCH", "https://localhost/users", strings.NewReader(""))},
 		{name: "delete src", mode: grid.SrcDelete, req: httptest.NewRequest("DELETE", "https://localhost/users", strings.NewReader(""))},
 		{name: "does not exist", mode: 0, req: httptest.NewRequest("TRACE", "https://localhost/users", strings.NewReader(""))},
 	}
@@ -279,6 +386,8 @@ func TestGrid_Render(t *testing.T) {
 	testFeCreate(t)
 	// Fe details, update
 	testFeDetailsFeUpdate(t)
+	// Fe config
+	testFeConfig(t)
 
 	mockSource.AssertExpectations(t)
 	mockController.AssertExpectations(t)
@@ -368,6 +477,20 @@ func testFeCreate(t *testing.T) {
 	g.Render()
 }
 
+// testFeConfig tests if the head fields and config are added to the controller and no data is fetched.
+func testFeConfig(t *testing.T) {
+	g, mockController, mockSource, _, _ := mockGrid(t, httptest.NewRequest("GET", "https://localhost/users?mode=config", strings.NewReader("")))
+	mockSource.On("UpdatedFields", mock.AnythingOfType("*grid.grid")).Once().Return(nil)
+	mockController.On("Set", "head", mock.AnythingOfType("[]grid.Field")).Once()
+	mockController.On("Set", "config", mock.AnythingOfType("grid.Config")).Once()
+	g.Render()
+
+	mockSource.AssertNumberOfCalls(t, "Count", 0)
+	mockSource.AssertNumberOfCalls(t, "All", 0)
+	mockSource.AssertNumberOfCalls(t, "First", 0)
+	mockController.AssertExpectations(t)
+}
+
 // testFeCreate tests if the head fields are added to the controller.
 func testFeDetailsFeUpdate(t *testing.T) {
 
@@ -15,6 +15,8 @@ const (
 	DECORATOR = "decorator"
 	WIDTH     = "width"
 	VALIDATE  = "validate"
+	EXISTS    = "exists" // relation name, the field is set to true if the relation has entries.
+	COUNT     = "count"  // optional operator (default query.GTE), the relation field is filtered by the number of entries.
 )
 
 // Select will represent a frontend Select or MultiSelect.
@@ -26,6 +28,7 @@ type Select struct {
 	TextField   string              `json:",omitempty"`    // name of the text field
 	ValueField  string              `json:",omitempty"`    // name of the value field
 	Condition   condition.Condition `json:",omitempty"`    // additional conditions
+	Order       string              `json:"-"`             // order field(s), comma separated. A `-` prefix will set DESC.
 	OrmField    string              `json:"-"`             // Orm field
 	Multiple    bool                `json:",omitempty"`    // multiselect
 	ReturnValue bool                `json:",omitempty"`    // return object or value only.
@@ -16,6 +16,7 @@ import (
 	"os"
 	"path"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/patrickascher/gofer/cache"
@@ -29,13 +30,17 @@ import (
 
 // Error messages.
 var (
-	ErrCallback    = "requested callback %s is not implemented"
-	ErrRequestBody = "request body is empty in %s"
-	ErrJSONInvalid = "json is invalid in %s"
-	ErrConfig      = "no fields are configured"
-	ErrConfigSrc   = fmt.Errorf("config the source over grid.Scope().Source() after the grid instance was created")
+	ErrCallback     = "requested callback %s is not implemented"
+	ErrRequestBody  = "request body is empty in %s"
+	ErrJSONInvalid  = "json is invalid in %s"
+	ErrPatchPrimary = "primary keys are missing in the patch request of %s"
+	ErrConfig       = "no fields are configured"
+	ErrConfigSrc    = fmt.Errorf("config the source over grid.Scope().Source() after the grid instance was created")
 )
 
+// selectLimit is the default limit of the select callback.
+var selectLimit = 20
+
 type gridSource struct {
 	orm orm.Interface
 }
@@ -90,6 +95,11 @@ func (g *gridSource) Callback(cbk string, gr Grid) (interface{}, error) {
 	return nil, fmt.Errorf(ErrCallback, cbk)
 }
 
+// Capabilities returns the capabilities of the orm builder.
+func (g *gridSource) Capabilities() query.Capabilities {
+	return g.orm.DefaultBuilder().Capabilities()
+}
+
 func (g *gridSource) Cache() cache.Manager {
 	c, _ := g.orm.DefaultCache()
 	return c
@@ -117,11 +127,82 @@ func (g *gridSource) UpdatedFields(grid Grid) error {
 		} else {
 			return fmt.Errorf(ErrConfig)
 		}
+
+		// relation count filters.
+		for _, f := range grid.Scope().Fields() {
+			if opt := f.Option(options.COUNT); opt != nil {
+				operator := query.GTE
+				if len(opt) > 0 {
+					operator = fmt.Sprint(opt[0])
+				}
+				stmt, args, err := relationCount(g.orm, f.referenceName, operator)
+				if err != nil {
+					return err
+				}
+				grid.Field(f.Name()).SetFilter(true, query.CUSTOM, stmt).filterArgs = args
+			}
+		}
 	}
 
 	return nil
 }
 
+// relationCount is a helper to create a filter statement for the number of relation entries.
+// The entries are counted by a correlated sub query, this way parents without entries are included and the pagination
+// count is not affected. The polymorphic value is returned as bound argument.
+// HasOne, HasMany and ManyToMany relations are supported.
+// Error will return if the relation does not exist or the operator is not allowed.
+func relationCount(model orm.Interface, relationName string, operator string) (string, []interface{}, error) {
+	switch operator {
+	case query.EQ, query.NEQ, query.GT, query.GTE, query.LT, query.LTE:
+	default:
+		return "", nil, fmt.Errorf(ErrOperator, operator, relationName)
+	}
+
+	scope, err := model.Scope()
+	if err != nil {
+		return "", nil, err
+	}
+	relation, err := scope.SQLRelation(relationName, orm.Permission{})
+	if err != nil {
+		return "", nil, err
+	}
+	if relation.Kind == orm.BelongsTo {
+		return "", nil, fmt.Errorf(orm.ErrFieldName, scope.FqdnModel(relationName))
+	}
+
+	// relation table and column.
+	b := scope.Builder()
+	table := relation.Mapping.Join.Table
+	column := relation.Mapping.Join.ForeignColumnName
+	if relation.Kind != orm.ManyToMany {
+		rel, err := scope.InitRelationByField(relation.Field, false)
+		if err != nil {
+			return "", nil, err
+		}
+		relScope, err := rel.Scope()
+		if err != nil {
+			return "", nil, err
+		}
+		b = relScope.Builder()
+		table = relScope.FqdnTable()
+		column = relation.Mapping.References.Information.Name
+	}
+
+	// the relation table is aliased, because it can be the same table on self referencing models.
+	alias := "relation_count"
+	stmt := "(SELECT COUNT(*) FROM " + b.QuoteIdentifier(table) + " " + b.QuoteIdentifier(alias) +
+		" WHERE " + b.QuoteIdentifier(alias+"."+column) + " = " + scope.Builder().QuoteIdentifier(scope.FqdnTable()+"."+relation.Mapping.ForeignKey.Information.Name)
+	var args []interface{}
+	if relation.IsPolymorphic() {
+		stmt += " AND " + b.QuoteIdentifier(alias+"."+relation.Mapping.Polymorphic.TypeField.Information.Name) + " = ?"
+		args = append(args, relation.Mapping.Polymorphic.Value)
+	}
+	stmt += ") " + operator
+
+	return stmt, args, nil
+}
+
 // whitelistFields is a helper to set a whitelist of added fields.
 // Only fields will be displayed which are not removed by the grid.
 // If a readOnly is defined, the permission will be set to READ.
@@ -227,9 +308,115 @@ func (g *gridSource) All(c condition.Condition, grid Grid) (interface{}, error)
 	if err != nil {
 		return nil, err
 	}
+
+	// set the relation existence fields.
+	for _, f := range grid.Scope().Fields() {
+		if opt := f.Option(options.EXISTS); opt != nil && !f.Removed() {
+			err = relationExists(g.orm, reflect.Indirect(resultSlice), f.referenceName, fmt.Sprint(opt[0]))
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	return reflect.Indirect(resultSlice).Interface(), nil
 }
 
+// relationExists is a helper to set a boolean field if the relation has any entries.
+// One grouped query per relation is used for all rows of the result.
+// HasOne, HasMany and ManyToMany relations are supported.
+func relationExists(model orm.Interface, rows reflect.Value, field string, relationName string) error {
+	if rows.Len() == 0 {
+		return nil
+	}
+
+	scope, err := model.Scope()
+	if err != nil {
+		return err
+	}
+
+	var relation *orm.Relation
+	for _, r := range scope.Relations(orm.Permission{}) {
+		if r.Field == relationName {
+			r := r
+			relation = &r
+			break
+		}
+	}
+	if relation == nil || relation.Kind == orm.BelongsTo {
+		return fmt.Errorf(orm.ErrFieldName, scope.FqdnModel(relationName))
+	}
+
+	// collect the keys of the result.
+	var keys []interface{}
+	for i := 0; i < rows.Len(); i++ {
+		key, err := query.SanitizeInterfaceValue(reflect.Indirect(rows.Index(i)).FieldByName(relation.Mapping.ForeignKey.Name).Interface())
+		if err != nil {
+			return err
+		}
+		if _, exists := slicer.InterfaceExists(keys, key); !exists {
+			keys = append(keys, key)
+		}
+	}
+
+	// relation table and column.
+	rel, err := scope.InitRelationByField(relation.Field, false)
+	if err != nil {
+		return err
+	}
+	relScope, err := rel.Scope()
+	if err != nil {
+		return err
+	}
+	b := relScope.Builder()
+	table := relScope.FqdnTable()
+	column := relation.Mapping.References.Information.Name
+	if relation.Kind == orm.ManyToMany {
+		b = scope.Builder()
+		table = relation.Mapping.Join.Table
+		column = relation.Mapping.Join.ForeignColumnName
+	}
+
+	sel := b.Query().Select(table).Columns(column).Where(b.QuoteIdentifier(column)+" IN (?)", keys).Group(column)
+	if relation.IsPolymorphic() {
+		sel.Where(b.QuoteIdentifier(relation.Mapping.Polymorphic.TypeField.Information.Name)+" = ?", relation.Mapping.Polymorphic.Value)
+	}
+	res, err := sel.All()
+	if err != nil {
+		return err
+	}
+	defer res.Close()
+
+	existing := map[string]bool{}
+	for res.Next() {
+		var key interface{}
+		if err = res.Scan(&key); err != nil {
+			return err
+		}
+		if k, ok := key.([]byte); ok {
+			key = string(k)
+		}
+		existing[fmt.Sprint(key)] = true
+	}
+	if err = res.Err(); err != nil {
+		return err
+	}
+
+	for i := 0; i < rows.Len(); i++ {
+		row := reflect.Indirect(rows.Index(i))
+		key, err := query.SanitizeInterfaceValue(row.FieldByName(relation.Mapping.ForeignKey.Name).Interface())
+		if err != nil {
+			return err
+		}
+		err = orm.SetReflectValue(row.FieldByName(field), reflect.ValueOf(existing[fmt.Sprint(key)]))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // Create a new entry.
 // The primary key will be returned.
 func (g *gridSource) Create(grid Grid) (interface{}, error) {
@@ -261,7 +448,16 @@ func (g *gridSource) Create(grid Grid) (interface{}, error) {
 }
 
 // Update the entry
+// On a patch request, the stored entry is loaded first and only the keys of the request body are applied.
+// Omitted fields are untouched because the orm only writes the changed values.
 func (g *gridSource) Update(grid Grid) error {
+	if isPatch(grid) {
+		err := g.loadPatchEntry(grid)
+		if err != nil {
+			return err
+		}
+	}
+
 	err := g.unmarshalModel(grid)
 	if err != nil {
 		return err
@@ -295,6 +491,34 @@ func (g *gridSource) Count(c condition.Condition, grid Grid) (int, error) {
 	return g.orm.Count(c)
 }
 
+// loadPatchEntry is a helper to load the stored entry of a patch request.
+// The primary keys are taken from the request body.
+// Error will return if the primary keys are not set or the entry does not exist.
+func (g *gridSource) loadPatchEntry(grid Grid) error {
+	err := g.unmarshalModel(grid)
+	if err != nil {
+		return err
+	}
+
+	s, err := g.orm.Scope()
+	if err != nil {
+		return err
+	}
+	if !s.PrimaryKeysSet() {
+		return fmt.Errorf(ErrPatchPrimary, grid.Scope().Config().ID)
+	}
+	pKeys, err := s.PrimaryKeys()
+	if err != nil {
+		return err
+	}
+	c := condition.New()
+	for _, pkey := range pKeys {
+		c.SetWhere(s.Builder().QuoteIdentifier(pkey.Information.Name)+" = ?", s.FieldValue(pkey.Name).Interface())
+	}
+
+	return g.orm.First(c)
+}
+
 // unmarshalModel is needed for create and update an orm model.
 // It checks if the request json is correct.
 // Only struct fields are allowed.
@@ -410,7 +634,12 @@ func gridFields(scope orm.Scope, g Grid, parent string) ([]Field, error) {
 			continue
 		}
 		field.SetPrimary(f.Information.PrimaryKey)
-		field.SetType(f.Information.Type.Kind())
+		// custom fields have no db type.
+		var kind string
+		if f.Information.Type != nil {
+			kind = f.Information.Type.Kind()
+		}
+		field.SetType(kind)
 
 		field.SetTitle(NewValue(translation.ORM + scope.Name(true) + "." + f.Name))
 		// TODO translate desc
@@ -422,23 +651,29 @@ func gridFields(scope orm.Scope, g Grid, parent string) ([]Field, error) {
 		// field.SetView(g.NewValue(""))
 		field.SetSort(true, f.Information.Name)
 		field.SetFilter(true, query.LIKE, f.Information.Name)
-		if f.Information.Type.Kind() == types.DATE || f.Information.Type.Kind() == types.DATETIME {
+		if kind == types.DATE || kind == types.DATETIME {
 			field.SetFilter(true, query.MYSQLDATE, f.Information.Name)
 		}
+		// custom fields which are scanned from a select expression alias can not be used in WHERE.
+		if f.NoSQLColumn && f.ScanColumn != "" {
+			field.SetSort(true, f.ScanColumn)
+			field.SetFilter(true, query.LIKE, f.ScanColumn)
+			field.filterHaving = true
+		}
 		field.SetGroupAble(true)
 		// set validation tag
 		if f.Validator.Config() != "" {
 			field.SetOption(orm.TagValidate, f.Validator.Config())
 		}
 
-		if f.Information.Type.Kind() == types.SELECT || f.Information.Type.Kind() == types.MULTISELECT {
+		if kind == types.SELECT || kind == types.MULTISELECT {
 			var items []options.SelectItem
 			sel := f.Information.Type.(types.Items)
 			for _, i := range sel.Items() {
 				items = append(items, options.SelectItem{Text: translation.ORM + scope.Name(true) + "." + f.Name + "." + i, Value: i})
 			}
 			var multiple bool
-			if f.Information.Type.Kind() == types.MULTISELECT {
+			if kind == types.MULTISELECT {
 				multiple = true
 			}
 			field.SetOption(options.SELECT, options.Select{ReturnValue: true, TextField: "text", ValueField: "value", Items: items, Multiple: multiple})
@@ -467,6 +702,11 @@ func gridFields(scope orm.Scope, g Grid, parent string) ([]Field, error) {
 	// relation fields
 	for _, relation := range scope.Relations(orm.Permission{Read: true}) {
 
+		// skip relations which were not available on Init.
+		if relation.Unavailable != nil {
+			continue
+		}
+
 		// skip on self referencing orm model loops.
 		// skips everything > depth 1 because the root scope has no parent yet.
 		if _, err := scope.Parent(relation.Type.String()); err == nil {
@@ -752,7 +992,9 @@ func selectCallbackHistory(g Grid, selectField string, cond condition.Condition)
 	return strings.Join(rv, defaultSeparator)
 }
 
-// TODO docu
+// selectCallback returns the options of a select field.
+// If the request param q is set, the first text field will be searched with LIKE.
+// The result is limited by the request param limit, otherwise selectLimit is used.
 // cond is used for additional condition, needed in history.
 func selectCallback(g Grid, selectField string, cond ...condition.Condition) (interface{}, error) {
 
@@ -828,11 +1070,46 @@ func selectCallback(g Grid, selectField string, cond ...condition.Condition) (in
 		c = cond[0]
 	} else {
 		if sel.Condition != nil {
-			c = sel.Condition
+			c = sel.Condition.Copy()
 		} else {
 			c = condition.New()
 			c.SetOrder(textFields[0]) // default order, first text field asc
 		}
+
+		// defined order, the field names are mapped to the column names.
+		if sel.Order != "" {
+			var order []string
+			for _, o := range strings.Split(sel.Order, ",") {
+				o = strings.Trim(o, " ")
+				prefix := ""
+				if strings.HasPrefix(o, "-") {
+					prefix = "-"
+					o = o[1:]
+				}
+				if f, err := relScope.Field(o); err == nil {
+					o = f.Information.Name
+				}
+				order = append(order, prefix+o)
+			}
+			c.SetOrder(order...)
+		}
+
+		// type-ahead search
+		if q, err := g.Scope().Controller().Context().Request.Param(paramSelectSearch); err == nil && q[0] != "" {
+			column := textFields[0]
+			if f, err := relScope.Field(column); err == nil {
+				column = f.Information.Name
+			}
+			c.SetWhere(relScope.Builder().QuoteIdentifier(column)+" LIKE ?", "%"+escape(q[0])+"%")
+		}
+
+		limit := selectLimit
+		if l, err := g.Scope().Controller().Context().Request.Param(paramSelectLimit); err == nil {
+			if v, err := strconv.Atoi(l[0]); err == nil && v > 0 {
+				limit = v
+			}
+		}
+		c.SetLimit(limit)
 	}
 
 	err = relScope.Model().All(rRes.Interface(), c)
@@ -22,6 +22,7 @@ import (
 	"github.com/patrickascher/gofer/controller"
 	"github.com/patrickascher/gofer/controller/context"
 	"github.com/patrickascher/gofer/grid"
+	"github.com/patrickascher/gofer/grid/options"
 	"github.com/patrickascher/gofer/orm"
 	"github.com/patrickascher/gofer/query"
 	"github.com/patrickascher/gofer/query/condition"
@@ -81,6 +82,241 @@ func TestOrm_All(t *testing.T) {
 
 }
 
+// TestOrm_All_SavedFilter tests:
+// - if the sorting of a saved user filter is kept and not replaced by the DefaultSort.
+func TestOrm_All_SavedFilter(t *testing.T) {
+	asserts := assert.New(t)
+	helperCreateDatabaseAndTable(asserts)
+	insertUserData(asserts)
+	helperCreateUserGridTables(asserts)
+	ctrl := TestCtrl{}
+	ctrl.SetRenderType("json")
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "https://localhost/users?filter=1", strings.NewReader(""))
+	req = req.WithContext(context2.WithValue(req.Context(), jwt.CLAIM, &auth.Claim{UID: 1, Login: "John"}))
+	ctrl.SetContext(context.New(w, req))
+	g, err := grid.New(&ctrl, grid.Orm(&Role{}))
+	asserts.NoError(err)
+	g.Scope().Config().DefaultSort = "ID"
+	g.Field("Name").SetRemove(grid.NewValue(false))
+
+	// saved filter sorted by name desc.
+	_, err = builder.Query().Insert("tests." + orm.OrmFwPrefix + "user_grids").Values([]map[string]interface{}{{"id": 1, "grid_id": g.Scope().Config().ID, "user_id": 1, "name": "byName"}}).Exec()
+	asserts.NoError(err)
+	_, err = builder.Query().Insert("tests." + orm.OrmFwPrefix + "user_grid_sorts").Values([]map[string]interface{}{{"id": 1, "user_grid_id": 1, "key": "Name", "pos": 0, "desc": true}}).Exec()
+	asserts.NoError(err)
+
+	g.Render()
+	asserts.Equal("", w.Body.String())
+	data := ctrl.Context().Response.Value("data").([]Role)
+	asserts.Equal(5, len(data))
+	asserts.Equal("RoleC", data[0].Name)
+	asserts.Equal("Loop-1", data[4].Name)
+	asserts.Equal([]string{"Name DESC"}, ctrl.Context().Response.Value("config").(grid.Config).Filter.Active.Sort)
+}
+
+// TestOrm_All_Exists tests:
+// - if the relation existence field is set by one grouped query.
+func TestOrm_All_Exists(t *testing.T) {
+	asserts := assert.New(t)
+	helperCreateDatabaseAndTable(asserts)
+	insertUserData(asserts)
+	ctrl := TestCtrl{}
+	ctrl.SetRenderType("json")
+
+	w := httptest.NewRecorder()
+	ctrl.SetContext(context.New(w, httptest.NewRequest("GET", "https://localhost/users?filter_ID="+url.QueryEscape("1;2;3"), strings.NewReader(""))))
+	g, err := grid.New(&ctrl, grid.Orm(&RoleExists{}))
+	asserts.NoError(err)
+	g.Field("Name").SetRemove(grid.NewValue(false))
+	g.Field("HasRoles").SetRemove(grid.NewValue(false)).SetOption(options.EXISTS, "Roles")
+	g.Render()
+	asserts.Equal("", w.Body.String())
+	data := ctrl.Context().Response.Value("data").([]RoleExists)
+	asserts.Equal(3, len(data))
+	asserts.True(data[0].HasRoles)
+	asserts.True(data[1].HasRoles)
+	asserts.False(data[2].HasRoles)
+}
+
+// TestOrm_All_Count tests:
+// - if the grid is filtered by the number of relation entries.
+// - if parents without relation entries are included (LT).
+// - if the pagination total is counted correctly.
+func TestOrm_All_Count(t *testing.T) {
+	asserts := assert.New(t)
+	helperCreateDatabaseAndTable(asserts)
+	insertUserData(asserts)
+	_, err := builder.Query().Insert("tests.role_roles").Values([]map[string]interface{}{{"role_id": 1, "child_id": 3}}).Exec()
+	asserts.NoError(err)
+	ctrl := TestCtrl{}
+	ctrl.SetRenderType("json")
+
+	// ok - roles with at least 2 child roles.
+	w := httptest.NewRecorder()
+	ctrl.SetContext(context.New(w, httptest.NewRequest("GET", "https://localhost/users?filter_Roles=2", strings.NewReader(""))))
+	g, err := grid.New(&ctrl, grid.Orm(&Role{}))
+	asserts.NoError(err)
+	g.Field("Name").SetRemove(grid.NewValue(false))
+	g.Field("Roles").SetOption(options.COUNT)
+	g.Render()
+	asserts.Equal("", w.Body.String())
+	data := ctrl.Context().Response.Value("data").([]Role)
+	asserts.Equal(1, len(data))
+	asserts.Equal("RoleA", data[0].Name)
+	pagination, err := json.Marshal(ctrl.Context().Response.Value("pagination"))
+	asserts.NoError(err)
+	asserts.Equal("{\"Limit\":15,\"Prev\":0,\"Next\":0,\"CurrentPage\":1,\"Total\":1,\"TotalPages\":1}", string(pagination))
+
+	// ok - roles with exactly 1 child role.
+	w = httptest.NewRecorder()
+	ctrl.SetContext(context.New(w, httptest.NewRequest("GET", "https://localhost/users?filter_Roles=1", strings.NewReader(""))))
+	g, err = grid.New(&ctrl, grid.Orm(&Role{}))
+	asserts.NoError(err)
+	g.Field("Name").SetRemove(grid.NewValue(false))
+	g.Field("Roles").SetOption(options.COUNT, query.EQ)
+	g.Render()
+	asserts.Equal("", w.Body.String())
+	asserts.Equal(3, len(ctrl.Context().Response.Value("data").([]Role)))
+
+	// ok - roles without child roles are included.
+	w = httptest.NewRecorder()
+	ctrl.SetContext(context.New(w, httptest.NewRequest("GET", "https://localhost/users?filter_Roles=1", strings.NewReader(""))))
+	g, err = grid.New(&ctrl, grid.Orm(&Role{}))
+	asserts.NoError(err)
+	g.Field("Name").SetRemove(grid.NewValue(false))
+	g.Field("Roles").SetOption(options.COUNT, query.LT)
+	g.Render()
+	asserts.Equal("", w.Body.String())
+	data = ctrl.Context().Response.Value("data").([]Role)
+	asserts.Equal(1, len(data))
+	asserts.Equal("RoleC", data[0].Name)
+
+	// error - operator is not allowed.
+	w = httptest.NewRecorder()
+	ctrl.SetContext(context.New(w, httptest.NewRequest("GET", "https://localhost/users?filter_Roles=1", strings.NewReader(""))))
+	g, err = grid.New(&ctrl, grid.Orm(&Role{}))
+	asserts.NoError(err)
+	g.Field("Name").SetRemove(grid.NewValue(false))
+	g.Field("Roles").SetOption(options.COUNT, query.LIKE)
+	g.Render()
+	asserts.Equal(http.StatusInternalServerError, w.Code)
+}
+
+// TestOrm_All_Having tests:
+// - if the filter on a select expression alias is added as HAVING.
+// - if the pagination total is counted correctly.
+func TestOrm_All_Having(t *testing.T) {
+	asserts := assert.New(t)
+	helperCreateDatabaseAndTable(asserts)
+	insertUserData(asserts)
+	ctrl := TestCtrl{}
+	ctrl.SetRenderType("json")
+
+	// ok - roles with at least 1 child role.
+	w := httptest.NewRecorder()
+	ctrl.SetContext(context.New(w, httptest.NewRequest("GET", "https://localhost/users?filter_ChildCount=1", strings.NewReader(""))))
+	role := &RoleChildCount{}
+	g, err := grid.New(&ctrl, grid.Orm(role))
+	asserts.NoError(err)
+	scope, err := role.Scope()
+	asserts.NoError(err)
+	cfg := scope.Config()
+	scope.SetConfig(cfg.SetSelectExpression("(SELECT COUNT(*) FROM `tests`.`role_roles` WHERE `role_roles`.`role_id` = `roles`.`id`)", "child_count"))
+	g.Field("Name").SetRemove(grid.NewValue(false))
+	g.Field("ChildCount").SetRemove(grid.NewValue(false)).SetFilter(true, query.GTE, "child_count")
+	g.Render()
+	asserts.Equal("", w.Body.String())
+	data := ctrl.Context().Response.Value("data").([]RoleChildCount)
+	asserts.Equal(4, len(data))
+	for _, role := range data {
+		asserts.Equal(1, role.ChildCount)
+		asserts.NotEqual(3, role.ID)
+	}
+	pagination, err := json.Marshal(ctrl.Context().Response.Value("pagination"))
+	asserts.NoError(err)
+	asserts.Equal("{\"Limit\":15,\"Prev\":0,\"Next\":0,\"CurrentPage\":1,\"Total\":4,\"TotalPages\":1}", string(pagination))
+}
+
+// TestOrm_SelectCallback tests:
+// - if the select options are sorted by the defined order.
+func TestOrm_SelectCallback(t *testing.T) {
+	asserts := assert.New(t)
+	helperCreateDatabaseAndTable(asserts)
+	insertUserData(asserts)
+	ctrl := TestCtrl{}
+	ctrl.SetRenderType("json")
+
+	// ok - sorted by name desc.
+	w := httptest.NewRecorder()
+	ctrl.SetContext(context.New(w, httptest.NewRequest("GET", "https://localhost/users?mode=callback&callback=select&f=Roles", strings.NewReader(""))))
+	g, err := grid.New(&ctrl, grid.Orm(&Role{}))
+	asserts.NoError(err)
+	g.Field("Roles").SetOption(options.SELECT, options.Select{TextField: "Name", ValueField: "ID", Order: "-Name"})
+	g.Render()
+	asserts.Equal("", w.Body.String())
+	data := ctrl.Context().Response.Value("data").([]Role)
+	asserts.Equal(5, len(data))
+	var names []string
+	for _, r := range data {
+		names = append(names, r.Name)
+	}
+	asserts.Equal([]string{"RoleC", "RoleB", "RoleA", "Loop-2", "Loop-1"}, names)
+
+	// ok - the plain select is limited.
+	w = httptest.NewRecorder()
+	ctrl.SetContext(context.New(w, httptest.NewRequest("GET", "https://localhost/users?mode=callback&callback=select&f=Roles&limit=3", strings.NewReader(""))))
+	g, err = grid.New(&ctrl, grid.Orm(&Role{}))
+	asserts.NoError(err)
+	g.Field("Roles").SetOption(options.SELECT, options.Select{TextField: "Name", ValueField: "ID"})
+	g.Render()
+	asserts.Equal("", w.Body.String())
+	asserts.Equal(3, len(ctrl.Context().Response.Value("data").([]Role)))
+}
+
+// TestOrm_SelectCallback_Search tests:
+// - if only the matching options are returned.
+// - if the result is limited by the request param.
+func TestOrm_SelectCallback_Search(t *testing.T) {
+	asserts := assert.New(t)
+	helperCreateDatabaseAndTable(asserts)
+	insertUserData(asserts)
+	_, err := builder.Query().Insert("tests.roles").Values([]map[string]interface{}{{"id": 6, "name": "Eva"}, {"id": 7, "name": "Evan"}, {"id": 8, "name": "Steve"}, {"id": 9, "name": "Bob"}}).Exec()
+	asserts.NoError(err)
+	ctrl := TestCtrl{}
+	ctrl.SetRenderType("json")
+
+	// ok - all matching options.
+	w := httptest.NewRecorder()
+	ctrl.SetContext(context.New(w, httptest.NewRequest("GET", "https://localhost/users?mode=callback&callback=select&f=Roles&q=Ev", strings.NewReader(""))))
+	g, err := grid.New(&ctrl, grid.Orm(&Role{}))
+	asserts.NoError(err)
+	g.Field("Roles").SetOption(options.SELECT, options.Select{TextField: "Name", ValueField: "ID"})
+	g.Render()
+	asserts.Equal("", w.Body.String())
+	data := ctrl.Context().Response.Value("data").([]Role)
+	var names []string
+	for _, r := range data {
+		names = append(names, r.Name)
+	}
+	asserts.Equal([]string{"Eva", "Evan", "Steve"}, names)
+
+	// ok - matching options within the limit.
+	w = httptest.NewRecorder()
+	ctrl.SetContext(context.New(w, httptest.NewRequest("GET", "https://localhost/users?mode=callback&callback=select&f=Roles&q=Ev&limit=2", strings.NewReader(""))))
+	g, err = grid.New(&ctrl, grid.Orm(&Role{}))
+	asserts.NoError(err)
+	g.Field("Roles").SetOption(options.SELECT, options.Select{TextField: "Name", ValueField: "ID"})
+	g.Render()
+	asserts.Equal("", w.Body.String())
+	data = ctrl.Context().Response.Value("data").([]Role)
+	asserts.Equal(2, len(data))
+	for _, r := range data {
+		asserts.Contains(strings.ToLower(r.Name), "ev")
+	}
+}
+
 // TestOrm_First tests:
 // - fetch existing ID and check result.
 // - fetch a none existing ID.
@@ -203,6 +439,63 @@ func TestOrm_Update(t *testing.T) {
 	asserts.Equal("RoleA-updated", src.Name)
 }
 
+// TestOrm_Update_Patch tests:
+// - if only the fields of the request body are updated on HTTP.PATCH.
+// - if the mode patch is handled like HTTP.PATCH.
+// - error: primary key is missing in the request.
+func TestOrm_Update_Patch(t *testing.T) {
+	asserts := assert.New(t)
+	helperCreateDatabaseAndTable(asserts)
+	insertUserData(asserts)
+	_, err := builder.Query().Insert("tests.role_notes").Values([]map[string]interface{}{{"id": 1, "role_id": 1, "note": "first"}}).Exec()
+	asserts.NoError(err)
+	ctrl := TestCtrl{}
+	ctrl.SetRenderType("json")
+
+	// ok - only the note is updated.
+	w := httptest.NewRecorder()
+	ctrl.SetContext(context.New(w, httptest.NewRequest("PATCH", "https://localhost/notes", strings.NewReader("{\"ID\":1,\"Note\":\"changed\"}"))))
+	g, err := grid.New(&ctrl, grid.Orm(&RoleNote{}))
+	asserts.NoError(err)
+	g.Field("RoleID").SetRemove(grid.NewValue(false))
+	g.Field("Note").SetRemove(grid.NewValue(false))
+	g.Render()
+	asserts.Equal("", w.Body.String())
+	asserts.Equal(http.StatusOK, w.Code)
+	note := RoleNote{}
+	err = note.Init(&note)
+	asserts.NoError(err)
+	err = note.First(condition.New().SetWhere("id = ?", 1))
+	asserts.NoError(err)
+	asserts.Equal(1, note.RoleID)
+	asserts.Equal("changed", note.Note)
+
+	// ok - mode patch.
+	w = httptest.NewRecorder()
+	ctrl.SetContext(context.New(w, httptest.NewRequest("PUT", "https://localhost/notes?mode=patch", strings.NewReader("{\"ID\":1,\"RoleID\":2}"))))
+	g, err = grid.New(&ctrl, grid.Orm(&RoleNote{}))
+	asserts.NoError(err)
+	g.Field("RoleID").SetRemove(grid.NewValue(false))
+	g.Field("Note").SetRemove(grid.NewValue(false))
+	g.Render()
+	asserts.Equal("", w.Body.String())
+	asserts.Equal(http.StatusOK, w.Code)
+	err = note.First(condition.New().SetWhere("id = ?", 1))
+	asserts.NoError(err)
+	asserts.Equal(2, note.RoleID)
+	asserts.Equal("changed", note.Note)
+
+	// error - primary key is missing.
+	w = httptest.NewRecorder()
+	ctrl.SetContext(context.New(w, httptest.NewRequest("PATCH", "https://localhost/notes", strings.NewReader("{\"Note\":\"changed\"}"))))
+	g, err = grid.New(&ctrl, grid.Orm(&RoleNote{}))
+	asserts.NoError(err)
+	g.Field("Note").SetRemove(grid.NewValue(false))
+	g.Render()
+	asserts.Equal(http.StatusInternalServerError, w.Code)
+	asserts.Contains(w.Body.String(), "primary keys are missing")
+}
+
 // TestOrm_Create tests:
 // - create a new entry.
 // - error: request field name does not exist.
@@ -248,6 +541,64 @@ func TestOrm_Create(t *testing.T) {
 
 }
 
+// TestOrm_SubGrid tests:
+// - if the head includes the nested child fields of the sub grid.
+// - if the posted child rows are persisted on update.
+func TestOrm_SubGrid(t *testing.T) {
+	asserts := assert.New(t)
+	helperCreateDatabaseAndTable(asserts)
+	insertUserData(asserts)
+	ctrl := TestCtrl{}
+	ctrl.SetRenderType("json")
+
+	// ok - head with nested child fields.
+	w := httptest.NewRecorder()
+	ctrl.SetContext(context.New(w, httptest.NewRequest("GET", "https://localhost/users?mode=create", strings.NewReader(""))))
+	g, err := grid.New(&ctrl, grid.Orm(&RoleWithNotes{}))
+	asserts.NoError(err)
+	g.Field("Name").SetRemove(grid.NewValue(false))
+	g.Field("Notes").SetSubGrid(true)
+	g.Render()
+	asserts.Equal("", w.Body.String())
+	head, err := json.Marshal(ctrl.Context().Response.Value("head"))
+	asserts.NoError(err)
+	var fields []map[string]interface{}
+	asserts.NoError(json.Unmarshal(head, &fields))
+	var subGrid map[string]interface{}
+	for _, f := range fields {
+		if f["name"] == "Notes" {
+			subGrid = f["subGrid"].(map[string]interface{})
+		}
+	}
+	asserts.NotNil(subGrid)
+	var names []interface{}
+	for _, f := range subGrid["fields"].([]interface{}) {
+		names = append(names, f.(map[string]interface{})["name"])
+	}
+	asserts.Contains(names, "Note")
+
+	// ok - child rows are persisted.
+	w = httptest.NewRecorder()
+	ctrl.SetContext(context.New(w, httptest.NewRequest("PUT", "https://localhost/users", strings.NewReader("{\"ID\":1,\"Name\":\"RoleA\",\"Notes\":[{\"Note\":\"first\"},{\"Note\":\"second\"}]}"))))
+	g, err = grid.New(&ctrl, grid.Orm(&RoleWithNotes{}))
+	asserts.NoError(err)
+	g.Field("Name").SetRemove(grid.NewValue(false))
+	g.Field("Notes").SetSubGrid(true)
+	g.Render()
+	asserts.Equal("", w.Body.String())
+	asserts.Equal(http.StatusOK, w.Code)
+
+	note := RoleNote{}
+	err = note.Init(&note)
+	asserts.NoError(err)
+	var notes []RoleNote
+	err = note.All(&notes, condition.New().SetWhere("role_id = ?", 1).SetOrder("id"))
+	asserts.NoError(err)
+	asserts.Equal(2, len(notes))
+	asserts.Equal("first", notes[0].Note)
+	asserts.Equal("second", notes[1].Note)
+}
+
 // ----------------------------------------------------------------------------------------------------------------------------------------
 type TestCtrl struct {
 	controller.Base
@@ -280,6 +631,11 @@ func helperCreateDatabaseAndTable(asserts *assert.Assertions) {
 	_, err = b.Query().DB().Exec("CREATE TABLE `tests`.`role_roles` (`role_id` int(11) unsigned NOT NULL, `child_id` int(11) unsigned NOT NULL) ENGINE=InnoDB DEFAULT CHARSET=utf8;")
 	asserts.NoError(err)
 
+	_, err = b.Query().DB().Exec("DROP TABLE IF EXISTS `tests`.`role_notes`")
+	asserts.NoError(err)
+	_, err = b.Query().DB().Exec("CREATE TABLE `tests`.`role_notes` (`id` int(11) unsigned NOT NULL AUTO_INCREMENT, `role_id` int(11) unsigned NOT NULL, `note` varchar(250) NOT NULL DEFAULT '', PRIMARY KEY (`id`)) ENGINE=InnoDB DEFAULT CHARSET=utf8;")
+	asserts.NoError(err)
+
 	// set default builder
 	builder, err = query.New("mysql", testConfig())
 	asserts.NoError(err)
@@ -292,6 +648,23 @@ func helperCreateDatabaseAndTable(asserts *assert.Assertions) {
 	asserts.NoError(err)
 }
 
+// helperCreateUserGridTables creates the tables of the saved user filters and adds the database to the server.
+func helperCreateUserGridTables(asserts *assert.Assertions) {
+	_, err := builder.Query().DB().Exec("CREATE TABLE `tests`.`" + orm.OrmFwPrefix + "user_grids` (`id` int(11) unsigned NOT NULL AUTO_INCREMENT, `grid_id` varchar(250) NOT NULL DEFAULT '', `user_id` int(11) unsigned NOT NULL, `name` varchar(250) NOT NULL DEFAULT '', `group_by` varchar(250) DEFAULT NULL, `default` tinyint(1) NOT NULL DEFAULT 0, `rows_per_page` int(11) DEFAULT NULL, PRIMARY KEY (`id`)) ENGINE=InnoDB DEFAULT CHARSET=utf8;")
+	asserts.NoError(err)
+	_, err = builder.Query().DB().Exec("CREATE TABLE `tests`.`" + orm.OrmFwPrefix + "user_grid_filters` (`id` int(11) unsigned NOT NULL AUTO_INCREMENT, `user_grid_id` int(11) unsigned NOT NULL, `key` varchar(250) NOT NULL DEFAULT '', `op` varchar(250) NOT NULL DEFAULT '', `value` varchar(250) DEFAULT NULL, PRIMARY KEY (`id`)) ENGINE=InnoDB DEFAULT CHARSET=utf8;")
+	asserts.NoError(err)
+	_, err = builder.Query().DB().Exec("CREATE TABLE `tests`.`" + orm.OrmFwPrefix + "user_grid_sorts` (`id` int(11) unsigned NOT NULL AUTO_INCREMENT, `user_grid_id` int(11) unsigned NOT NULL, `key` varchar(250) NOT NULL DEFAULT '', `pos` int(11) DEFAULT NULL, `desc` tinyint(1) NOT NULL DEFAULT 0, PRIMARY KEY (`id`)) ENGINE=InnoDB DEFAULT CHARSET=utf8;")
+	asserts.NoError(err)
+	_, err = builder.Query().DB().Exec("CREATE TABLE `tests`.`" + orm.OrmFwPrefix + "user_grid_fields` (`id` int(11) unsigned NOT NULL AUTO_INCREMENT, `user_grid_id` int(11) unsigned NOT NULL, `key` varchar(250) NOT NULL DEFAULT '', `pos` int(11) NOT NULL DEFAULT 0, PRIMARY KEY (`id`)) ENGINE=InnoDB DEFAULT CHARSET=utf8;")
+	asserts.NoError(err)
+
+	cfg := testConfig()
+	cfg.Provider = "mysql"
+	err = server.New(server.Configuration{Databases: []query.Config{cfg}, Caches: []server.ConfigurationCache{{Provider: "memory", GCInterval: 360}}})
+	asserts.NoError(err)
+}
+
 func insertUserData(asserts *assert.Assertions) {
 
 	values := []map[string]interface{}{
@@ -322,6 +695,73 @@ type Role struct {
 	Roles []Role
 }
 
+type RoleExists struct {
+	orm.Model
+	ID       int
+	Name     string
+	HasRoles bool `orm:"custom"`
+
+	Roles []RoleExists `orm:"join_table:role_roles;join_fk:role_id;join_refs:child_id"`
+}
+
+func (r RoleExists) DefaultTableName() string {
+	return "roles"
+}
+func (r RoleExists) DefaultCache() (cache.Manager, time.Duration) {
+	return c, cache.DefaultExpiration
+}
+func (r RoleExists) DefaultBuilder() query.Builder {
+	return builder
+}
+
+type RoleChildCount struct {
+	orm.Model
+	ID         int
+	Name       string
+	ChildCount int `orm:"custom;scan:child_count"`
+}
+
+func (r RoleChildCount) DefaultTableName() string {
+	return "roles"
+}
+func (r RoleChildCount) DefaultCache() (cache.Manager, time.Duration) {
+	return c, cache.DefaultExpiration
+}
+func (r RoleChildCount) DefaultBuilder() query.Builder {
+	return builder
+}
+
+type RoleWithNotes struct {
+	orm.Model
+	ID    int
+	Name  string
+	Notes []RoleNote `orm:"refs:RoleID"`
+}
+
+func (r RoleWithNotes) DefaultTableName() string {
+	return "roles"
+}
+func (r RoleWithNotes) DefaultCache() (cache.Manager, time.Duration) {
+	return c, cache.DefaultExpiration
+}
+func (r RoleWithNotes) DefaultBuilder() query.Builder {
+	return builder
+}
+
+type RoleNote struct {
+	orm.Model
+	ID     int
+	RoleID int
+	Note   string
+}
+
+func (r RoleNote) DefaultCache() (cache.Manager, time.Duration) {
+	return c, cache.DefaultExpiration
+}
+func (r RoleNote) DefaultBuilder() query.Builder {
+	return builder
+}
+
 func (r Role) DefaultCache() (cache.Manager, time.Duration) {
 	return c, cache.DefaultExpiration
 }
@@ -5,6 +5,7 @@
 package grid
 
 import (
+	"github.com/patrickascher/gofer/cache"
 	"github.com/patrickascher/gofer/controller"
 	"github.com/patrickascher/gofer/query/condition"
 )
@@ -19,6 +20,30 @@ func (g *grid) SetCondition(c condition.Condition) {
 	g.srcCondition = c
 }
 
+// ResolveCondition returns the condition of the table view for the actual request, without executing it.
+// It includes the source condition, the user filter, filter and sort params. Pagination is not added.
+// Error will return if a filter or sort param is not allowed.
+func (g *grid) ResolveCondition() (condition.Condition, error) {
+	return g.conditionAll()
+}
+
+// SetFieldsPostProcessor can be used to manipulate the generated fields (hide, retitle, reorder,...).
+// The function is only called once, the result is cached and the function will not be called again on the next requests.
+// Error will return if the cache could not be set.
+func (g *grid) SetFieldsPostProcessor(fn func([]Field) []Field) error {
+	if g.fieldsProcessed {
+		return nil
+	}
+
+	g.fields = fn(g.fields)
+	g.fieldsProcessed = true
+
+	// update the cache.
+	cached := *g
+	cached.fields = copySlice(g.fields)
+	return g.src.Cache().Set(prefixCache, g.cacheKey, cached, cache.NoExpiration)
+}
+
 // Config will return a ptr to the configuration.
 func (g *grid) Config() *Config {
 	return &g.config
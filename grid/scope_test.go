@@ -5,9 +5,12 @@
 package grid_test
 
 import (
+	stdContext "context"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -16,6 +19,8 @@ import (
 	"github.com/patrickascher/gofer/controller/mocks"
 	"github.com/patrickascher/gofer/grid"
 	mocks2 "github.com/patrickascher/gofer/grid/mocks"
+	"github.com/patrickascher/gofer/query"
+	"github.com/patrickascher/gofer/query/condition"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -63,6 +68,50 @@ func TestGrid_Source(t *testing.T) {
 	asserts.Equal(src, g.Scope().Source())
 }
 
+// TestGrid_ResolveCondition tests:
+// - If the condition of the table/export render is returned.
+// - If an error returns on a not allowed filter.
+func TestGrid_ResolveCondition(t *testing.T) {
+	asserts := assert.New(t)
+
+	// ok
+	req := httptest.NewRequest("GET", "https://localhost/users?mode=export&type=gridCsv&sort=-ID&filter_ID=1", strings.NewReader(""))
+	req = req.WithContext(stdContext.WithValue(req.Context(), "router_params", map[string][]string{}))
+	g, mockController, mockSource, _, _ := mockGrid(t, req)
+	g.Scope().Fields()[0].SetSort(true, "id").SetFilter(true, query.EQ, "id")
+	g.Scope().SetCondition(condition.New().SetWhere("deleted_at IS NULL"))
+	c, err := g.Scope().ResolveCondition()
+	asserts.NoError(err)
+	stmt, args, err := c.Render(condition.Placeholder{Char: "?"})
+	asserts.NoError(err)
+	asserts.Equal("WHERE deleted_at IS NULL AND id = ? ORDER BY id DESC", stmt)
+	asserts.Equal([]interface{}{"1"}, args)
+
+	// ok: same condition is used on the render.
+	var rendered condition.Condition
+	mockSource.On("UpdatedFields", mock.AnythingOfType("*grid.grid")).Once().Return(nil)
+	mockController.On("Set", mock.Anything, mock.Anything)
+	mockController.On("SetRenderType", "gridCsv").Once()
+	mockSource.On("All", mock.AnythingOfType("*condition.condition"), mock.AnythingOfType("*grid.grid")).Run(func(args mock.Arguments) {
+		rendered = args.Get(0).(condition.Condition)
+	}).Return("srcdata", nil).Once()
+	g.Render()
+	asserts.NotNil(rendered)
+	renderedStmt, renderedArgs, err := rendered.Render(condition.Placeholder{Char: "?"})
+	asserts.NoError(err)
+	asserts.Equal(stmt, renderedStmt)
+	asserts.Equal(args, renderedArgs)
+
+	// error: filter is not allowed.
+	req = httptest.NewRequest("GET", "https://localhost/users?filter_NotExisting=1", strings.NewReader(""))
+	req = req.WithContext(stdContext.WithValue(req.Context(), "router_params", map[string][]string{}))
+	g, _, _, _, _ = mockGrid(t, req)
+	c, err = g.Scope().ResolveCondition()
+	asserts.Error(err)
+	asserts.Equal(fmt.Sprintf(grid.ErrFieldPermission, "NotExisting", "filter"), err.Error())
+	asserts.Nil(c)
+}
+
 // mockGrid will create a new grid out of mocks.
 func mockGrid(t *testing.T, req *http.Request, src ...grid.Source) (grid.Grid, *mocks.Interface, *mocks2.Source, *mocks3.Manager, []grid.Field) {
 	asserts := assert.New(t)
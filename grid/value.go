@@ -63,7 +63,7 @@ func (v *value) set(val interface{}) {
 func (v *value) get(mode int) interface{} {
 
 	switch mode {
-	case FeTable, FeFilter:
+	case FeTable, FeFilter, FeConfig:
 		return v.table
 	case FeDetails:
 		return v.details
@@ -4,7 +4,33 @@
 
 package orm
 
-import "github.com/patrickascher/gofer/query/condition"
+import (
+	"github.com/patrickascher/gofer/query"
+	"github.com/patrickascher/gofer/query/condition"
+)
+
+// Relation sync modes.
+const (
+	Replace = "replace" // omitted relation entries are deleted on update (default).
+	Merge   = "merge"   // only new entries are created and existing updated, omitted entries are kept.
+)
+
+// defaultConfig is set as root configuration of the orm models on Init.
+var defaultConfig = config{allowHasOneZero: true}
+
+// DefaultConfig returns a copy of the default configuration, which is set on Init of the orm models.
+func DefaultConfig() *config {
+	c := defaultConfig
+	return &c
+}
+
+// SetDefaultConfig sets the default configuration of all orm models.
+// It should be called before the models are initialized.
+//
+//	orm.SetDefaultConfig(orm.DefaultConfig().SetSkipMissingRelations(true))
+func SetDefaultConfig(c *config) {
+	defaultConfig = *c
+}
 
 // NewConfig will return a new empty configuration struct.
 func NewConfig() *config {
@@ -17,15 +43,41 @@ type config struct {
 	showDeletedRows      bool // if a soft delete is active, they will be displayed.
 	updateReferencesOnly bool // always the root struct will be taken.
 	permissionsExplicit  bool // always the root struct will be taken.
+	disableTimestamps    bool // always the root struct will be taken.
+	polymorphicAnyOwner  bool // polymorphic relations are loaded without the type condition.
+	reselectOnCreate     bool // the created row will be selected again if the provider does not support returning.
+	allowDeleteAll       bool // delete without primary keys will delete all rows.
+	uniqueTogether       [][]string
+	relationLoader       map[string]RelationLoader
+	relationLimit        map[string]int
+	snapshotFields       []string
+	snapshotUntracked    bool // untracked fields are written on update.
+	disableMetadataCache bool // the structure is parsed on every Init.
+	lockRequireTx        bool // row locks return an error outside of a transaction.
+	relationSync         map[string]string
+	relationConcurrency  int // max number of relations which are loaded in parallel.
 	relationCondition    relationCondition
+	indexHints           []query.IndexHint // added to the select of the model.
+	selectExpressions    []selectExpression
+	skipMissingRelations bool // relations which can not be initialized are skipped on Init.
 }
 
+// RelationLoader can be used to load relation data from an external source (microservice, cache,...).
+// All loaded parents are passed and the loader has to populate the relation field.
+type RelationLoader func(parents []Interface) error
+
 // relationCondition struct
 type relationCondition struct {
 	c     condition.Condition
 	reset bool // reset default condition
 }
 
+// selectExpression struct
+type selectExpression struct {
+	expr  string
+	alias string
+}
+
 // SetAllowHasOneZero if set to false, hasOne relations with an empty result will return an error.
 func (c *config) SetAllowHasOneZero(b bool) *config {
 	c.allowHasOneZero = b
@@ -50,6 +102,34 @@ func (c *config) SetUpdateReferenceOnly(b bool) *config {
 	return c
 }
 
+// SetManageTimestamps if set to false, the CreatedAt and UpdatedAt fields will not be set automatically on Create and Update.
+// The given values will be written as they are. By default the timestamps are managed.
+func (c *config) SetManageTimestamps(b bool) *config {
+	c.disableTimestamps = !b
+	return c
+}
+
+// SetPolymorphicAnyOwner if set, polymorphic relations will be loaded by the foreign key only, regardless of the owner type.
+// On create, the polymorphic type of the current model will still be written.
+func (c *config) SetPolymorphicAnyOwner(b bool) *config {
+	c.polymorphicAnyOwner = b
+	return c
+}
+
+// SetReselectOnCreate if set, the created entry will be selected again to fill db defaults, triggers or sequences.
+// It is only used if the provider does not support query.CapReturning, otherwise the row is returned by the insert.
+func (c *config) SetReselectOnCreate(b bool) *config {
+	c.reselectOnCreate = b
+	return c
+}
+
+// SetAllowDeleteAll if set, Delete without a set primary key will delete all rows of the table.
+// By default an ErrNoCondition will return. Relation entries are not deleted, use a sql CASCADE for it.
+func (c *config) SetAllowDeleteAll(b bool) *config {
+	c.allowDeleteAll = b
+	return c
+}
+
 // SetCondition will add or set a condition for a relation.
 // If merge is false, the default condition will be reset - be aware that the complete condition has to be set.
 func (c *config) SetCondition(condition condition.Condition, merge ...bool) *config {
@@ -67,3 +147,144 @@ func (c *config) SetCondition(condition condition.Condition, merge ...bool) *con
 func (c *config) Condition() (condition.Condition, bool) {
 	return c.relationCondition.c, c.relationCondition.reset
 }
+
+// SetUniqueTogether defines fields which must be unique together (composite unique key).
+// Before Create and Update the values are checked against the database and a query.ErrUniqueViolation will return.
+// It can be called multiple times to define more than one composite key.
+func (c *config) SetUniqueTogether(fields ...string) *config {
+	c.uniqueTogether = append(c.uniqueTogether, fields)
+	return c
+}
+
+// SetRelationLoader defines a custom loader for the given relation field.
+// On First and All the loader is called instead of the sql select for that relation.
+func (c *config) SetRelationLoader(relation string, loader RelationLoader) *config {
+	if c.relationLoader == nil {
+		c.relationLoader = make(map[string]RelationLoader)
+	}
+	c.relationLoader[relation] = loader
+	return c
+}
+
+// SetRelationLimit defines the max number of hasMany entries which are loaded per parent (top-N).
+// The order of the relation condition is used, by default the primary key.
+// If the provider supports window functions, one query is used, otherwise the relation is requested per parent.
+func (c *config) SetRelationLimit(relation string, n int) *config {
+	if c.relationLimit == nil {
+		c.relationLimit = make(map[string]int)
+	}
+	c.relationLimit[relation] = n
+	return c
+}
+
+// SetSnapshotFields limits the change tracking of the snapshot to the given fields.
+// The snapshot only selects the primary keys, the given fields and the foreign keys of the relations.
+// Untracked fields are ignored by the change detection and are never written on Update, this can be changed by SetSnapshotWriteUntracked.
+// Relations are not affected.
+func (c *config) SetSnapshotFields(fields ...string) *config {
+	c.snapshotFields = fields
+	return c
+}
+
+// SetSnapshotWriteUntracked if set, the untracked fields are always written if a tracked field has changed.
+func (c *config) SetSnapshotWriteUntracked(b bool) *config {
+	c.snapshotUntracked = b
+	return c
+}
+
+// SetMetadataCache if set to false, the model structure will be parsed from the database on the next Init instead of reading the cache.
+// The config must be set on the root model before Init is called again.
+func (c *config) SetMetadataCache(b bool) *config {
+	c.disableMetadataCache = !b
+	return c
+}
+
+// SetLockRequireTx if set, ForUpdate and ForShare will return an error outside of a transaction.
+// By default the lock is ignored.
+func (c *config) SetLockRequireTx(b bool) *config {
+	c.lockRequireTx = b
+	return c
+}
+
+// SetIndexHint adds an index hint to the select of the model.
+// The hint can be added to a relation by setting the relation configuration.
+//
+//	scope.SetConfig(orm.NewConfig().SetIndexHint("idx_name", query.HintForce), "Address")
+func (c *config) SetIndexHint(index string, kind query.HintKind) *config {
+	c.indexHints = append(c.indexHints, query.IndexHint{Index: index, Kind: kind})
+	return c
+}
+
+// SetSelectExpression adds an expression with an alias to the select of the model.
+// The value is scanned into the custom field with the matching scan tag.
+//
+//	ToyCount int `orm:"custom;scan:toy_count"`
+//	scope.SetConfig(orm.NewConfig().SetSelectExpression("(SELECT COUNT(*) FROM toys WHERE toys.animal_id = animals.id)", "toy_count"))
+func (c *config) SetSelectExpression(expr string, alias string) *config {
+	c.selectExpressions = append(c.selectExpressions, selectExpression{expr: expr, alias: alias})
+	return c
+}
+
+// SetSkipMissingRelations if set to true, relations which can not be initialized (example: the table does not exist yet)
+// are logged and skipped on Init instead of returning an error. The relation is marked as unavailable.
+// It must be set by SetDefaultConfig or before a re-Init of the model.
+func (c *config) SetSkipMissingRelations(b bool) *config {
+	c.skipMissingRelations = b
+	return c
+}
+
+// SetRelationConcurrency defines how many relations of the root model are loaded in parallel on First and All.
+// Each relation uses its own connection. Inside a transaction the relations are always loaded sequentially.
+func (c *config) SetRelationConcurrency(n int) *config {
+	c.relationConcurrency = n
+	return c
+}
+
+// SetRelationSync defines how hasMany and m2m relation entries are synchronized on update.
+// Replace (default) deletes omitted entries, Merge only creates new and updates existing entries.
+func (c *config) SetRelationSync(relation string, mode string) *config {
+	if c.relationSync == nil {
+		c.relationSync = make(map[string]string)
+	}
+	c.relationSync[relation] = mode
+	return c
+}
+
+// copy returns a deep copy of the configuration, slices and maps are not shared.
+func (c config) copy() config {
+	if c.uniqueTogether != nil {
+		unique := make([][]string, len(c.uniqueTogether))
+		for i, fields := range c.uniqueTogether {
+			unique[i] = append([]string(nil), fields...)
+		}
+		c.uniqueTogether = unique
+	}
+	if c.relationLoader != nil {
+		loader := make(map[string]RelationLoader, len(c.relationLoader))
+		for k, v := range c.relationLoader {
+			loader[k] = v
+		}
+		c.relationLoader = loader
+	}
+	if c.relationLimit != nil {
+		limit := make(map[string]int, len(c.relationLimit))
+		for k, v := range c.relationLimit {
+			limit[k] = v
+		}
+		c.relationLimit = limit
+	}
+	if c.relationSync != nil {
+		sync := make(map[string]string, len(c.relationSync))
+		for k, v := range c.relationSync {
+			sync[k] = v
+		}
+		c.relationSync = sync
+	}
+	if c.relationCondition.c != nil {
+		c.relationCondition.c = c.relationCondition.c.Copy()
+	}
+	c.snapshotFields = append([]string(nil), c.snapshotFields...)
+	c.indexHints = append([]query.IndexHint(nil), c.indexHints...)
+	c.selectExpressions = append([]selectExpression(nil), c.selectExpressions...)
+	return c
+}
@@ -5,6 +5,8 @@
 package orm
 
 import (
+	"crypto/sha1"
+	"encoding/hex"
 	"time"
 
 	"github.com/patrickascher/gofer/cache"
@@ -14,6 +16,11 @@ import (
 
 var OrmFwPrefix = "fw_"
 
+// IdentifierMaxLength is the maximum length of generated identifiers (junction table and column names).
+// Longer names are truncated to a readable prefix and a short hash, to stay stable and unique.
+// Names defined by tag are not changed. A value of 0 disables the truncation.
+var IdentifierMaxLength = 64
+
 // Defined struct time field names.
 const (
 	CreatedAt = "CreatedAt"
@@ -30,12 +37,49 @@ type TimeFields struct {
 	DeletedAt *query.NullTime `orm:"permission:w" json:",omitempty"`
 }
 
+// NamingStrategy can be implemented by the orm model to customize the generated names.
+// JoinTableName will be used for the default many to many junction table name.
+// If an empty string is returned, the default junction table name is kept.
+// A join_table tag will still overwrite it.
+type NamingStrategy interface {
+	JoinTableName(modelA, modelB string) string
+}
+
+// truncateIdentifier is a helper to truncate a generated identifier to the IdentifierMaxLength.
+// The name is cut and a suffix of the first 8 characters of the sha1 hash of the full name is added.
+func truncateIdentifier(name string) string {
+	if IdentifierMaxLength <= 0 || len(name) <= IdentifierMaxLength {
+		return name
+	}
+	h := sha1.Sum([]byte(name))
+	suffix := "_" + hex.EncodeToString(h[:])[:8]
+	if IdentifierMaxLength <= len(suffix) {
+		return suffix[1 : IdentifierMaxLength+1]
+	}
+	return name[:IdentifierMaxLength-len(suffix)] + suffix
+}
+
+// CacheExpirer can be implemented by the orm model to define the cache expiration of the model metadata.
+// It overwrites the duration of DefaultCache. cache.DefaultExpiration uses the manager default, a negative duration never expires.
+type CacheExpirer interface {
+	CacheExpiration() time.Duration
+}
+
+// PolymorphicValuer can be implemented by the orm model to define the polymorphic value per instance.
+// It is used instead of the default polymorphic value (model name or poly_value tag) when the polymorphic type column is written
+// and on loading the relations. If several owners are loaded at once, they are requested grouped by their polymorphic value.
+type PolymorphicValuer interface {
+	PolymorphicValue() string
+}
+
 // SoftDelete should return the field and value.
 // If the ActiveValues are nil, sql NULL will be searched as active value.
+// If a Predicate is set, it will be used as where clause for the active rows. The placeholder %s will be replaced by the quoted field (e.g. "%s = 0").
 type SoftDelete struct {
 	Field        string // the sql name
 	Value        interface{}
 	ActiveValues []interface{}
+	Predicate    string
 }
 
 // DefaultSoftDelete returns the default soft deleting.
@@ -0,0 +1,105 @@
+// Copyright (c) 2021 Patrick Ascher <development@fullhouse-productions.com>. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package orm
+
+import (
+	"time"
+)
+
+// OrmDiagnostics describes how an orm model is wired.
+// It is serializable and can be used for support tooling or admin endpoints.
+type OrmDiagnostics struct {
+	Name       string
+	Database   string
+	Table      string
+	Columns    []ColumnDiagnostics
+	Relations  []RelationDiagnostics
+	SoftDelete *SoftDelete `json:",omitempty"`
+	Cache      CacheDiagnostics
+}
+
+// ColumnDiagnostics describes a field of the orm model.
+type ColumnDiagnostics struct {
+	Field       string
+	Column      string
+	Type        string `json:",omitempty"`
+	PrimaryKey  bool
+	NullAble    bool
+	NoSQLColumn bool
+	ReadOnly    bool
+	Permission  Permission
+}
+
+// RelationDiagnostics describes a relation of the orm model and its mapping.
+type RelationDiagnostics struct {
+	Field            string
+	Kind             string
+	Type             string
+	ForeignKey       string
+	References       string
+	Polymorphic      string `json:",omitempty"` // the polymorphic type column.
+	PolymorphicValue string `json:",omitempty"`
+	JoinTable        string `json:",omitempty"`
+	JoinForeignKey   string `json:",omitempty"`
+	JoinReferences   string `json:",omitempty"`
+	NoSQLColumn      bool
+	Permission       Permission
+}
+
+// CacheDiagnostics describes the cache status of the orm model.
+type CacheDiagnostics struct {
+	Defined bool
+	Cached  bool
+	TTL     time.Duration
+}
+
+// Diagnostics returns the resolved table, columns, relations, soft delete and cache status of the orm model.
+func (s *scope) Diagnostics() OrmDiagnostics {
+	d := OrmDiagnostics{
+		Name:       s.Name(true),
+		Database:   s.model.db,
+		Table:      s.model.table,
+		SoftDelete: s.model.softDelete,
+	}
+
+	for _, field := range s.model.fields {
+		c := ColumnDiagnostics{
+			Field:       field.Name,
+			Column:      field.Information.Name,
+			PrimaryKey:  field.Information.PrimaryKey,
+			NullAble:    field.Information.NullAble,
+			NoSQLColumn: field.NoSQLColumn,
+			ReadOnly:    field.ReadOnly,
+			Permission:  field.Permission,
+		}
+		if field.Information.Type != nil {
+			c.Type = field.Information.Type.Kind()
+		}
+		d.Columns = append(d.Columns, c)
+	}
+
+	for _, relation := range s.model.relations {
+		d.Relations = append(d.Relations, RelationDiagnostics{
+			Field:            relation.Field,
+			Kind:             relation.Kind,
+			Type:             relation.Type.String(),
+			ForeignKey:       relation.Mapping.ForeignKey.Information.Name,
+			References:       relation.Mapping.References.Information.Name,
+			Polymorphic:      relation.Mapping.Polymorphic.TypeField.Information.Name,
+			PolymorphicValue: relation.Mapping.Polymorphic.Value,
+			JoinTable:        relation.Mapping.Join.Table,
+			JoinForeignKey:   relation.Mapping.Join.ForeignColumnName,
+			JoinReferences:   relation.Mapping.Join.ReferencesColumnName,
+			NoSQLColumn:      relation.NoSQLColumn,
+			Permission:       relation.Permission,
+		})
+	}
+
+	if s.model.cache != nil {
+		d.Cache = CacheDiagnostics{Defined: true, Cached: s.model.cache.Exist(prefixCache, s.Name(true)), TTL: s.model.cacheTTL}
+	}
+
+	return d
+}
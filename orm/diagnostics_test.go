@@ -0,0 +1,69 @@
+// Copyright (c) 2021 Patrick Ascher <development@fullhouse-productions.com>. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package orm_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/patrickascher/gofer/orm"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestScope_Diagnostics tests:
+// - if the table, columns and all relations with kind and mapping are returned.
+// - if the cache status is returned.
+// - if the result is serializable.
+func TestScope_Diagnostics(t *testing.T) {
+	asserts := assert.New(t)
+
+	helperCreateDatabaseAndTable(asserts)
+
+	animal := Animal{}
+	err := animal.Init(&animal)
+	asserts.NoError(err)
+	scope, err := animal.Scope()
+	asserts.NoError(err)
+
+	d := scope.Diagnostics()
+	asserts.Equal("orm_test.Animal", d.Name)
+	asserts.Equal("animals", d.Table)
+	asserts.True(d.Cache.Defined)
+	asserts.True(d.Cache.Cached)
+	asserts.NotNil(d.SoftDelete)
+
+	// columns
+	var pk []string
+	for _, c := range d.Columns {
+		if c.PrimaryKey {
+			pk = append(pk, c.Column)
+		}
+	}
+	asserts.Equal([]string{"id"}, pk)
+
+	// relations
+	asserts.Equal(24, len(d.Relations))
+	kinds := map[string]int{}
+	relations := map[string]orm.RelationDiagnostics{}
+	for _, r := range d.Relations {
+		kinds[r.Kind]++
+		relations[r.Field] = r
+	}
+	asserts.Equal(map[string]int{orm.BelongsTo: 4, orm.HasOne: 4, orm.HasMany: 8, orm.ManyToMany: 8}, kinds)
+
+	asserts.Equal("species_id", relations["Species"].ForeignKey)
+	asserts.Equal("id", relations["Species"].References)
+	asserts.Equal("id", relations["Toys"].ForeignKey)
+	asserts.Equal("animal_id", relations["Toys"].References)
+	asserts.Equal("toy_type", relations["ToyPoly"].Polymorphic)
+	asserts.Equal("Animal", relations["ToyPoly"].PolymorphicValue)
+	asserts.Equal("animal_walkers", relations["Walkers"].JoinTable)
+	asserts.Equal("animal_id", relations["Walkers"].JoinForeignKey)
+	asserts.Equal("human_id", relations["Walkers"].JoinReferences)
+
+	// serializable
+	_, err = json.Marshal(d)
+	asserts.NoError(err)
+}
@@ -8,11 +8,9 @@ import (
 	"fmt"
 	"github.com/patrickascher/gofer/query/types"
 	"reflect"
-	"strings"
 
 	"github.com/patrickascher/gofer/query"
 	"github.com/patrickascher/gofer/stringer"
-	"github.com/patrickascher/gofer/structer"
 )
 
 // Error messages.
@@ -34,6 +32,11 @@ const (
 	tagPermission = "permission"
 	tagSQLSelect  = "sql"
 	tagPrimary    = "primary"
+	tagUnique     = "unique"
+	tagOrder      = "order"
+	tagReadOnly   = "readonly"
+	tagSensitive  = "sensitive"
+	tagScan       = "scan"
 )
 
 // Field is holding the struct field information.
@@ -43,7 +46,11 @@ type Field struct {
 	Permission  Permission
 	Information query.Column
 	Validator   validator
-	NoSQLColumn bool // defines a none db column.
+	NoSQLColumn bool   // defines a none db column.
+	UniqueWhere string // additional predicate of a filtered unique index.
+	ReadOnly    bool   // defines a computed db column, which is never written.
+	Sensitive   bool   // defines a column which value is redacted in the query log.
+	ScanColumn  string // defines the selected column alias which is scanned into a custom field.
 }
 
 // Permission of the field.
@@ -53,6 +60,21 @@ type Permission struct {
 	Write bool
 }
 
+// copy returns a copy of the field with its own validator configuration.
+func (f Field) copy() Field {
+	f.Validator = f.Validator.copy()
+	return f
+}
+
+// sqlValue returns the field value of the scope as statement argument.
+// Sensitive fields are marked by query.Sensitive.
+func sqlValue(scope Scope, f Field) interface{} {
+	if f.Sensitive {
+		return query.Sensitive(scope.FieldValue(f.Name).Interface())
+	}
+	return scope.FieldValue(f.Name).Interface()
+}
+
 // createFields will create the orm model fields.
 // The Field.Name will be set.
 // The Field.Information.Name will be set as snake style - if not added manually by tag.
@@ -74,42 +96,41 @@ func (m *Model) createFields(structFields []reflect.StructField) error {
 		}
 
 		// parse tag and config the Field.
-		for k, v := range structer.ParseTag(structField.Tag.Get("orm")) {
-			switch k {
-			case tagNoSQLField:
-				f.NoSQLColumn = true
-			case tagPrimary:
-				f.Information.PrimaryKey = true
-			case tagColumn:
-				f.Information.Name = v
-				f.Permission.Read = true
-				f.Permission.Write = false
-				f.Information.Name = v
-				f.Information.Type = types.NewText("varchar(250)")
-			case tagPermission:
-				f.Permission.Read = false
-				f.Permission.Write = false
-				if strings.Contains(v, "r") {
-					f.Permission.Read = true
-				}
-				if strings.Contains(v, "w") {
-					f.Permission.Write = true
-				}
-			case tagSQLSelect:
-				if v[0:1] != "!" {
-					v = query.DbExpr(v)
-				}
-				f.SQLSelect = v
-				f.Permission.Read = true
-				f.Permission.Write = false
-				f.Information.Name = v
-				f.Information.Type = types.NewText("varchar(250)")
+		// the same rules as ParseFieldTags are used.
+		ft, err := parseFieldTags(structField, m.scope.FqdnModel)
+		if err != nil {
+			return err
+		}
+		f.NoSQLColumn = ft.Custom
+		f.Information.PrimaryKey = f.Information.PrimaryKey || ft.Primary
+		f.Information.Unique = ft.Unique
+		f.UniqueWhere = ft.UniqueWhere
+		f.ReadOnly = ft.ReadOnly
+		f.Sensitive = ft.Sensitive
+		f.ScanColumn = ft.Scan
+		if ft.Column != "" {
+			f.Information.Name = ft.Column
+			f.Permission.Read = true
+			f.Permission.Write = false
+			f.Information.Type = types.NewText("varchar(250)")
+		}
+		if v := ft.SQLSelect; v != "" {
+			if v[0:1] != "!" {
+				v = query.DbExpr(v)
 			}
+			f.SQLSelect = v
+			f.Permission.Read = true
+			f.Permission.Write = false
+			f.Information.Name = v
+			f.Information.Type = types.NewText("varchar(250)")
+		}
+		if ft.Permission != nil {
+			f.Permission = *ft.Permission
 		}
 
 		// validator
 		f.Validator = validator{}
-		f.Validator.SetConfig(structField.Tag.Get(TagValidate))
+		f.Validator.SetConfig(ft.Validate)
 
 		// add to model fields
 		m.fields = append(m.fields, f)
@@ -194,6 +215,8 @@ Columns:
 					}
 				}
 
+				// keep the unique tag information.
+				dbCol.Unique = dbCol.Unique || m.fields[i].Information.Unique
 				m.fields[i].Information = dbCol
 
 				//decrease dbCols
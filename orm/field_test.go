@@ -7,6 +7,7 @@ package orm_test
 import (
 	"errors"
 	"fmt"
+	"reflect"
 	"testing"
 	"time"
 
@@ -25,6 +26,7 @@ import (
 // - primary key exists as struct field ID
 // - primary key exists as primary tag field.
 // - all tags are checked if set.
+// - unique tag with a predicate.
 // - error: defined struct pk is no pk in the db.
 // - error: db null field but struct field has no null type.
 // - error: soft deleting field does not exist in struct.
@@ -66,7 +68,7 @@ func TestModel_createFields(t *testing.T) {
 				mBuilder.On("Query").Once().Return(mProvider)
 				mInformation := new(mockBuilder.Information)
 				mProvider.On("Information", "orm_field").Return(mInformation)
-				mInformation.On("Describe", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("an error"))
+				mInformation.On("Describe", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("an error"))
 			}
 
 			// if no error happens, the cache will be set.
@@ -91,6 +93,30 @@ func TestModel_createFields(t *testing.T) {
 					asserts.Equal("required", field.Validator.Config())
 					asserts.Equal(orm.Permission{Read: true, Write: true}, field.Permission)
 					asserts.Equal("id", field.Information.Name)
+
+					// unique field with predicate.
+					field, err = scope.Field("Name")
+					asserts.NoError(err)
+					asserts.True(field.Information.Unique)
+					asserts.Equal("deleted_at IS NULL", field.UniqueWhere)
+
+					// soft deleted rows are not colliding.
+					_, err = builder.Query().DB().Exec("INSERT INTO `orm_field` (`name`,`deleted_at`) VALUES ('John',NOW())")
+					asserts.NoError(err)
+					test.model.(*OrmIDTag).Name = query.NewNullString("John", true)
+					unique, err := scope.IsUnique("Name")
+					asserts.NoError(err)
+					asserts.True(unique)
+					_, err = builder.Query().DB().Exec("INSERT INTO `orm_field` (`name`) VALUES ('John')")
+					asserts.NoError(err)
+					unique, err = scope.IsUnique("Name")
+					asserts.NoError(err)
+					asserts.False(unique)
+
+					// error: field is not unique.
+					_, err = scope.IsUnique("Internal")
+					asserts.Error(err)
+					asserts.Equal(fmt.Sprintf(orm.ErrFieldNotUnique, "orm_test.OrmIDTag:Internal"), err.Error())
 				}
 			}
 		})
@@ -134,8 +160,9 @@ type OrmPKErr struct {
 // OrmIDTag - test with a manually set primary ID field.
 type OrmIDTag struct {
 	OrmFieldBase
-	IDTag    int `orm:"column:id;primary;sql:Count(*);permission:rw" validate:"required"`
-	Internal int `orm:"custom"`
+	IDTag    int              `orm:"column:id;primary;sql:Count(*);permission:rw" validate:"required"`
+	Name     query.NullString `orm:"unique:deleted_at IS NULL"`
+	Internal int              `orm:"custom"`
 }
 
 // OrmIDField test with automatic struct ID primary field.
@@ -182,3 +209,113 @@ func createTestTable(asserts *assert.Assertions) query.Builder {
 
 	return builder
 }
+
+// TestParseFieldTags tests:
+// - If a belongsTo relation with poly returns the structured tags.
+// - If the default relation kind is set.
+// - If the field tags are parsed.
+// - error: order tag is no integer.
+// - error: relation kind is not allowed on the field type.
+func TestParseFieldTags(t *testing.T) {
+	asserts := assert.New(t)
+
+	type tagModel struct {
+		Name   string `orm:"column:first_name;permission:r;unique:deleted_at IS NULL;order:2;sensitive" validate:"required"`
+		Skip   string `orm:"-"`
+		Order  string `orm:"order:x"`
+		Broken []Toy  `orm:"relation:belongsTo"`
+	}
+
+	// ok: belongsTo with poly
+	field, _ := reflect.TypeOf(Animal{}).FieldByName("SpeciesPolyPtr")
+	tags, err := orm.ParseFieldTags(field)
+	asserts.NoError(err)
+	asserts.Equal(orm.FieldTags{Relation: orm.BelongsTo, ForeignKey: "SpeciesID", References: "ID", Polymorphic: true, PolymorphicName: "SpeciesPoly", PolymorphicValue: "Animal"}, tags)
+
+	// ok: default relation kind
+	field, _ = reflect.TypeOf(Animal{}).FieldByName("Toys")
+	tags, err = orm.ParseFieldTags(field)
+	asserts.NoError(err)
+	asserts.Equal(orm.HasMany, tags.Relation)
+
+	// ok: field
+	order := 2
+	field, _ = reflect.TypeOf(tagModel{}).FieldByName("Name")
+	tags, err = orm.ParseFieldTags(field)
+	asserts.NoError(err)
+	asserts.Equal(orm.FieldTags{Column: "first_name", Permission: &orm.Permission{Read: true}, Unique: true, UniqueWhere: "deleted_at IS NULL", Order: &order, Sensitive: true, Validate: "required"}, tags)
+
+	// ok: skip
+	field, _ = reflect.TypeOf(tagModel{}).FieldByName("Skip")
+	tags, err = orm.ParseFieldTags(field)
+	asserts.NoError(err)
+	asserts.True(tags.Skip)
+
+	// error: order is no integer
+	field, _ = reflect.TypeOf(tagModel{}).FieldByName("Order")
+	_, err = orm.ParseFieldTags(field)
+	asserts.Error(err)
+	asserts.Equal(fmt.Sprintf(orm.ErrFieldOrder, "x", "Order"), err.Error())
+
+	// error: relation kind is not allowed
+	field, _ = reflect.TypeOf(tagModel{}).FieldByName("Broken")
+	_, err = orm.ParseFieldTags(field)
+	asserts.Error(err)
+	asserts.Equal(fmt.Sprintf(orm.ErrRelationKind, orm.BelongsTo, "slice", "Broken"), err.Error())
+}
+
+// TestParseFieldTags_Init tests:
+// - If ParseFieldTags returns the same view of the tags as the model Init.
+func TestParseFieldTags_Init(t *testing.T) {
+	asserts := assert.New(t)
+	helperCreateDatabaseAndTable(asserts)
+
+	animal := Animal{}
+	err := animal.Init(&animal)
+	asserts.NoError(err)
+	scope, err := animal.Scope()
+	asserts.NoError(err)
+	rt := reflect.TypeOf(animal)
+
+	// fields
+	for _, f := range scope.Fields(orm.Permission{}) {
+		field, ok := rt.FieldByName(f.Name)
+		asserts.True(ok)
+		tags, err := orm.ParseFieldTags(field)
+		asserts.NoError(err)
+		asserts.Equal(f.NoSQLColumn, tags.Custom, f.Name)
+		asserts.Equal(f.ReadOnly, tags.ReadOnly, f.Name)
+		asserts.Equal(f.Sensitive, tags.Sensitive, f.Name)
+		asserts.Equal(f.UniqueWhere, tags.UniqueWhere, f.Name)
+		asserts.Equal(f.ScanColumn, tags.Scan, f.Name)
+		if tags.Column != "" {
+			asserts.Equal(f.Information.Name, tags.Column, f.Name)
+		}
+		if tags.Permission != nil {
+			asserts.Equal(f.Permission, *tags.Permission, f.Name)
+		}
+	}
+
+	// relations
+	for _, r := range scope.Relations(orm.Permission{}) {
+		field, ok := rt.FieldByName(r.Field)
+		asserts.True(ok)
+		tags, err := orm.ParseFieldTags(field)
+		asserts.NoError(err)
+		asserts.Equal(r.Kind, tags.Relation, r.Field)
+		asserts.Equal(r.NoSQLColumn, tags.Custom, r.Field)
+		asserts.Equal(r.IsPolymorphic(), tags.Polymorphic, r.Field)
+		if tags.PolymorphicValue != "" {
+			asserts.Equal(r.Mapping.Polymorphic.Value, tags.PolymorphicValue, r.Field)
+		}
+		if tags.JoinTable != "" {
+			asserts.Equal(r.Mapping.Join.Table, tags.JoinTable, r.Field)
+		}
+		if tags.JoinReferences != "" {
+			asserts.Equal(r.Mapping.Join.ReferencesColumnName, tags.JoinReferences, r.Field)
+		}
+		if tags.Permission != nil {
+			asserts.Equal(r.Permission, *tags.Permission, r.Field)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2021 Patrick Ascher <development@fullhouse-productions.com>. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package orm
+
+// The following hook interfaces can be implemented by the orm model.
+// They are called before and after the Create, Update and Delete strategy.
+// The scope is passed to access the model information and the context (see Model.WithContext).
+// If a hook returns an error, the operation will be canceled and the transaction will be rolled back.
+
+// BeforeCreate hook.
+type BeforeCreate interface {
+	BeforeCreate(Scope) error
+}
+
+// AfterCreate hook.
+type AfterCreate interface {
+	AfterCreate(Scope) error
+}
+
+// BeforeUpdate hook.
+type BeforeUpdate interface {
+	BeforeUpdate(Scope) error
+}
+
+// AfterUpdate hook.
+type AfterUpdate interface {
+	AfterUpdate(Scope) error
+}
+
+// BeforeDelete hook.
+type BeforeDelete interface {
+	BeforeDelete(Scope) error
+}
+
+// AfterDelete hook.
+type AfterDelete interface {
+	AfterDelete(Scope) error
+}
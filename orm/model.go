@@ -6,8 +6,15 @@ package orm
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"math"
 	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	valid "github.com/go-playground/validator/v10"
@@ -27,24 +34,68 @@ const (
 
 // Error messages
 var (
-	ErrInit      = "orm: forgot to call Init() on %s"
-	ErrMandatory = "orm: %s is mandatory but has zero-value in %s"
-	ErrResultPtr = "orm: result variable must be a ptr in %s (All)"
+	ErrInit        = "orm: forgot to call Init() on %s"
+	ErrMandatory   = "orm: %s is mandatory but has zero-value in %s"
+	ErrResultPtr   = "orm: result variable must be a ptr in %s (All)"
+	ErrPluckPtr    = "orm: result variable must be a ptr to a slice in %s (Pluck)"
+	ErrPluckType   = "orm: slice type %s is not compatible with %s (%s)"
+	ErrPaginate    = "orm: page (%d) and perPage (%d) must be greater than 0 in %s"
+	ErrBatch       = "orm: items must be a slice of %s (BatchCreate)"
+	ErrNoCondition = "orm: delete without a condition is not allowed in %s, use SetAllowDeleteAll"
+	ErrLockTx      = "orm: row lock requires a transaction in %s"
+	ErrPreInit     = "orm: pre-init failed: %s"
+	ErrTouch       = "orm: %s has no UpdatedAt field (Touch)"
+	ErrTouchAll    = "orm: touch without a condition or primary keys is not allowed in %s"
+	ErrUnavailable = "orm: relation %s is not available: %w"
+	ErrCountHaving = "orm: count with having clauses on select expressions is not supported by the builder of %s"
+	ErrChunk       = "orm: size (%d) must be greater than 0 and exactly one primary key is required in %s (Chunk)"
+	// ErrRecordNotFound wraps sql.ErrNoRows and will return by FirstOrError if no result was found.
+	ErrRecordNotFound = fmt.Errorf("orm: record not found: %w", sql.ErrNoRows)
 )
 
 var registerdModels map[string]Interface
 
+// metadataCache defines if the parsed model structure is cached.
+var metadataCache = true
+
+// DisableMetadataCache disables the metadata cache for all orm models.
+// The structure is parsed from the database on every Init. This can be helpful during schema development.
+func DisableMetadataCache() {
+	metadataCache = false
+}
+
+// EnableMetadataCache enables the metadata cache for all orm models (default).
+func EnableMetadataCache() {
+	metadataCache = true
+}
+
 // Interface of the orm model.
 type Interface interface {
 	Init(Interface) error
 	Scope() (Scope, error)
 
 	First(c ...condition.Condition) error
+	FirstOrError(c condition.Condition) error
 	All(result interface{}, c ...condition.Condition) error
 	Count(c ...condition.Condition) (int, error)
+	Pluck(column string, dest interface{}, c ...condition.Condition) error
+	Paginate(c condition.Condition, page int, perPage int) (interface{}, Pagination, error)
+	Chunk(size int, c condition.Condition, fn func(batch interface{}) error) error
 	Create() error
+	BatchCreate(items interface{}) error
 	Update() error
+	Save() error
 	Delete() error
+	DeleteReturning(c condition.Condition, dest interface{}) error
+	Touch(c condition.Condition) (int, error)
+	Reset() error
+	WithContext(ctx context.Context) Interface
+	WithTx(tx query.Tx) Interface
+	With(relations ...string) Interface
+	WithoutRelations() Interface
+	ForUpdate() Interface
+	ForShare() Interface
+	Stats() Stats
 
 	// Permissions
 	Permissions() (p int, fields []string)
@@ -91,23 +142,45 @@ type Model struct {
 	tx      query.Tx
 	autoTx  bool
 
+	// request scoped values, accessible in the hooks.
+	ctx context.Context
+
 	// cache settings for the struct.
 	cache      cache.Manager
 	cacheTTL   time.Duration
 	softDelete *SoftDelete
 
 	permissionList *permissionList
+	with           []string
+	lock           string
 	snapshot       bool
 	snapshotCaller Interface
 
-	changedValues []ChangedValue
+	changedValues      []ChangedValue
+	circularReferences []circularReference
 
 	config        map[string]config
 	loopDetection map[string][]string
+	stats         *Stats
 
 	TimeFields
 }
 
+// Stats holds the rows read and affected of the last orm operation.
+// The rows of all relations are aggregated.
+type Stats struct {
+	RowsRead     int64
+	RowsAffected int64
+}
+
+// Pagination holds the information of a paginated result.
+type Pagination struct {
+	Total       int
+	TotalPages  int
+	CurrentPage int
+	PerPage     int
+}
+
 // RegisterModel is a function to register a models.
 // This can be used on application start to pre-cache all models and boost the performance.
 func RegisterModel(orm ...Interface) {
@@ -124,6 +197,37 @@ func RegisterModels() map[string]Interface {
 	return registerdModels
 }
 
+// PreInitAll initializes the given models in parallel to warm the metadata cache on application start.
+// The concurrency is bounded by the number of CPUs.
+// Error will return if one or more models can not be initialized, the error message identifies the failed models.
+func PreInitAll(models ...Interface) error {
+	errs := make([]error, len(models))
+	sem := make(chan struct{}, runtime.NumCPU())
+	var wg sync.WaitGroup
+	for i, m := range models {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, m Interface) {
+			defer func() { <-sem; wg.Done() }()
+			if err := m.Init(m); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", reflectName(m), err)
+			}
+		}(i, m)
+	}
+	wg.Wait()
+
+	var msg []string
+	for _, err := range errs {
+		if err != nil {
+			msg = append(msg, err.Error())
+		}
+	}
+	if len(msg) > 0 {
+		return fmt.Errorf(ErrPreInit, strings.Join(msg, "; "))
+	}
+	return nil
+}
+
 // Count the existing rows by the given condition.
 // TODO move the logic to provider for none db orm in the future?
 func (m *Model) Count(c ...condition.Condition) (int, error) {
@@ -144,10 +248,32 @@ func (m *Model) Count(c ...condition.Condition) (int, error) {
 	// First,all,... already using the right strategy
 	addSoftDeleteCondition(&m.scope, m.scope.Config(), cond)
 
-	// create query
-	row, err := m.builder.Query(m.tx).Select(m.scope.FqdnTable()).Condition(cond).Columns(query.DbExpr("COUNT(*)")).First()
-	if err != nil {
-		return 0, err
+	// the having clauses can reference the select expressions, therefore the rows are counted by a sub query.
+	var row *sql.Row
+	var err error
+	if exprs := m.scope.Config().selectExpressions; len(exprs) > 0 && len(cond.Having()) > 0 {
+		p, ok := m.builder.Query(m.tx).(query.Provider)
+		if !ok {
+			return 0, fmt.Errorf(ErrCountHaving, m.scope.Name(true))
+		}
+		sel := p.Select(m.scope.FqdnTable()).Condition(cond)
+		for _, expr := range exprs {
+			sel.ColumnExpression(expr.expr, expr.alias)
+		}
+		stmt, args, err := sel.String()
+		if err != nil {
+			return 0, err
+		}
+		row, err = p.First("SELECT COUNT(*) FROM ("+stmt+") "+m.builder.QuoteIdentifier("count_rows"), args)
+		if err != nil {
+			return 0, err
+		}
+	} else {
+		// create query
+		row, err = m.builder.Query(m.tx).Select(m.scope.FqdnTable()).Condition(cond).Columns(query.DbExpr("COUNT(*)")).First()
+		if err != nil {
+			return 0, err
+		}
 	}
 
 	var count int
@@ -159,6 +285,71 @@ func (m *Model) Count(c ...condition.Condition) (int, error) {
 	return count, nil
 }
 
+// Pluck will fetch a single column of all rows found by the condition.
+// The column is the struct field name and dest must be a ptr to a slice.
+// The slice type must be assignable from the field type or implement the sql.Scanner interface.
+// The condition is optional, if set the first argument will be used.
+// Error will return if the field does not exist, is no sql column or the dest type is not compatible.
+func (m *Model) Pluck(column string, dest interface{}, c ...condition.Condition) error {
+	// check if model is init.
+	if err := m.isInit(); err != nil {
+		return err
+	}
+
+	// check dest type
+	if dest == nil || reflect.TypeOf(dest).Kind() != reflect.Ptr || reflect.TypeOf(dest).Elem().Kind() != reflect.Slice {
+		return fmt.Errorf(ErrPluckPtr, m.scope.Name(true))
+	}
+
+	// check field
+	field, err := m.scope.Field(column)
+	if err != nil {
+		return err
+	}
+	if field.NoSQLColumn {
+		return fmt.Errorf(ErrFieldName, m.scope.FqdnModel(column))
+	}
+	elemType := reflect.TypeOf(dest).Elem().Elem()
+	fieldType := m.scope.FieldValue(column).Type()
+	if !fieldType.AssignableTo(elemType) && !reflect.PtrTo(elemType).Implements(reflect.TypeOf((*sql.Scanner)(nil)).Elem()) {
+		return fmt.Errorf(ErrPluckType, elemType, fieldType, m.scope.FqdnModel(column))
+	}
+
+	// create sql condition, the given condition is copied because the soft delete condition gets added.
+	var cond condition.Condition
+	if len(c) == 0 {
+		cond = condition.New()
+	} else {
+		cond = c[0].Copy()
+	}
+
+	// TODO must be fixed as soon as there are different strategies at the moment its fixed eager.
+	addSoftDeleteCondition(&m.scope, m.scope.Config(), cond)
+
+	// create query
+	rows, err := m.builder.Query(m.tx).Select(m.scope.FqdnTable()).Condition(cond).Columns(field.Information.Name).All()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	rv := reflect.MakeSlice(reflect.TypeOf(dest).Elem(), 0, 0)
+	for rows.Next() {
+		value := reflect.New(elemType)
+		err = rows.Scan(value.Interface())
+		if err != nil {
+			return err
+		}
+		rv = reflect.Append(rv, value.Elem())
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	reflect.ValueOf(dest).Elem().Set(rv)
+	return nil
+}
+
 // First will return the first found row.
 // The condition is optional, if set the first argument will be used.
 // A sql.ErrNoRows will return if no result was found.
@@ -169,6 +360,9 @@ func (m *Model) First(c ...condition.Condition) error {
 		return err
 	}
 
+	// reset the stats of the last operation.
+	m.resetStats()
+
 	// TODO Callbacks before
 
 	// create sql condition
@@ -179,7 +373,19 @@ func (m *Model) First(c ...condition.Condition) error {
 		cond = c[0]
 	}
 
-	err := m.scope.setFieldPermission()
+	restore, err := m.applyWith()
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	unlock, err := m.applyLock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	err = m.scope.setFieldPermission()
 	if err != nil {
 		return err
 	}
@@ -199,6 +405,16 @@ func (m *Model) First(c ...condition.Condition) error {
 	return nil
 }
 
+// FirstOrError is like First but returns ErrRecordNotFound if no result was found.
+// Not found errors of relations are not mapped and will return as they are.
+func (m *Model) FirstOrError(c condition.Condition) error {
+	err := m.First(c)
+	if err == sql.ErrNoRows {
+		return ErrRecordNotFound
+	}
+	return err
+}
+
 // All will return all results found by the condition.
 // The result argument must be as ptr slice to the struct.
 // The condition is optional, if set the first argument will be used.
@@ -210,6 +426,9 @@ func (m *Model) All(result interface{}, c ...condition.Condition) error {
 		return err
 	}
 
+	// reset the stats of the last operation.
+	m.resetStats()
+
 	// checking if the res is a ptr to an Interface slice.
 	if result == nil || reflect.TypeOf(result).Kind() != reflect.Ptr ||
 		(reflect.TypeOf(result).Elem().Kind() != reflect.Slice && reflect.TypeOf(result).Elem().Kind() != reflect.Ptr) ||
@@ -227,7 +446,19 @@ func (m *Model) All(result interface{}, c ...condition.Condition) error {
 		cond = c[0]
 	}
 
-	err := m.scope.setFieldPermission()
+	restore, err := m.applyWith()
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	unlock, err := m.applyLock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	err = m.scope.setFieldPermission()
 	if err != nil {
 		return err
 	}
@@ -247,6 +478,98 @@ func (m *Model) All(result interface{}, c ...condition.Condition) error {
 	return nil
 }
 
+// Paginate will return the rows of the given page and the pagination information.
+// The result is a slice of the orm model type. The condition can be nil.
+// Error will return if the page or perPage is lower than 1.
+func (m *Model) Paginate(c condition.Condition, page int, perPage int) (interface{}, Pagination, error) {
+	p := Pagination{CurrentPage: page, PerPage: perPage}
+
+	// check if model is init.
+	if err := m.isInit(); err != nil {
+		return nil, p, err
+	}
+
+	if page < 1 || perPage < 1 {
+		return nil, p, fmt.Errorf(ErrPaginate, page, perPage, m.scope.Name(true))
+	}
+
+	if c == nil {
+		c = condition.New()
+	}
+
+	// count on a copy, because the soft delete condition is added again on All.
+	var err error
+	p.Total, err = m.Count(c.Copy())
+	if err != nil {
+		return nil, p, err
+	}
+	p.TotalPages = int(math.Ceil(float64(p.Total) / float64(p.PerPage)))
+
+	result := reflect.New(reflect.SliceOf(reflect.TypeOf(m.caller).Elem()))
+	err = m.All(result.Interface(), c.Copy().SetLimit(perPage).SetOffset((page-1)*perPage))
+	if err != nil {
+		return nil, p, err
+	}
+
+	return result.Elem().Interface(), p, nil
+}
+
+// Chunk fetches the rows in batches of the given size, ordered by the primary key.
+// The callback receives a slice of the orm model type. The next batch is selected by the last primary key (keyset),
+// so no offset is used and no transaction is held between the batches.
+// Chunk stops if all rows are processed or the callback returns an error. The condition can be nil.
+// Error will return if the size is lower than 1 or the model has not exactly one primary key.
+func (m *Model) Chunk(size int, c condition.Condition, fn func(batch interface{}) error) error {
+	// check if model is init.
+	if err := m.isInit(); err != nil {
+		return err
+	}
+
+	pKeys, err := m.scope.PrimaryKeys()
+	if err != nil {
+		return err
+	}
+	if size < 1 || len(pKeys) != 1 {
+		return fmt.Errorf(ErrChunk, size, m.scope.Name(true))
+	}
+
+	if c == nil {
+		c = condition.New()
+	}
+
+	// the requested relations are used for all batches.
+	with := m.with
+	var last interface{}
+	for {
+		m.with = with
+		cond := c.Copy()
+		cond.Reset(condition.OFFSET)
+		if last != nil {
+			cond.SetWhere(m.scope.Builder().QuoteIdentifier(pKeys[0].Information.Name)+" > ?", last)
+		}
+		cond.SetOrder(pKeys[0].Information.Name).SetLimit(size)
+
+		result := reflect.New(reflect.SliceOf(reflect.TypeOf(m.caller).Elem()))
+		err = m.All(result.Interface(), cond)
+		if err != nil {
+			return err
+		}
+
+		batch := result.Elem()
+		if batch.Len() == 0 {
+			return nil
+		}
+		err = fn(batch.Interface())
+		if err != nil {
+			return err
+		}
+		if batch.Len() < size {
+			return nil
+		}
+		last = batch.Index(batch.Len() - 1).FieldByName(pKeys[0].Name).Interface()
+	}
+}
+
 // Create the given orm model.
 // A transaction will be created in the background for all relations and a rollback will be triggered if an error happens.
 // The orm model will be checked if its valid by tags.
@@ -259,12 +582,22 @@ func (m *Model) Create() (err error) {
 		return
 	}
 
-	// TODO callback before
+	// reset the stats of the last operation.
+	m.resetStats()
+
+	if h, ok := m.caller.(BeforeCreate); ok {
+		err = h.BeforeCreate(&m.scope)
+		if err != nil {
+			return
+		}
+	}
 
 	// set the CreatedAt info if exists
 	// it only gets saved if the field exists in the db (permission is set)
-	createdAt := query.NewNullTime(time.Now(), true)
-	m.CreatedAt = &createdAt
+	if m.manageTimestamps() {
+		createdAt := query.NewNullTime(time.Now(), true)
+		m.CreatedAt = &createdAt
+	}
 
 	// if the model is empty no need for creating.
 	if m.scope.IsEmpty(Permission{Write: true}) {
@@ -287,17 +620,131 @@ func (m *Model) Create() (err error) {
 		return
 	}
 
+	err = m.scope.checkUniqueTogether()
+	if err != nil {
+		return
+	}
+
 	err = m.strategy.Create(&m.scope)
 	if err != nil {
 		return
 	}
 
+	if h, ok := m.caller.(AfterCreate); ok {
+		err = h.AfterCreate(&m.scope)
+		if err != nil {
+			return
+		}
+	}
+
 	err = m.commitAutoTx()
+	return
+}
+
+// BatchCreate creates all items in one transaction.
+// Items must be a []*T or a ptr to a []T or []*T of the orm model type.
+// The root entries are inserted with multi value statements and the autoincrement ids are set to the items.
+// The ids are calculated from the first id of each statement, this requires that the database assigns consecutive ids
+// to a multi value insert (mysql: auto_increment_increment=1). Otherwise a primary key generator must be used.
+// The relations of each item are created as usual.
+// Error will return if the items are not a slice of the orm model, an item is not valid or a sql error happens.
+func (m *Model) BatchCreate(items interface{}) (err error) {
+	defer func() { modelDefer(m, err) }()
+
+	// check if model is init.
+	if err = m.isInit(); err != nil {
+		return
+	}
+
+	// reset the stats of the last operation.
+	m.resetStats()
+
+	slice := reflect.Indirect(reflect.ValueOf(items))
+	if slice.Kind() != reflect.Slice || strings.TrimPrefix(slice.Type().Elem().String(), "*") != reflectName(m.caller) {
+		err = fmt.Errorf(ErrBatch, reflectName(m.caller))
+		return
+	}
+
+	// init the items.
+	var scopes []Scope
+	for i := 0; i < slice.Len(); i++ {
+		item := slice.Index(i)
+		if item.Kind() == reflect.Ptr {
+			if item.IsNil() {
+				continue
+			}
+			item = item.Elem()
+		}
+		if !item.CanAddr() {
+			err = fmt.Errorf(ErrBatch, reflectName(m.caller))
+			return
+		}
+		model := item.Addr().Interface().(Interface)
+		err = m.scope.InitRelation(model, "")
+		if err != nil {
+			return
+		}
+		itemModel := model.model()
+
+		if h, ok := model.(BeforeCreate); ok {
+			err = h.BeforeCreate(&itemModel.scope)
+			if err != nil {
+				return
+			}
+		}
+
+		if itemModel.manageTimestamps() {
+			createdAt := query.NewNullTime(time.Now(), true)
+			itemModel.CreatedAt = &createdAt
+		}
+
+		if itemModel.scope.IsEmpty(Permission{Write: true}) {
+			continue
+		}
+
+		err = itemModel.scope.setFieldPermission()
+		if err != nil {
+			return
+		}
+
+		err = itemModel.IsValid()
+		if err != nil {
+			return
+		}
+		scopes = append(scopes, &itemModel.scope)
+	}
+
+	if len(scopes) == 0 {
+		return
+	}
+
+	// one transaction for all items.
+	if m.tx == nil {
+		m.autoTx = true
+		m.tx, err = m.builder.Query().Tx()
+		if err != nil {
+			return
+		}
+	}
+	for _, scope := range scopes {
+		scope.Model().tx = m.tx
+	}
+
+	err = m.strategy.CreateBatch(scopes)
 	if err != nil {
 		return
 	}
 
-	// TODO callback after
+	for _, scope := range scopes {
+		if h, ok := scope.Caller().(AfterCreate); ok {
+			err = h.AfterCreate(scope)
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	err = m.commitAutoTx()
 	return
 }
 
@@ -314,6 +761,9 @@ func (m *Model) Update() (err error) {
 		return err
 	}
 
+	// reset the stats of the last operation.
+	m.resetStats()
+
 	// check primary keys
 	if !m.scope.PrimaryKeysSet() {
 		err = fmt.Errorf("PKEY Err for %s", m.name)
@@ -341,7 +791,12 @@ func (m *Model) Update() (err error) {
 		c.SetWhere(m.scope.Builder().QuoteIdentifier(pkey.Information.Name)+" = ?", m.scope.FieldValue(pkey.Name).Interface())
 	}
 
-	// TODO callback before
+	if h, ok := m.caller.(BeforeUpdate); ok {
+		err = h.BeforeUpdate(&m.scope)
+		if err != nil {
+			return
+		}
+	}
 
 	// call delete on strategy
 	err = m.addAutoTx()
@@ -364,6 +819,7 @@ func (m *Model) Update() (err error) {
 		if err != nil {
 			return
 		}
+		restrictSnapshotColumns(snapshot.model().scope, m.scope.Config())
 
 		err = m.strategy.First(&snapshot.model().scope, c, Permission{Write: true})
 		if err != nil {
@@ -386,21 +842,93 @@ func (m *Model) Update() (err error) {
 
 	// set the UpdatedAt info if exists
 	// it only gets saved if the field exists in the db (permission is set)
-	updatedAt := query.NewNullTime(time.Now(), true)
-	m.UpdatedAt = &updatedAt
+	if m.manageTimestamps() {
+		updatedAt := query.NewNullTime(time.Now(), true)
+		m.UpdatedAt = &updatedAt
+	}
+
+	err = m.scope.checkUniqueTogether()
+	if err != nil {
+		return
+	}
 
 	err = m.strategy.Update(&m.scope, c)
 	if err != nil {
 		return
 	}
 
+	if h, ok := m.caller.(AfterUpdate); ok {
+		err = h.AfterUpdate(&m.scope)
+		if err != nil {
+			return
+		}
+	}
+
 	err = m.commitAutoTx()
+	return
+}
+
+// Save will create the orm model if the primary keys are not set, otherwise it will be updated.
+// If the primary keys are set but the entry does not exist yet, the entry will be created.
+// The existence check (SELECT ... FOR UPDATE) and the create or update are running in one transaction.
+// If a transaction was set by WithTx, it will be used and not committed.
+func (m *Model) Save() (err error) {
+	// check if model is init.
+	if err = m.isInit(); err != nil {
+		return err
+	}
+
+	if !m.scope.PrimaryKeysSet() {
+		return m.Create()
+	}
+
+	if m.tx == nil {
+		m.tx, err = m.builder.Query().Tx()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			tx := m.tx
+			m.tx = nil
+			if err != nil {
+				// the query package already rolls back on sql errors.
+				if tx.HasTx() {
+					_ = tx.Rollback()
+				}
+				return
+			}
+			err = tx.Commit()
+		}()
+	}
+
+	// lock the primary key.
+	pKeys, err := m.scope.PrimaryKeys()
 	if err != nil {
-		return
+		return err
+	}
+	c := condition.New()
+	var columns []string
+	for _, pkey := range pKeys {
+		columns = append(columns, pkey.Information.Name)
+		c.SetWhere(m.scope.Builder().QuoteIdentifier(pkey.Information.Name)+" = ?", m.scope.FieldValue(pkey.Name).Interface())
+	}
+	row, err := m.builder.Query(m.tx).Select(m.scope.FqdnTable()).Columns(columns...).Condition(c).Lock(query.LockForUpdate).First()
+	if err != nil {
+		return err
+	}
+	keys := make([]interface{}, len(columns))
+	for i := range keys {
+		keys[i] = new(interface{})
+	}
+	err = row.Scan(keys...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return m.Create()
+	}
+	if err != nil {
+		return err
 	}
-	// TODO callback after
 
-	return nil
+	return m.Update()
 }
 
 // Delete the orm model by its primary keys.
@@ -413,9 +941,13 @@ func (m *Model) Delete() (err error) {
 		return err
 	}
 
-	// check primary keys
-	if !m.scope.PrimaryKeysSet() {
-		err = fmt.Errorf("PKEY Err for %s", m.name)
+	// reset the stats of the last operation.
+	m.resetStats()
+
+	// check primary keys, a delete without condition must be allowed explicitly.
+	deleteAll := !m.scope.PrimaryKeysSet()
+	if deleteAll && !m.scope.Config().allowDeleteAll {
+		err = fmt.Errorf(ErrNoCondition, m.scope.Name(true))
 		return
 	}
 
@@ -430,35 +962,210 @@ func (m *Model) Delete() (err error) {
 		return
 	}
 	c := condition.New()
-	for _, pkey := range pKeys {
-		c.SetWhere(m.scope.Builder().QuoteIdentifier(pkey.Information.Name)+" = ?", m.scope.FieldValue(pkey.Name).Interface())
+	if !deleteAll {
+		for _, pkey := range pKeys {
+			c.SetWhere(m.scope.Builder().QuoteIdentifier(pkey.Information.Name)+" = ?", m.scope.FieldValue(pkey.Name).Interface())
+		}
+	}
+
+	if h, ok := m.caller.(BeforeDelete); ok {
+		err = h.BeforeDelete(&m.scope)
+		if err != nil {
+			return
+		}
 	}
 
 	// check if its a soft delete
 	if m.softDelete != nil {
-		_, err = m.scope.Builder().Query(m.tx).Update(m.scope.FqdnTable()).Columns(m.softDelete.Field).Set(map[string]interface{}{m.softDelete.Field: m.softDelete.Value}).Condition(c).Exec()
-		return err
+		var res sql.Result
+		res, err = m.scope.Builder().Query(m.tx).Update(m.scope.FqdnTable()).Columns(m.softDelete.Field).Set(map[string]interface{}{m.softDelete.Field: m.softDelete.Value}).Condition(c).Exec()
+		if err != nil {
+			return
+		}
+		m.addRowsAffected(res)
+		if h, ok := m.caller.(AfterDelete); ok {
+			err = h.AfterDelete(&m.scope)
+			if err != nil {
+				return
+			}
+		}
+		return
 	}
 
-	// TODO callback before
-
 	// call delete on strategy
 	err = m.addAutoTx()
 	if err != nil {
 		return
 	}
 
-	err = m.strategy.Delete(&m.scope, c)
+	if deleteAll {
+		// only the root table is deleted, relation entries are not touched.
+		var res sql.Result
+		res, err = m.scope.Builder().Query(m.tx).Delete(m.scope.FqdnTable()).Condition(c).Exec()
+		if err != nil {
+			return
+		}
+		m.addRowsAffected(res)
+	} else {
+		err = m.strategy.Delete(&m.scope, c)
+		if err != nil {
+			return
+		}
+	}
+
+	if h, ok := m.caller.(AfterDelete); ok {
+		err = h.AfterDelete(&m.scope)
+		if err != nil {
+			return
+		}
+	}
+
+	err = m.commitAutoTx()
+	return
+}
+
+// DeleteReturning deletes all rows of the condition and returns the deleted rows into dest.
+// The dest argument must be a ptr slice to the struct.
+// The rows are fetched with a FOR UPDATE lock and deleted afterwards in the same transaction.
+// Only the root table is deleted, relations are not touched. If a soft delete is defined, the rows are soft deleted.
+// Error will return if the condition has no where clause and SetAllowDeleteAll was not set.
+func (m *Model) DeleteReturning(c condition.Condition, dest interface{}) (err error) {
+	defer func() { modelDefer(m, err) }()
+
+	// check if model is init.
+	if err = m.isInit(); err != nil {
+		return
+	}
+
+	if c == nil {
+		c = condition.New()
+	}
+	if len(c.Where()) == 0 && !m.scope.Config().allowDeleteAll {
+		err = fmt.Errorf(ErrNoCondition, m.scope.Name(true))
+		return
+	}
+
+	// a transaction is always needed to guarantee the fetched and deleted rows are the same.
+	if m.tx == nil {
+		m.autoTx = true
+		m.tx, err = m.builder.Query().Tx()
+		if err != nil {
+			return
+		}
+	}
+
+	// fetch the rows.
+	m.lock = query.LockForUpdate
+	err = m.All(dest, c)
 	if err != nil {
 		return
 	}
+	if reflect.Indirect(reflect.ValueOf(dest)).Len() == 0 {
+		err = m.commitAutoTx()
+		return
+	}
 
-	err = m.commitAutoTx()
+	// delete the rows.
+	var res sql.Result
+	if m.softDelete != nil {
+		res, err = m.scope.Builder().Query(m.tx).Update(m.scope.FqdnTable()).Columns(m.softDelete.Field).Set(map[string]interface{}{m.softDelete.Field: m.softDelete.Value}).Condition(c).Exec()
+	} else {
+		res, err = m.scope.Builder().Query(m.tx).Delete(m.scope.FqdnTable()).Condition(c).Exec()
+	}
 	if err != nil {
 		return
 	}
+	m.addRowsAffected(res)
 
-	// TODO callback after
+	err = m.commitAutoTx()
+	return
+}
+
+// Touch sets the UpdatedAt field of all rows of the condition to the actual time.
+// If the condition has no where clause, the row of the set primary keys is touched. Soft deleted rows are not touched.
+// Relations, hooks and the change detection are skipped. The number of affected rows will return.
+// Error will return if the model has no UpdatedAt field or neither a condition nor the primary keys are set.
+func (m *Model) Touch(c condition.Condition) (int, error) {
+
+	// check if model is init.
+	if err := m.isInit(); err != nil {
+		return 0, err
+	}
+
+	// reset the stats of the last operation.
+	m.resetStats()
+
+	f, err := m.scope.Field(UpdatedAt)
+	if err != nil {
+		return 0, fmt.Errorf(ErrTouch, m.scope.Name(true))
+	}
+
+	// a condition or the primary keys are required, so that not all rows are touched by accident.
+	if c == nil || len(c.Where()) == 0 {
+		if !m.scope.PrimaryKeysSet() {
+			return 0, fmt.Errorf(ErrTouchAll, m.scope.Name(true))
+		}
+		pKeys, err := m.scope.PrimaryKeys()
+		if err != nil {
+			return 0, err
+		}
+		c = condition.New()
+		for _, pkey := range pKeys {
+			c.SetWhere(m.scope.Builder().QuoteIdentifier(pkey.Information.Name)+" = ?", m.scope.FieldValue(pkey.Name).Interface())
+		}
+	} else {
+		c = c.Copy()
+	}
+	addSoftDeleteCondition(&m.scope, m.scope.Config(), c)
+
+	res, err := m.scope.Builder().Query(m.tx).Update(m.scope.FqdnTable()).Columns(f.Information.Name).Set(map[string]interface{}{f.Information.Name: query.NewNullTime(time.Now(), true)}).Condition(c).Exec()
+	if err != nil {
+		return 0, err
+	}
+	m.addRowsAffected(res)
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(rows), nil
+}
+
+// Reset will clear all field and relation values, the snapshot, the changed values and an open auto transaction.
+// The cached model information is kept, so the orm model can be reused without calling Init again.
+// Error will return if the orm model was not initialized or the rollback of the transaction fails.
+func (m *Model) Reset() error {
+	// check if model is init.
+	if err := m.isInit(); err != nil {
+		return err
+	}
+
+	// field and relation values.
+	for _, f := range m.fields {
+		v := m.scope.FieldValue(f.Name)
+		v.Set(reflect.Zero(v.Type()))
+	}
+	for _, r := range m.relations {
+		v := m.scope.FieldValue(r.Field)
+		v.Set(reflect.Zero(v.Type()))
+	}
+	m.TimeFields = TimeFields{}
+
+	// snapshot and change tracking.
+	m.snapshot = false
+	m.snapshotCaller = nil
+	m.changedValues = nil
+	m.loopDetection = nil
+
+	// auto transaction.
+	if m.autoTx && m.tx != nil {
+		m.autoTx = false
+		tx := m.tx
+		m.tx = nil
+		if tx.HasTx() {
+			return tx.Rollback()
+		}
+	}
 
 	return nil
 }
@@ -472,12 +1179,19 @@ func (m *Model) Init(caller Interface) error {
 	if m.cache, m.cacheTTL = m.caller.DefaultCache(); m.cache == nil {
 		return fmt.Errorf(ErrMandatory, "cache", reflectName(caller))
 	}
+	if e, ok := m.caller.(CacheExpirer); ok {
+		m.cacheTTL = e.CacheExpiration()
+	}
 
 	// set scope
 	m.scope.model = m
 
+	// the metadata cache can be disabled globally or by the model config (re-Init).
+	useCache := metadataCache && !m.config[RootStruct].disableMetadataCache
+	modelConfig := m.config
+
 	// check if a cache exists
-	if m.cache.Exist(prefixCache, m.scope.Name(true)) {
+	if useCache && m.cache.Exist(prefixCache, m.scope.Name(true)) {
 		item, err := m.cache.Get(prefixCache, m.scope.Name(true))
 		if err != nil {
 			return err
@@ -531,13 +1245,18 @@ func (m *Model) Init(caller Interface) error {
 
 		// set default config
 		m.scope.model.config = make(map[string]config, 1)
-		m.scope.SetConfig(&config{allowHasOneZero: true, showDeletedRows: false})
+		m.scope.SetConfig(DefaultConfig())
 
 		// set cache
 		err = m.cache.Set(prefixCache, m.scope.Name(true), *m, m.cacheTTL)
 		if err != nil {
 			return fmt.Errorf("orm: %w", err)
 		}
+
+		// keep the model config if the cache is disabled by it.
+		if modelConfig[RootStruct].disableMetadataCache {
+			m.config = modelConfig
+		}
 	}
 
 	// fields and relations are copied so that no change will reflect the cache.
@@ -546,6 +1265,60 @@ func (m *Model) Init(caller Interface) error {
 	return nil
 }
 
+// WithContext sets a context to the orm model.
+// It can be used to pass request scoped values (user, request id, ...) to the hooks.
+// Relation models will use the context of the root model.
+func (m *Model) WithContext(ctx context.Context) Interface {
+	m.ctx = ctx
+	return m.caller
+}
+
+// WithTx sets a transaction to the orm model, all following statements are executed in it.
+// The transaction is not committed or rolled back by the orm model, this must be done by the caller.
+func (m *Model) WithTx(tx query.Tx) Interface {
+	m.tx = tx
+	m.autoTx = false
+	return m.caller
+}
+
+// ForUpdate locks the selected rows of the next First or All call with FOR UPDATE.
+// Only the root select is locked. The lock is ignored outside of a transaction, this can be changed by config.
+func (m *Model) ForUpdate() Interface {
+	m.lock = query.LockForUpdate
+	return m.caller
+}
+
+// ForShare locks the selected rows of the next First or All call with a shared lock.
+// Only the root select is locked. The lock is ignored outside of a transaction, this can be changed by config.
+func (m *Model) ForShare() Interface {
+	m.lock = query.LockForShare
+	return m.caller
+}
+
+// With defines the relations which should be loaded on the next First or All call.
+// Dotted paths are allowed (Toys.Animal), all other relations will be skipped.
+// Error will return on First or All if a relation does not exist.
+func (m *Model) With(relations ...string) Interface {
+	m.with = relations
+	return m.caller
+}
+
+// WithoutRelations skips all relations on the next First or All call.
+// Only the root row(s) will be selected.
+func (m *Model) WithoutRelations() Interface {
+	m.with = []string{}
+	return m.caller
+}
+
+// Stats returns the rows read and affected of the last First, All, Create, BatchCreate, Update, Save or Delete call.
+// The rows of all relations are included.
+func (m *Model) Stats() Stats {
+	if m.stats == nil {
+		return Stats{}
+	}
+	return *m.stats
+}
+
 // Scope will return the models scope with some helper functions.
 // Error will return if the model was not initialized yet.
 func (m *Model) Scope() (Scope, error) {
@@ -555,6 +1328,15 @@ func (m *Model) Scope() (Scope, error) {
 	return &m.scope, nil
 }
 
+// ReadOnlyScope will return the orm model scope with a read-only builder.
+// Insert, Update and Delete statements of the builder will return a query.ErrReadOnly.
+func (m *Model) ReadOnlyScope() (Scope, error) {
+	if err := m.isInit(); err != nil {
+		return nil, fmt.Errorf(ErrInit, reflectName(m.caller))
+	}
+	return readOnlyScope{scope: &m.scope}, nil
+}
+
 // SetPermissions allows to set a policy (White/Blacklist), Fields or Relations.
 func (m *Model) SetPermissions(p int, fields ...string) {
 	fields = slicer.StringUnique(fields)
@@ -570,6 +1352,7 @@ func (m *Model) Permissions() (p int, fields []string) {
 }
 
 // IsValid checks if a custom validation was added and runs it.
+// The validation config of writeable relations is checked (example: hasMany with min=1).
 // After that the struct will be validated by tag, if set.
 func (m Model) IsValid() error {
 
@@ -583,9 +1366,28 @@ func (m Model) IsValid() error {
 		}
 	}
 
+	// relation validation (min=1, required,...) of writeable relations.
+	// relations which are already validated or not writeable are excluded from the struct validation.
+	var except []string
+	for _, relation := range m.scope.Relations(Permission{}) {
+		if !relation.Permission.Write || relation.Unavailable != nil {
+			except = append(except, relation.Field)
+			continue
+		}
+		if config := relation.Validator.Config(); config != "" {
+			err := errorMessage(m, relation.Field, validate.VarCtx(newCtx(m), m.scope.FieldValue(relation.Field).Interface(), config))
+			if err != nil {
+				return err
+			}
+			except = append(except, relation.Field)
+		} else if m.isCircularReference(relation) {
+			// the back reference would end in a validation loop, the relation is validated by its own model.
+			except = append(except, relation.Field)
+		}
+	}
+
 	// struct tag validation
-	// TODO: this will end in a loop on Animal - Address - *Animal backref.
-	err := errorMessage(m, "", validate.StructCtx(newCtx(m), m.caller))
+	err := errorMessage(m, "", validate.StructExceptCtx(newCtx(m), m.caller, except...))
 	if err != nil {
 		return err
 	}
@@ -593,6 +1395,31 @@ func (m Model) IsValid() error {
 	return nil
 }
 
+// isCircularReference checks if the relation value references the caller back (A belongsTo B, B belongsTo A).
+func (m Model) isCircularReference(relation Relation) bool {
+	v := m.scope.FieldValue(relation.Field)
+	caller := reflect.ValueOf(m.caller)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct || caller.Kind() != reflect.Ptr {
+		return false
+	}
+	v = v.Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if f := v.Field(i); f.Kind() == reflect.Ptr && !f.IsNil() && f.Pointer() == caller.Pointer() {
+			return true
+		}
+	}
+	return false
+}
+
+// manageTimestamps is a helper to check if the CreatedAt and UpdatedAt fields should be set automatically.
+// The configuration of the root struct will be taken.
+func (m *Model) manageTimestamps() bool {
+	if root, err := m.scope.Parent(RootStruct); err == nil {
+		return !root.config[RootStruct].disableTimestamps
+	}
+	return !m.config[RootStruct].disableTimestamps
+}
+
 // isInit is a helper to identify if the orm.Model was already initialized.
 func (m *Model) isInit() error {
 	if m.scope.model == nil {
@@ -641,6 +1468,37 @@ func (m *Model) commitAutoTx() error {
 	return nil
 }
 
+// resetStats is a helper to reset the stats on the root orm model.
+// Relation models are sharing the stats of the root model.
+func (m *Model) resetStats() {
+	if m.parentModel == nil {
+		m.stats = &Stats{}
+	}
+}
+
+// addRowsRead is a helper to add read rows to the stats.
+func (m *Model) addRowsRead(n int64) {
+	if m.stats != nil {
+		atomic.AddInt64(&m.stats.RowsRead, n)
+	}
+}
+
+// addRowsAffected is a helper to add the affected rows of the sql results to the stats.
+// Results which are not supporting RowsAffected are skipped.
+func (m *Model) addRowsAffected(res ...sql.Result) {
+	if m.stats == nil {
+		return
+	}
+	for _, r := range res {
+		if r == nil {
+			continue
+		}
+		if n, err := r.RowsAffected(); err == nil {
+			atomic.AddInt64(&m.stats.RowsAffected, n)
+		}
+	}
+}
+
 // setParent is a helper to set a parent.
 func (m *Model) setParent(caller *Model) {
 	m.parentModel = caller
@@ -651,6 +1509,48 @@ func (m *Model) model() *Model {
 	return m
 }
 
+// applyWith is a helper to set the permission list of the requested With relations.
+// The returned function restores the previous permission list and field/relation permissions.
+func (m *Model) applyWith() (func(), error) {
+	if m.with == nil {
+		return func() {}, nil
+	}
+	relations := m.with
+	m.with = nil
+
+	list, err := withPermissionList(&m.scope, m.permissionList, relations)
+	if err != nil {
+		return nil, err
+	}
+
+	prevList := m.permissionList
+	fields := make([]Field, len(m.fields))
+	copy(fields, m.fields)
+	rels := make([]Relation, len(m.relations))
+	copy(rels, m.relations)
+
+	m.permissionList = list
+	return func() {
+		m.permissionList = prevList
+		copy(m.fields, fields)
+		copy(m.relations, rels)
+	}, nil
+}
+
+// applyLock is a helper to check the requested row lock.
+// The lock is only used once. Outside of a transaction it is ignored or an error will return, if configured.
+// The returned function resets the lock.
+func (m *Model) applyLock() (func(), error) {
+	reset := func() { m.lock = "" }
+	if m.lock != "" && (m.tx == nil || !m.tx.HasTx()) {
+		reset()
+		if m.scope.Config().lockRequireTx {
+			return nil, fmt.Errorf(ErrLockTx, m.scope.Name(true))
+		}
+	}
+	return reset, nil
+}
+
 // copyFieldRelationSlices is needed that the cached fields and relations of the orm model are not getting changed.
 func (m *Model) copyFieldRelationSlices() {
 	cFields := make([]Field, len(m.fields))
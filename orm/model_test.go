@@ -14,6 +14,7 @@ import (
 	mockCache "github.com/patrickascher/gofer/cache/mocks"
 	"github.com/patrickascher/gofer/orm"
 	"github.com/patrickascher/gofer/query"
+	"github.com/patrickascher/gofer/query/condition"
 	mockBuilder "github.com/patrickascher/gofer/query/mocks"
 	"github.com/stretchr/testify/assert"
 )
@@ -144,3 +145,166 @@ func createOrm(init bool) (Orm, *mockCache.Manager, *mockBuilder.Builder, error)
 	}
 	return testOrm, mCache, mBuilder, err
 }
+
+// TestModel_MetadataCache tests:
+// - if a schema change is not picked up if the structure is cached.
+// - if a schema change is picked up on the next Init if the metadata cache is disabled by config.
+// - if a schema change is picked up on Init if the metadata cache is disabled globally.
+func TestModel_MetadataCache(t *testing.T) {
+	asserts := assert.New(t)
+
+	mem, err := cache.New("memory", nil)
+	asserts.NoError(err)
+	builder := createTestTable(asserts)
+	testOrm := &OrmIDTag{OrmFieldBase: OrmFieldBase{mockCache: mem, mockCacheTTL: cache.NoExpiration, mockBuilder: builder}}
+	err = testOrm.Init(testOrm)
+	asserts.NoError(err)
+
+	nameLength := func() int64 {
+		scope, err := testOrm.Scope()
+		asserts.NoError(err)
+		field, err := scope.Field("Name")
+		asserts.NoError(err)
+		return field.Information.Length.Int64
+	}
+	asserts.Equal(int64(50), nameLength())
+
+	// ok: cached structure.
+	_, err = builder.Query().DB().Exec("ALTER TABLE `orm_field` MODIFY `name` varchar(100) DEFAULT ''")
+	asserts.NoError(err)
+	err = testOrm.Init(testOrm)
+	asserts.NoError(err)
+	asserts.Equal(int64(50), nameLength())
+
+	// ok: disabled by config.
+	scope, err := testOrm.Scope()
+	asserts.NoError(err)
+	scope.SetConfig(orm.NewConfig().SetAllowHasOneZero(true).SetMetadataCache(false))
+	err = testOrm.Init(testOrm)
+	asserts.NoError(err)
+	asserts.Equal(int64(100), nameLength())
+
+	// ok: disabled globally.
+	_, err = builder.Query().DB().Exec("ALTER TABLE `orm_field` MODIFY `name` varchar(150) DEFAULT ''")
+	asserts.NoError(err)
+	orm.DisableMetadataCache()
+	defer orm.EnableMetadataCache()
+	testOrm = &OrmIDTag{OrmFieldBase: OrmFieldBase{mockCache: mem, mockCacheTTL: cache.NoExpiration, mockBuilder: builder}}
+	err = testOrm.Init(testOrm)
+	asserts.NoError(err)
+	asserts.Equal(int64(150), nameLength())
+}
+
+// OrmCacheExpiration - test with a model cache expiration.
+type OrmCacheExpiration struct {
+	OrmFieldBase
+	ID int
+}
+
+func (t *OrmCacheExpiration) CacheExpiration() time.Duration {
+	return 10 * time.Minute
+}
+
+// TestModel_CacheExpiration tests:
+// - if the DefaultCache duration is used.
+// - if the CacheExpiration of the model overwrites the DefaultCache duration.
+func TestModel_CacheExpiration(t *testing.T) {
+	asserts := assert.New(t)
+
+	mem, err := cache.New("memory", nil)
+	asserts.NoError(err)
+	builder := createTestTable(asserts)
+
+	// ok: DefaultCache duration.
+	idField := &OrmIDField{OrmFieldBase: OrmFieldBase{mockCache: mem, mockCacheTTL: cache.NoExpiration, mockBuilder: builder}}
+	err = idField.Init(idField)
+	asserts.NoError(err)
+	item, err := mem.Get("orm_", "orm_test.OrmIDField")
+	asserts.NoError(err)
+	asserts.Equal(time.Duration(cache.NoExpiration), item.Expiration())
+
+	// ok: model CacheExpiration.
+	expiration := &OrmCacheExpiration{OrmFieldBase: OrmFieldBase{mockCache: mem, mockCacheTTL: cache.NoExpiration, mockBuilder: builder}}
+	err = expiration.Init(expiration)
+	asserts.NoError(err)
+	item, err = mem.Get("orm_", "orm_test.OrmCacheExpiration")
+	asserts.NoError(err)
+	asserts.Equal(10*time.Minute, item.Expiration())
+}
+
+// TestPreInitAll tests:
+// - if all models are initialized.
+// - if the failed model is identified in the error while the others are initialized.
+func TestPreInitAll(t *testing.T) {
+	asserts := assert.New(t)
+	helperCreateDatabaseAndTable(asserts)
+
+	// ok: all models are initialized.
+	animal := Animal{}
+	human := Human{}
+	err := orm.PreInitAll(&animal, &human)
+	asserts.NoError(err)
+	_, err = animal.Scope()
+	asserts.NoError(err)
+	_, err = human.Scope()
+	asserts.NoError(err)
+
+	// error: one broken model.
+	animal = Animal{}
+	broken := Orm{}
+	err = orm.PreInitAll(&animal, &broken)
+	asserts.Error(err)
+	asserts.Equal(fmt.Sprintf(orm.ErrPreInit, "orm_test.Orm: "+fmt.Sprintf(orm.ErrMandatory, "cache", "orm_test.Orm")), err.Error())
+	_, err = animal.Scope()
+	asserts.NoError(err)
+}
+
+// TestModel_Pluck tests:
+// - If a single column is fetched into the slice.
+// - If soft deleted rows are excluded.
+// - If the given condition is not modified.
+// - Error if the dest is not a slice ptr, the field does not exist or the type is not compatible.
+func TestModel_Pluck(t *testing.T) {
+	asserts := assert.New(t)
+
+	helperCreateDatabaseAndTable(asserts)
+	insertUserData(asserts)
+
+	animal := Animal{}
+	err := animal.Init(&animal)
+	asserts.NoError(err)
+
+	// ok - soft deleted Nala is excluded.
+	var names []string
+	c := condition.New().SetOrder("id")
+	err = animal.Pluck("Name", &names, c)
+	asserts.NoError(err)
+	asserts.Equal([]string{"Blacky", "Snowflake", "Simba"}, names)
+	asserts.Equal(0, len(c.Where()))
+
+	// ok - with condition.
+	var ids []int
+	err = animal.Pluck("ID", &ids, condition.New().SetWhere("species_id = ?", 3))
+	asserts.NoError(err)
+	asserts.Equal([]int{3}, ids)
+
+	// error - dest is no slice ptr.
+	err = animal.Pluck("Name", names)
+	asserts.Error(err)
+	asserts.Equal(fmt.Sprintf(orm.ErrPluckPtr, "orm_test.Animal"), err.Error())
+
+	// error - field does not exist.
+	err = animal.Pluck("NotExisting", &names)
+	asserts.Error(err)
+	asserts.Equal(fmt.Sprintf(orm.ErrFieldName, "orm_test.Animal:NotExisting"), err.Error())
+
+	// error - type is not compatible.
+	var times []time.Time
+	err = animal.Pluck("Name", &times)
+	asserts.Error(err)
+	asserts.Equal(fmt.Sprintf(orm.ErrPluckType, "time.Time", "string", "orm_test.Animal:Name"), err.Error())
+	var idNames []string
+	err = animal.Pluck("ID", &idNames)
+	asserts.Error(err)
+	asserts.Equal(fmt.Sprintf(orm.ErrPluckType, "string", "int", "orm_test.Animal:ID"), err.Error())
+}
@@ -5,6 +5,7 @@
 package orm
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/patrickascher/gofer/slicer"
@@ -268,3 +269,86 @@ func mandatoryKeys(scope scope, p Permission, relation Relation, fields []string
 
 	return rv, nil
 }
+
+// withPermissionList returns a permission list which only allows the given relations.
+// Dotted paths are allowed, in that case only the listed child relations of the parent are loaded.
+// All other relations are added to the blacklist or removed from an existing whitelist.
+// Error will return if a relation does not exist.
+func withPermissionList(scope Scope, list *permissionList, relations []string) (*permissionList, error) {
+	matched := map[string]bool{}
+	excluded, err := excludedRelations(scope, "", relations, matched)
+	if err != nil {
+		return nil, err
+	}
+	for _, relation := range relations {
+		if !matched[relation] {
+			return nil, fmt.Errorf(ErrFieldName, scope.Name(true)+":"+relation)
+		}
+	}
+
+	// no permission list is defined.
+	if list == nil {
+		return newPermissionList(BLACKLIST, excluded), nil
+	}
+
+	// whitelist - remove all excluded relations.
+	if list.policy == WHITELIST {
+		var fields []string
+	whitelist:
+		for _, field := range list.fields {
+			for _, relation := range excluded {
+				if field == relation || strings.HasPrefix(field, relation+".") {
+					continue whitelist
+				}
+			}
+			fields = append(fields, field)
+		}
+		return newPermissionList(WHITELIST, fields), nil
+	}
+
+	// blacklist - add the excluded relations.
+	return newPermissionList(BLACKLIST, slicer.StringUnique(append(append([]string{}, list.fields...), excluded...))), nil
+}
+
+// excludedRelations is a helper to return all relation paths of the scope which are not requested.
+// Matched paths are added to the matched map.
+func excludedRelations(scope Scope, prefix string, relations []string, matched map[string]bool) ([]string, error) {
+	var excluded []string
+	for _, relation := range scope.Relations(Permission{}) {
+		path := prefix + relation.Field
+
+		listed, nested := false, false
+		for _, r := range relations {
+			if r == path {
+				listed = true
+			}
+			if strings.HasPrefix(r, path+".") {
+				nested = true
+			}
+		}
+		if !listed && !nested {
+			excluded = append(excluded, path)
+			continue
+		}
+		matched[path] = true
+
+		// the relation was skipped on Init.
+		if relation.Unavailable != nil {
+			return nil, fmt.Errorf(ErrUnavailable, scope.Name(true)+":"+path, relation.Unavailable)
+		}
+
+		// only the listed child relations will be loaded.
+		if nested {
+			relScope, err := scope.NewScopeFromType(relation.Type)
+			if err != nil {
+				return nil, err
+			}
+			childExcluded, err := excludedRelations(relScope, path+".", relations, matched)
+			if err != nil {
+				return nil, err
+			}
+			excluded = append(excluded, childExcluded...)
+		}
+	}
+	return excluded, nil
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2021 Patrick Ascher <development@fullhouse-productions.com>. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package orm
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+
+	"github.com/patrickascher/gofer/registry"
+)
+
+// prefixPKGenerator is a internal prefix for the primary key generator registration.
+const prefixPKGenerator = "orm_pk_"
+
+// Error messages.
+var (
+	ErrPKGenerator = "orm: generated primary key type %s can not be set to the field %s (%s)"
+)
+
+// RegisterPKGenerator registers a primary key generator for the given model (package.Struct, e.g. "models.Animal").
+// The generator is called on create if the primary key is zero. The database value (autoincrement, uuid) is not used then.
+// On composite primary keys, only the first primary key field is generated.
+// The generated value must be assignable to the primary key field. Integers are converted to the field type.
+// Error will return if a generator is already registered for the model.
+func RegisterPKGenerator(model string, fn func() interface{}) error {
+	if fn == nil {
+		return registry.ErrMandatoryArguments
+	}
+	return registry.Set(prefixPKGenerator+model, fn)
+}
+
+// pkGenerator returns the registered primary key generator of the scope.
+// False will return if none is registered.
+func pkGenerator(scope Scope) (func() interface{}, bool) {
+	fn, err := registry.Get(prefixPKGenerator + scope.Name(true))
+	if err != nil {
+		return nil, false
+	}
+	return fn.(func() interface{}), true
+}
+
+// setGeneratedPK is a helper to set the generated value to the primary key field.
+// Error will return if the value type does not match the field type.
+func setGeneratedPK(field reflect.Value, name string, value interface{}) error {
+	v := reflect.ValueOf(value)
+	switch {
+	case !v.IsValid():
+		return fmt.Errorf(ErrPKGenerator, "nil", name, field.Type())
+	case v.Type().AssignableTo(field.Type()):
+	case isIntegerKind(v.Kind()) && isIntegerKind(field.Kind()):
+		v = v.Convert(field.Type())
+	case field.Kind() == reflect.Struct && field.Addr().Type().Implements(reflect.TypeOf((*sql.Scanner)(nil)).Elem()):
+	default:
+		return fmt.Errorf(ErrPKGenerator, v.Type(), name, field.Type())
+	}
+	return SetReflectValue(field, v)
+}
+
+// isIntegerKind returns true if the kind is a signed or unsigned integer.
+func isIntegerKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Uint64
+}
@@ -6,9 +6,11 @@ package orm
 
 import (
 	"fmt"
+	"log"
 	"reflect"
 	"strings"
 
+	"github.com/patrickascher/gofer/query"
 	"github.com/patrickascher/gofer/stringer"
 	"github.com/patrickascher/gofer/structer"
 )
@@ -18,7 +20,7 @@ var (
 	ErrRelationKind = "orm: relation kind %s is not allowed on field type %s (%s)"
 	ErrRelationType = "orm: relation type %s is not allowed (%s)"
 	ErrPolymorphic  = "orm: polymorphism is only available on HasOne, HasMany and ManyToMany(not self-referencing) (%s)"
-	ErrJoinTable    = "orm: required join table columns are not existing %s in %s"
+	ErrJoinTable    = "orm: join table %s is missing the column(s) %s, available columns: %s"
 )
 
 // tag definitions.
@@ -50,10 +52,38 @@ type Relation struct {
 	NoSQLColumn bool
 	Permission  Permission
 	Validator   validator
+	Unavailable error // set if the relation was skipped on Init (SkipMissingRelations).
 
 	Mapping Mapping
 }
 
+// RelationError wraps an error of a relation load or write.
+// The model and field name can be accessed by errors.As, the underlying error by errors.Is or errors.Unwrap.
+type RelationError struct {
+	Model string
+	Field string
+	Err   error
+}
+
+// Error returns the error message with the relation path.
+func (e *RelationError) Error() string {
+	return fmt.Errorf(ErrNoRows, e.Model+":"+e.Field, e.Err).Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *RelationError) Unwrap() error {
+	return e.Err
+}
+
+// relationError is a helper to wrap the error of a relation.
+// Nil will return if the error is nil.
+func relationError(scope Scope, relation Relation, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &RelationError{Model: scope.Name(true), Field: relation.Field, Err: err}
+}
+
 // IsPolymorphic returns true if a polymorphic was defined for this relation.
 func (r Relation) IsPolymorphic() bool {
 	return r.Mapping.Polymorphic.Value != ""
@@ -107,6 +137,8 @@ type Join struct {
 // - fk will be the first primary key of the struct model (example: {Post.ID})
 // - refs will be the first primary key of the relation model. (example: {Comment.ID})
 // - join table name will be the model name + relation model name in snake style and plural. The column names will be struct name + primary key of the models. (Example: table: post_comments, column_fk: post_id, column_refs: refs_id)
+// 		The join table name can be customized by implementing the NamingStrategy interface on the model. An empty name keeps the default.
+// 		Generated names longer than IdentifierMaxLength are truncated to a prefix and a short hash.
 // - poly must be set manually.
 // 		if a poly is set a additional type column is required in the junction table.
 // 		Example: Post, Video, Tag. Post and video can both have tags.
@@ -125,6 +157,10 @@ func (m *Model) createRelations(structRelations []reflect.StructField) error {
 		if err != nil {
 			return err
 		}
+		ft, err := parseFieldTags(structRelation, m.scope.FqdnModel)
+		if err != nil {
+			return err
+		}
 
 		// creating relation with defaults.
 		relation := Relation{}
@@ -133,10 +169,10 @@ func (m *Model) createRelations(structRelations []reflect.StructField) error {
 		relation.Type = structRelation.Type
 		relation.Permission = Permission{Read: true, Write: true}
 		relation.Validator = validator{}
-		relation.Validator.SetConfig(structRelation.Tag.Get(TagValidate))
+		relation.Validator.SetConfig(ft.Validate)
 
 		// custom field
-		if _, ok := tags[tagNoSQLField]; ok {
+		if ft.Custom {
 			relation.NoSQLColumn = true
 		} else {
 
@@ -151,6 +187,9 @@ func (m *Model) createRelations(structRelations []reflect.StructField) error {
 				relModel.setParent(m)
 				err = relModel.Init(relModel)
 				if err != nil {
+					if m.skipMissingRelation(relation, err) {
+						continue
+					}
 					return err
 				}
 			}
@@ -205,20 +244,26 @@ func (m *Model) createRelations(structRelations []reflect.StructField) error {
 				// Join table
 				j := Join{}
 				j.Table = stringer.CamelToSnake(stringer.Plural(m.scope.Name(false) + relScope.Name(false)))
+				if n, ok := m.caller.(NamingStrategy); ok {
+					if name := n.JoinTableName(m.scope.Name(false), relScope.Name(false)); name != "" {
+						j.Table = name
+					}
+				}
 
 				// join fk
 				j.ForeignColumnName = stringer.CamelToSnake(stringer.Singular(m.scope.Name(false)) + fk.Name)
 
 				// if poly is set
-				if v, ok := tags[tagPolymorphic]; ok {
+				if ft.Polymorphic {
+					v := ft.PolymorphicName
 					// error on self reference & poly
 					if m.isSelfReferencing(relation.Type) {
 						return fmt.Errorf(ErrPolymorphic, m.scope.FqdnModel(relation.Field))
 					}
 					// set type value
 					poly.Value = m.scope.Name(false)
-					if v, ok := tags[tagPolymorphicValue]; ok && v != "" {
-						poly.Value = v
+					if ft.PolymorphicValue != "" {
+						poly.Value = ft.PolymorphicValue
 					}
 					// poly is not set
 					if v == "" {
@@ -231,23 +276,28 @@ func (m *Model) createRelations(structRelations []reflect.StructField) error {
 						poly.TypeField.Information.Name = stringer.CamelToSnake(v + "Type")
 					}
 				}
+				// truncate the generated names.
+				j.Table = truncateIdentifier(j.Table)
+				j.ForeignColumnName = truncateIdentifier(j.ForeignColumnName)
+				poly.TypeField.Information.Name = truncateIdentifier(poly.TypeField.Information.Name)
+
 				// set join table by tag
-				if v, ok := tags[tagJoinTable]; ok && v != "" {
-					j.Table = v
+				if ft.JoinTable != "" {
+					j.Table = ft.JoinTable
 				}
 				// set join fk by tag
-				if v, ok := tags[tagJoinFk]; ok && v != "" {
-					j.ForeignColumnName = v
+				if ft.JoinForeignKey != "" {
+					j.ForeignColumnName = ft.JoinForeignKey
 				}
 
 				// join refs
 				if m.isSelfReferencing(relation.Type) {
 					j.ReferencesColumnName = "child_id"
 				} else {
-					j.ReferencesColumnName = stringer.CamelToSnake(stringer.Singular(relScope.Name(false)) + refs.Name)
+					j.ReferencesColumnName = truncateIdentifier(stringer.CamelToSnake(stringer.Singular(relScope.Name(false)) + refs.Name))
 				}
-				if v, ok := tags[tagJoinRefs]; ok && v != "" {
-					j.ReferencesColumnName = v
+				if ft.JoinReferences != "" {
+					j.ReferencesColumnName = ft.JoinReferences
 				}
 
 				// checking if join table and fields exist.
@@ -257,12 +307,15 @@ func (m *Model) createRelations(structRelations []reflect.StructField) error {
 					requiredColumns = append(requiredColumns, poly.TypeField.Information.Name)
 				}
 				cols, err := m.builder.Query().Information(j.Table).Describe(requiredColumns...)
+				if err == nil && len(cols) != len(requiredColumns) {
+					err = m.joinTableError(j.Table, requiredColumns, cols)
+				}
 				if err != nil {
+					if m.skipMissingRelation(relation, err) {
+						continue
+					}
 					return err
 				}
-				if len(cols) != len(requiredColumns) {
-					return fmt.Errorf(ErrJoinTable, requiredColumns, j.Table)
-				}
 
 				relation.Mapping.Join = j
 			}
@@ -274,15 +327,8 @@ func (m *Model) createRelations(structRelations []reflect.StructField) error {
 		}
 
 		// parse tags
-		if v, ok := tags[tagPermission]; ok {
-			relation.Permission.Read = false
-			relation.Permission.Write = false
-			if strings.Contains(v, "r") {
-				relation.Permission.Read = true
-			}
-			if strings.Contains(v, "w") {
-				relation.Permission.Write = true
-			}
+		if ft.Permission != nil {
+			relation.Permission = *ft.Permission
 		}
 
 		// add relation
@@ -296,31 +342,23 @@ func (m *Model) createRelations(structRelations []reflect.StructField) error {
 // Error will return if the type is not allowed or supported.
 func (m *Model) relationKind(tags map[string]string, field reflect.StructField) (string, error) {
 
-	// check if tag is set and valid
-	if tagRel, ok := tags[tagRelation]; ok {
-		if !isTagRelationAllowed(field, tagRel) {
-			return "", fmt.Errorf(ErrRelationKind, tagRel, field.Type.Kind().String(), m.scope.FqdnModel(field.Name))
-		}
-		return tagRel, nil
+	// the same rules as ParseFieldTags are used.
+	kind, byTag, err := fieldRelationKind(field, tags, m.scope.FqdnModel)
+	if err != nil {
+		return "", err
 	}
 
-	// default kinds
-	switch field.Type.Kind() {
-	case reflect.Struct:
-		return HasOne, nil
-	case reflect.Ptr:
-		if field.Type.Elem().Kind() == reflect.Struct {
-			return HasOne, nil
-		}
-		if field.Type.Elem().Kind() == reflect.Slice {
-			return m.sliceType(field)
-		}
-	case reflect.Slice:
+	// error field type is not supported.
+	if kind == "" {
+		return "", fmt.Errorf(ErrRelationType, field.Type.Kind(), m.scope.FqdnModel(field.Name))
+	}
+
+	// default slices are ManyToMany on self referencing.
+	if !byTag && kind == HasMany {
 		return m.sliceType(field)
 	}
 
-	// error field type is not supported.
-	return "", fmt.Errorf(ErrRelationType, field.Type.Kind(), m.scope.FqdnModel(field.Name))
+	return kind, nil
 }
 
 // sliceType is a helper to return the default relation kind.
@@ -361,3 +399,40 @@ func isTagRelationAllowed(field reflect.StructField, relKind string) bool {
 
 	return false
 }
+
+// joinTableError is a helper to create an error with the missing join table columns and all available columns of the table.
+func (m *Model) joinTableError(table string, required []string, cols []query.Column) error {
+	var missing []string
+Required:
+	for _, name := range required {
+		for _, col := range cols {
+			if col.Name == name {
+				continue Required
+			}
+		}
+		missing = append(missing, name)
+	}
+
+	all, err := m.builder.Query().Information(table).Describe()
+	if err != nil {
+		return err
+	}
+	available := make([]string, len(all))
+	for i, col := range all {
+		available[i] = col.Name
+	}
+
+	return fmt.Errorf(ErrJoinTable, table, strings.Join(missing, ", "), strings.Join(available, ", "))
+}
+
+// skipMissingRelation is a helper to add the relation as unavailable if the SkipMissingRelations config is set.
+// The error will be logged. False will return if the config is not set.
+func (m *Model) skipMissingRelation(relation Relation, err error) bool {
+	if !defaultConfig.skipMissingRelations && !m.config[RootStruct].skipMissingRelations {
+		return false
+	}
+	log.Printf("orm: relation %s skipped: %s", m.scope.FqdnModel(relation.Field), err)
+	relation.Unavailable = err
+	m.relations = append(m.relations, relation)
+	return true
+}
@@ -303,7 +303,7 @@ func TestModel_createRelation(t *testing.T) {
 		{typeName: "[]orm.RelationTests", relation: Relation{Field: "ManyToManyPolyTag", Kind: ManyToMany, NoSQLColumn: false, Permission: Permission{Read: true, Write: true}, Validator: validator{config: nil}, Mapping: Mapping{ForeignKey: Field{Name: "ID"}, References: Field{Name: "ID"}, Polymorphic: Polymorphic{Field{Information: query.Column{Name: "tag_type"}}, "OrmRel"}, Join: Join{Table: "relation_tests_tags", ReferencesColumnName: "relation_test_id", ForeignColumnName: "tag_id"}}}},
 		{typeName: "[]orm.RelationTests", relation: Relation{Field: "ManyToManyPolyTagValue", Kind: ManyToMany, NoSQLColumn: false, Permission: Permission{Read: true, Write: true}, Validator: validator{config: nil}, Mapping: Mapping{ForeignKey: Field{Name: "ID"}, References: Field{Name: "ID"}, Polymorphic: Polymorphic{Field{Information: query.Column{Name: "tag_type"}}, "ORM"}, Join: Join{Table: "relation_tests_tags", ReferencesColumnName: "relation_test_id", ForeignColumnName: "tag_id"}}}},
 		{typeName: "[]orm.RelationTests", relation: Relation{Field: "ManyToManyPolyTags", Kind: ManyToMany, NoSQLColumn: false, Permission: Permission{Read: true, Write: true}, Validator: validator{config: nil}, Mapping: Mapping{ForeignKey: Field{Name: "ID"}, References: Field{Name: "ID"}, Polymorphic: Polymorphic{Field{Information: query.Column{Name: "tag_type"}}, "ORM"}, Join: Join{Table: "jtable", ReferencesColumnName: "tableRefs", ForeignColumnName: "tableFK"}}}},
-		{error: true, errMsg: fmt.Sprintf(ErrJoinTable, []string{"notExisting", "tableRefs"}, "jtable"), typeName: "[]orm.RelationTests", relation: Relation{Field: "ManyToManyJoinTagsErr", Kind: ManyToMany, NoSQLColumn: false, Permission: Permission{Read: true, Write: true}, Validator: validator{config: nil}, Mapping: Mapping{ForeignKey: Field{Name: "ID"}, References: Field{Name: "ID"}, Polymorphic: Polymorphic{Field{Information: query.Column{Name: "tag_type"}}, "ORM"}, Join: Join{Table: "jtable", ReferencesColumnName: "tableRefs", ForeignColumnName: "tag_id"}}}},
+		{error: true, errMsg: fmt.Sprintf(ErrJoinTable, "jtable", "notExisting", "tag_id, tableRefs, tag_type"), typeName: "[]orm.RelationTests", relation: Relation{Field: "ManyToManyJoinTagsErr", Kind: ManyToMany, NoSQLColumn: false, Permission: Permission{Read: true, Write: true}, Validator: validator{config: nil}, Mapping: Mapping{ForeignKey: Field{Name: "ID"}, References: Field{Name: "ID"}, Polymorphic: Polymorphic{Field{Information: query.Column{Name: "tag_type"}}, "ORM"}, Join: Join{Table: "jtable", ReferencesColumnName: "tableRefs", ForeignColumnName: "tag_id"}}}},
 		{error: true, errMsg: "an error", typeName: "[]orm.RelationTests", relation: Relation{Field: "ManyToManyErrDescribe", Kind: ManyToMany, NoSQLColumn: false, Permission: Permission{Read: true, Write: true}, Validator: validator{config: nil}, Mapping: Mapping{ForeignKey: Field{Name: "ID"}, References: Field{Name: "ID"}, Polymorphic: Polymorphic{Field{Information: query.Column{Name: "tag_type"}}, "ORM"}, Join: Join{Table: "jtable", ReferencesColumnName: "tableRefs", ForeignColumnName: "tag_id"}}}},
 
 		{error: true, errMsg: fmt.Sprintf(ErrFieldName, "orm.ormRel:NotExisting"), typeName: "*[]*orm.RelationTests", relation: Relation{Field: "ManyToManyTagFkErr", Kind: ManyToMany, NoSQLColumn: false, Permission: Permission{Read: true, Write: true}, Validator: validator{config: nil}, Mapping: Mapping{ForeignKey: Field{Name: "ID"}, References: Field{Name: "ID"}, Polymorphic: Polymorphic{}}}},
@@ -441,6 +441,206 @@ func TestModel_createRelationSelfRef(t *testing.T) {
 	}
 }
 
+// TestModel_createRelationNamingStrategy tests:
+// - If the join table name of the NamingStrategy is used.
+// - If the default join table name is kept if the NamingStrategy returns an empty name.
+func TestModel_createRelationNamingStrategy(t *testing.T) {
+	asserts := assert.New(t)
+
+	model := Model{caller: &legacyRoles{}}
+	model.scope.model = &model
+	model.builder = model.caller.DefaultBuilder()
+	model.fields = append(model.fields, Field{Name: "ID", Information: query.Column{Name: "id", PrimaryKey: true, Type: types.NewInt("int")}})
+	field, exists := reflect.TypeOf(legacyRoles{}).FieldByName("Roles")
+	asserts.True(exists)
+	err := model.createRelations([]reflect.StructField{field})
+	asserts.NoError(err)
+	asserts.Equal(1, len(model.relations))
+	asserts.Equal("role_links", model.relations[0].Mapping.Join.Table)
+	asserts.Equal("legacy_role_id", model.relations[0].Mapping.Join.ForeignColumnName)
+	asserts.Equal("child_id", model.relations[0].Mapping.Join.ReferencesColumnName)
+
+	// default name is kept.
+	model = Model{caller: &unnamedRoles{}}
+	model.scope.model = &model
+	model.builder = model.caller.DefaultBuilder()
+	model.fields = append(model.fields, Field{Name: "ID", Information: query.Column{Name: "id", PrimaryKey: true, Type: types.NewInt("int")}})
+	field, exists = reflect.TypeOf(unnamedRoles{}).FieldByName("Roles")
+	asserts.True(exists)
+	err = model.createRelations([]reflect.StructField{field})
+	asserts.NoError(err)
+	asserts.Equal(1, len(model.relations))
+	asserts.Equal("unnamed_roles_unnamed_roles", model.relations[0].Mapping.Join.Table)
+}
+
+type legacyRoles struct {
+	Model
+	ID    int
+	Roles []legacyRoles
+}
+
+func (r *legacyRoles) JoinTableName(modelA, modelB string) string {
+	if modelA == "LegacyRoles" && modelB == "LegacyRoles" {
+		return "role_links"
+	}
+	return ""
+}
+
+func (r *legacyRoles) DefaultCache() (cache.Manager, time.Duration) {
+	mCache := new(mockCache.Manager)
+	mCache.On("Exist", mock.Anything, mock.Anything).Return(false)
+	mCache.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	return mCache, 0
+}
+
+func (r *legacyRoles) DefaultBuilder() query.Builder {
+	mBuilder := new(mockBuilder.Builder)
+	mProvider := new(mockBuilder.Provider)
+	mInformation := new(mockBuilder.Information)
+
+	mBuilder.On("Config").Return(query.Config{Database: "tests"})
+	mBuilder.On("Query").Return(mProvider)
+
+	mProvider.On("Information", "legacy_roles").Return(mInformation)
+	cols := []query.Column{
+		{Name: "id", PrimaryKey: true, Type: types.NewInt("int")},
+	}
+	mInformation.On("Describe", "id", "created_at", "updated_at", "deleted_at").Return(cols, nil)
+
+	// custom join table
+	mJoinInformation := new(mockBuilder.Information)
+	mProvider.On("Information", "role_links").Return(mJoinInformation)
+	cols = []query.Column{
+		{Name: "legacy_role_id", PrimaryKey: true, Type: types.NewInt("int")},
+		{Name: "child_id", Type: types.NewInt("int")},
+	}
+	mJoinInformation.On("Describe", "legacy_role_id", "child_id").Return(cols, nil)
+
+	return mBuilder
+}
+
+type unnamedRoles struct {
+	Model
+	ID    int
+	Roles []unnamedRoles
+}
+
+func (r *unnamedRoles) JoinTableName(modelA, modelB string) string {
+	return ""
+}
+
+func (r *unnamedRoles) DefaultCache() (cache.Manager, time.Duration) {
+	mCache := new(mockCache.Manager)
+	mCache.On("Exist", mock.Anything, mock.Anything).Return(false)
+	mCache.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	return mCache, 0
+}
+
+func (r *unnamedRoles) DefaultBuilder() query.Builder {
+	mBuilder := new(mockBuilder.Builder)
+	mProvider := new(mockBuilder.Provider)
+	mInformation := new(mockBuilder.Information)
+
+	mBuilder.On("Config").Return(query.Config{Database: "tests"})
+	mBuilder.On("Query").Return(mProvider)
+
+	mProvider.On("Information", "unnamed_roles").Return(mInformation)
+	cols := []query.Column{
+		{Name: "id", PrimaryKey: true, Type: types.NewInt("int")},
+	}
+	mInformation.On("Describe", "id", "created_at", "updated_at", "deleted_at").Return(cols, nil)
+
+	// default join table
+	mJoinInformation := new(mockBuilder.Information)
+	mProvider.On("Information", "unnamed_roles_unnamed_roles").Return(mJoinInformation)
+	cols = []query.Column{
+		{Name: "unnamed_role_id", PrimaryKey: true, Type: types.NewInt("int")},
+		{Name: "child_id", Type: types.NewInt("int")},
+	}
+	mJoinInformation.On("Describe", "unnamed_role_id", "child_id").Return(cols, nil)
+
+	return mBuilder
+}
+
+// TestModel_createRelationTruncate tests:
+// - If a too long generated join table name is truncated and used for the describe.
+func TestModel_createRelationTruncate(t *testing.T) {
+	asserts := assert.New(t)
+
+	model := Model{caller: &longRoles{}}
+	model.scope.model = &model
+	model.builder = model.caller.DefaultBuilder()
+	model.fields = append(model.fields, Field{Name: "ID", Information: query.Column{Name: "id", PrimaryKey: true, Type: types.NewInt("int")}})
+	field, exists := reflect.TypeOf(longRoles{}).FieldByName("Roles")
+	asserts.True(exists)
+	err := model.createRelations([]reflect.StructField{field})
+	asserts.NoError(err)
+	asserts.Equal(1, len(model.relations))
+	asserts.Equal("legacy_roles_with_a_very_long_descriptive_name_legacy_r_f5db56aa", model.relations[0].Mapping.Join.Table)
+	asserts.Equal(IdentifierMaxLength, len(model.relations[0].Mapping.Join.Table))
+}
+
+// TestTruncateIdentifier tests:
+// - If short names are not changed.
+// - If long names are truncated deterministic.
+// - If the truncation can be disabled.
+func TestTruncateIdentifier(t *testing.T) {
+	asserts := assert.New(t)
+
+	long := "legacy_roles_with_a_very_long_descriptive_name_legacy_roles_with_a_very_long_descriptive_names"
+	asserts.Equal("role_links", truncateIdentifier("role_links"))
+	asserts.Equal("legacy_roles_with_a_very_long_descriptive_name_legacy_r_f5db56aa", truncateIdentifier(long))
+	asserts.Equal(truncateIdentifier(long), truncateIdentifier(long))
+	asserts.NotEqual(truncateIdentifier(long), truncateIdentifier(long+"s"))
+
+	defer func(l int) { IdentifierMaxLength = l }(IdentifierMaxLength)
+	IdentifierMaxLength = 0
+	asserts.Equal(long, truncateIdentifier(long))
+}
+
+type longRoles struct {
+	Model
+	ID    int
+	Roles []longRoles
+}
+
+func (r *longRoles) JoinTableName(modelA, modelB string) string {
+	return "legacy_roles_with_a_very_long_descriptive_name_legacy_roles_with_a_very_long_descriptive_names"
+}
+
+func (r *longRoles) DefaultCache() (cache.Manager, time.Duration) {
+	mCache := new(mockCache.Manager)
+	mCache.On("Exist", mock.Anything, mock.Anything).Return(false)
+	mCache.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	return mCache, 0
+}
+
+func (r *longRoles) DefaultBuilder() query.Builder {
+	mBuilder := new(mockBuilder.Builder)
+	mProvider := new(mockBuilder.Provider)
+	mInformation := new(mockBuilder.Information)
+
+	mBuilder.On("Config").Return(query.Config{Database: "tests"})
+	mBuilder.On("Query").Return(mProvider)
+
+	mProvider.On("Information", "long_roles").Return(mInformation)
+	cols := []query.Column{
+		{Name: "id", PrimaryKey: true, Type: types.NewInt("int")},
+	}
+	mInformation.On("Describe", "id", "created_at", "updated_at", "deleted_at").Return(cols, nil)
+
+	// truncated join table
+	mJoinInformation := new(mockBuilder.Information)
+	mProvider.On("Information", "legacy_roles_with_a_very_long_descriptive_name_legacy_r_f5db56aa").Return(mJoinInformation)
+	cols = []query.Column{
+		{Name: "long_role_id", Type: types.NewInt("int")},
+		{Name: "child_id", Type: types.NewInt("int")},
+	}
+	mJoinInformation.On("Describe", "long_role_id", "child_id").Return(cols, nil)
+
+	return mBuilder
+}
+
 type rolesErr struct {
 	Model
 
@@ -683,6 +883,12 @@ func (r *ormRel) DefaultBuilder() query.Builder {
 		{Name: "tableRefs", Type: types.NewInt("int")},
 	}
 	mInformation.On("Describe", "notExisting", "tableRefs").Return(cols, nil)
+	cols = []query.Column{
+		{Name: "tag_id", Type: types.NewInt("int")},
+		{Name: "tableRefs", Type: types.NewInt("int")},
+		{Name: "tag_type", Type: types.NewInt("int")},
+	}
+	mInformation.On("Describe").Return(cols, nil)
 
 	// describe err
 	mInformation.On("Describe", "errDescribe", "tableRefs").Return(nil, errors.New("an error"))
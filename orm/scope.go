@@ -5,11 +5,13 @@
 package orm
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"fmt"
 	"reflect"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -27,9 +29,13 @@ var (
 	ErrFieldType      = "orm: field type %s is not allowed (%s). Fields must include default go types or implement the sql.Scanner/driver.Valuer or relations must implement the orm.Interface or set as custom"
 	ErrFieldName      = "orm: field/relation (%s) does not exist or does not have the required permission"
 	ErrFieldUnique    = "orm: field name (%s) is not unique"
+	ErrFieldNotUnique = "orm: field (%s) is not defined as unique"
+	ErrUniqueTogether = "orm: %w of the columns (%s) in %s"
+	ErrFieldOrder     = "orm: order tag value (%s) must be an integer (%s)"
 	ErrPrimaryKey     = "orm: no primary key is defined in %s"
 	ErrMaxSearchDepth = "orm: the max parent search depth of %d was reached (%s)"
 	ErrInfinityLoop   = "orm: 🎉 congratulation you created an infinity loop (%s)"
+	ErrPreload        = "orm: parents must be a slice of %s (PreloadRelations)"
 )
 
 // Scope provide some useful helper functions for the orm.Model.
@@ -40,7 +46,9 @@ type Scope interface {
 	FqdnTable() string
 	FqdnModel(string) string
 	Model() *Model
+	Clone() (Scope, error)
 	Caller() Interface
+	Context() context.Context
 
 	Cache() cache.Manager
 	SetCache(mgr cache.Manager) error
@@ -48,17 +56,24 @@ type Scope interface {
 	SQLFields(permission Permission) []Field
 	Fields(permission Permission) []Field
 	SQLScanFields(permission Permission) []interface{}
+	SQLScanFieldsByColumns(columns []string) []interface{}
 	SQLColumns(permission Permission) []string
 
 	Field(name string) (*Field, error)
+	ColumnKind(column string) (string, bool)
 	FieldValue(name string) reflect.Value
 
+	HasField(name string) bool
+	HasRelation(name string) bool
 	SQLRelation(relation string, permission Permission) (Relation, error)
 	SQLRelations(permission Permission) []Relation
 	Relations(permission Permission) []Relation
+	LoadedRelations() []string
+	Diagnostics() OrmDiagnostics
 
 	PrimaryKeys() ([]Field, error)
 	PrimaryKeysSet() bool
+	IsUnique(field string) (bool, error)
 
 	// internals
 	foreignKey(tag string, tags map[string]string) (Field, error)
@@ -70,6 +85,8 @@ type Scope interface {
 	InitRelation(relation Interface, field string) error
 	SetBackReference(Relation) error
 	NewScopeFromType(reflect.Type) (Scope, error)
+	PreloadRelations(parents interface{}, relations ...string) error
+	CountRelation(relation string, c condition.Condition) (int, error)
 
 	// experimental
 	Config(...string) config
@@ -115,11 +132,70 @@ func (s scope) SetConfig(c *config, name ...string) {
 	s.model.config[RootStruct] = *c
 }
 
+// Clone will return a copy of the scope with its own caller, fields, relations, configuration and permission list.
+// It can be used to change permissions or configurations per request without affecting the original model.
+// The caller is a new instance with a copy of the struct values.
+// Error will return if the model was not initialized.
+func (s scope) Clone() (Scope, error) {
+	if s.model == nil || s.model.caller == nil {
+		return nil, fmt.Errorf(ErrInit, "scope")
+	}
+
+	// new caller with the struct values of the original.
+	rv := reflect.New(reflect.TypeOf(s.model.caller).Elem())
+	rv.Elem().Set(reflect.ValueOf(s.model.caller).Elem())
+	caller := rv.Interface().(Interface)
+
+	m := caller.model()
+	*m = *s.model
+	m.caller = caller
+	m.snapshotCaller = nil
+	m.stats = nil
+
+	m.fields = make([]Field, len(s.model.fields))
+	for i, f := range s.model.fields {
+		m.fields[i] = f.copy()
+	}
+	m.relations = make([]Relation, len(s.model.relations))
+	for i, r := range s.model.relations {
+		r.Validator = r.Validator.copy()
+		r.Mapping.ForeignKey = r.Mapping.ForeignKey.copy()
+		r.Mapping.References = r.Mapping.References.copy()
+		r.Mapping.Polymorphic.TypeField = r.Mapping.Polymorphic.TypeField.copy()
+		m.relations[i] = r
+	}
+	m.config = make(map[string]config, len(s.model.config))
+	for name, c := range s.model.config {
+		m.config[name] = c.copy()
+	}
+	if s.model.permissionList != nil {
+		pl := *s.model.permissionList
+		pl.fields = append([]string(nil), pl.fields...)
+		m.permissionList = &pl
+	}
+	m.with = append([]string(nil), s.model.with...)
+	m.scope = scope{model: m}
+
+	return &m.scope, nil
+}
+
 // Model will return the scopes orm model.
 func (s scope) Model() *Model {
 	return s.model
 }
 
+// Context will return the context of the root orm model.
+// If no context was set, context.Background will return.
+func (s scope) Context() context.Context {
+	if root, err := s.Parent(RootStruct); err == nil && root.ctx != nil {
+		return root.ctx
+	}
+	if s.model.ctx != nil {
+		return s.model.ctx
+	}
+	return context.Background()
+}
+
 // TakeSnapshot will define if a snapshot of the orm model will be taken.
 // This is used mainly in update.
 func (s scope) TakeSnapshot(snapshot bool) {
@@ -226,6 +302,16 @@ func (s *scope) Builder() query.Builder {
 	return s.model.builder
 }
 
+// readOnlyScope wraps the scope and returns a read-only builder.
+type readOnlyScope struct {
+	*scope
+}
+
+// Builder will return the read-only model builder.
+func (s readOnlyScope) Builder() query.Builder {
+	return s.scope.Builder().ReadOnly()
+}
+
 // Cache will return the callers cache.
 // TODO at the moment not in use because the logic changed to DefaultCache. Delete?
 func (s *scope) Cache() cache.Manager {
@@ -242,6 +328,94 @@ func (s *scope) SetCache(cache cache.Manager) error {
 	return nil
 }
 
+// PreloadRelations loads the given relations into already populated parents.
+// Parents must be a slice or a ptr to a slice of the orm model. If no relation is given, all relations are loaded.
+// Each relation is requested once for all parents.
+func (s scope) PreloadRelations(parents interface{}, relations ...string) error {
+	m := s.model
+	if err := m.isInit(); err != nil {
+		return err
+	}
+	m.resetStats()
+
+	slice := reflect.Indirect(reflect.ValueOf(parents))
+	if parents == nil || slice.Kind() != reflect.Slice || strings.TrimPrefix(slice.Type().Elem().String(), "*") != reflectName(m.caller) {
+		return fmt.Errorf(ErrPreload, reflectName(m.caller))
+	}
+
+	if len(relations) > 0 {
+		m.with = relations
+	}
+	restore, err := m.applyWith()
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	err = m.scope.setFieldPermission()
+	if err != nil {
+		return err
+	}
+
+	return m.strategy.Preload(slice.Interface(), &m.scope)
+}
+
+// CountRelation will return the number of relation entries of the current parent without loading them.
+// The given condition is added to the relation query, it can be nil.
+// ManyToMany relations are counted by the junction table, joined with the relation table.
+// Error will return if the relation does not exist or has not the read permission.
+func (s scope) CountRelation(relation string, c condition.Condition) (int, error) {
+	m := s.model
+	if err := m.isInit(); err != nil {
+		return 0, err
+	}
+
+	r, err := s.SQLRelation(relation, Permission{Read: true})
+	if err != nil {
+		return 0, err
+	}
+	rel, err := s.InitRelationByField(r.Field, false)
+	if err != nil {
+		return 0, err
+	}
+	relScope := &rel.model().scope
+	config := relScope.Config()
+
+	cond := condition.New()
+	if c != nil {
+		cond = c.Copy()
+	}
+	value := s.FieldValue(r.Mapping.ForeignKey.Name).Interface()
+
+	if r.Kind != ManyToMany {
+		cond.SetWhere(relScope.Builder().QuoteIdentifier(r.Mapping.References.Information.Name)+" = ?", value)
+		if r.IsPolymorphic() && !config.polymorphicAnyOwner {
+			cond.SetWhere(relScope.Builder().QuoteIdentifier(r.Mapping.Polymorphic.TypeField.Information.Name)+" = ?", polymorphicValue(s.Caller(), r))
+		}
+		return rel.Count(cond)
+	}
+
+	// the junction table is requested with the parent builder, like in the eager strategy.
+	b := m.builder
+	cond.SetJoin(condition.INNER, b.QuoteIdentifier(relScope.FqdnTable()), b.QuoteIdentifier(r.Mapping.Join.Table+"."+r.Mapping.Join.ReferencesColumnName)+" = "+b.QuoteIdentifier(relScope.FqdnTable()+"."+r.Mapping.References.Information.Name))
+	cond.SetWhere(b.QuoteIdentifier(r.Mapping.Join.Table+"."+r.Mapping.Join.ForeignColumnName)+" = ?", value)
+	if r.IsPolymorphic() && !config.polymorphicAnyOwner {
+		cond.SetWhere(b.QuoteIdentifier(r.Mapping.Join.Table+"."+r.Mapping.Polymorphic.TypeField.Information.Name)+" = ?", polymorphicValue(s.Caller(), r))
+	}
+	addSoftDeleteCondition(relScope, config, cond)
+
+	row, err := b.Query(m.tx).Select(r.Mapping.Join.Table).Condition(cond).Columns(query.DbExpr("COUNT(*)")).First()
+	if err != nil {
+		return 0, err
+	}
+	var count int
+	err = row.Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
 // Name will return the orm caller name with or without package prefix.
 func (s scope) Name(ns bool) string {
 	name := s.model.name
@@ -256,11 +430,11 @@ func (s scope) Name(ns bool) string {
 }
 
 // SQLRelations will return all sql relations by the given Permission.
-// Relation(s) which are defined as "custom" or have not the required Permission will not be returned.
+// Relation(s) which are defined as "custom", are unavailable or have not the required Permission will not be returned.
 func (s scope) SQLRelations(p Permission) []Relation {
 	var rv []Relation
 	for _, rel := range s.Relations(p) {
-		if rel.NoSQLColumn || (p.Read && !rel.Permission.Read) || (p.Write && !rel.Permission.Write) {
+		if rel.NoSQLColumn || rel.Unavailable != nil || (p.Read && !rel.Permission.Read) || (p.Write && !rel.Permission.Write) {
 			continue
 		}
 		rv = append(rv, rel)
@@ -279,6 +453,69 @@ func (s scope) Relations(p Permission) []Relation {
 	return rv
 }
 
+// LoadedRelations will return the dotted paths of all readable relations which have a non-zero value.
+// Nested relations are added with their parent path (Toys.Brand).
+// It can be used after a First or All to identify which relations were actually populated.
+func (s scope) LoadedRelations() []string {
+	var rv []string
+	loadedRelations(&s, "", &rv, map[string]bool{})
+	return rv
+}
+
+// loadedRelations is a helper to add the loaded relations of the given scope recursively.
+// The recursion stops at the maxSearchDepth to avoid loops on back-references.
+func loadedRelations(s Scope, prefix string, rv *[]string, added map[string]bool) {
+	if strings.Count(prefix, ".") >= maxSearchDepth {
+		return
+	}
+	for _, relation := range s.Relations(Permission{Read: true}) {
+		v := s.FieldValue(relation.Field)
+		if IsValueZero(v) {
+			continue
+		}
+		v = reflect.Indirect(v)
+		if v.Kind() == reflect.Slice && v.Len() == 0 {
+			continue
+		}
+
+		path := prefix + relation.Field
+		if !added[path] {
+			added[path] = true
+			*rv = append(*rv, path)
+		}
+
+		// add nested relations
+		values := []reflect.Value{v}
+		if v.Kind() == reflect.Slice {
+			values = nil
+			for i := 0; i < v.Len(); i++ {
+				values = append(values, reflect.Indirect(v.Index(i)))
+			}
+		}
+		for _, value := range values {
+			if !value.CanAddr() {
+				continue
+			}
+			if relModel, ok := value.Addr().Interface().(Interface); ok {
+				if relScope, err := relModel.Scope(); err == nil {
+					loadedRelations(relScope, path+".", rv, added)
+				}
+			}
+		}
+	}
+}
+
+// HasRelation returns true if a relation with the given name exists.
+// The permission and custom relations are not checked.
+func (s scope) HasRelation(name string) bool {
+	for _, rel := range s.model.relations {
+		if rel.Field == name {
+			return true
+		}
+	}
+	return false
+}
+
 // SQLRelation will return the requested relation by permission.
 // Relations(s) which are defined as "custom" or have not the required Permission will not be returned.
 // Error will return if the relation does not exist or has not the required permission.
@@ -312,6 +549,26 @@ func (s scope) SQLScanFields(p Permission) []interface{} {
 	return cols
 }
 
+// SQLScanFieldsByColumns is a helper for row.scan.
+// The scan fields are returned in the exact order of the given (projected) columns.
+// Custom fields with a scan tag are scanned by the column alias.
+// Columns which do not belong to a struct field (expressions) will be scanned into a placeholder.
+func (s scope) SQLScanFieldsByColumns(columns []string) []interface{} {
+	fields := s.Fields(Permission{})
+	rv := make([]interface{}, len(columns))
+	for i, column := range columns {
+		name := query.ColumnName(column)
+		rv[i] = new(interface{})
+		for _, field := range fields {
+			if (!field.NoSQLColumn && field.Information.Name == name) || (field.NoSQLColumn && field.ScanColumn == name) {
+				rv[i] = s.FieldValue(field.Name).Addr().Interface()
+				break
+			}
+		}
+	}
+	return rv
+}
+
 // SQLFields will return all sql column fields by the given Permission.
 // Field(s) which are defined as "custom" or have not the required Permission will not be returned.
 func (s scope) SQLFields(p Permission) []Field {
@@ -330,7 +587,8 @@ func (s scope) Fields(p Permission) []Field {
 	var rv []Field
 	for _, field := range s.model.fields {
 		// skip if permission is not permitted.
-		if (p.Read && !field.Permission.Read) || (p.Write && !field.Permission.Write) {
+		// read-only fields are never written.
+		if (p.Read && !field.Permission.Read) || (p.Write && (!field.Permission.Write || field.ReadOnly)) {
 			continue
 		}
 		rv = append(rv, field)
@@ -338,6 +596,17 @@ func (s scope) Fields(p Permission) []Field {
 	return rv
 }
 
+// HasField returns true if a field with the given name exists.
+// The permission and custom fields are not checked.
+func (s scope) HasField(name string) bool {
+	for _, field := range s.model.fields {
+		if field.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 // Field returns a ptr to the struct field by name.
 // Error will return if the field does not exist.
 func (s scope) Field(name string) (*Field, error) {
@@ -349,6 +618,17 @@ func (s scope) Field(name string) (*Field, error) {
 	return nil, fmt.Errorf(ErrFieldName, s.FqdnModel(name))
 }
 
+// ColumnKind returns the sanitized type kind of the given sql column name.
+// It can be used to validate a condition before execution by condition.Validate(c, scope).
+func (s scope) ColumnKind(column string) (string, bool) {
+	for _, field := range s.model.fields {
+		if !field.NoSQLColumn && field.Information.Name == column && field.Information.Type != nil {
+			return field.Information.Type.Kind(), true
+		}
+	}
+	return "", false
+}
+
 // FieldValue returns a reflect.Value of the orm caller struct field.
 // It returns the zero Value if no field was found.
 func (s scope) FieldValue(name string) reflect.Value {
@@ -382,6 +662,99 @@ func (s scope) PrimaryKeysSet() bool {
 	return true
 }
 
+// IsUnique checks if the field value is unique in the database table.
+// If the field has a unique predicate (tag unique:deleted_at IS NULL), it will be added to the condition.
+// If the primary keys are set, the entry itself is excluded.
+// Error will return if the field does not exist or is not defined as unique.
+func (s scope) IsUnique(field string) (bool, error) {
+	f, err := s.Field(field)
+	if err != nil {
+		return false, err
+	}
+	if !f.Information.Unique || f.NoSQLColumn {
+		return false, fmt.Errorf(ErrFieldNotUnique, s.FqdnModel(field))
+	}
+
+	b := s.model.builder
+	c := condition.New().SetWhere(b.QuoteIdentifier(f.Information.Name)+" = ?", s.FieldValue(f.Name).Interface())
+	if f.UniqueWhere != "" {
+		c.SetWhere(f.UniqueWhere)
+	}
+	if err = s.excludeSelf(c); err != nil {
+		return false, err
+	}
+
+	row, err := b.Query(s.model.tx).Select(s.FqdnTable()).Columns(query.DbExpr("COUNT(*)")).Condition(c).First()
+	if err != nil {
+		return false, err
+	}
+	var count int
+	err = row.Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count == 0, nil
+}
+
+// excludeSelf is a helper to exclude the entry itself by its primary keys from the condition.
+// On composite primary keys, the keys are negated as one group NOT (pk1 = ? AND pk2 = ?).
+// Nothing will be added if the primary keys are not set.
+func (s scope) excludeSelf(c condition.Condition) error {
+	if !s.PrimaryKeysSet() {
+		return nil
+	}
+	pKeys, err := s.PrimaryKeys()
+	if err != nil {
+		return err
+	}
+	pkc := condition.New()
+	for _, pk := range pKeys {
+		pkc.SetWhere(s.model.builder.QuoteIdentifier(pk.Information.Name)+" = ?", s.FieldValue(pk.Name).Interface())
+	}
+	not := condition.Not(pkc)
+	if err = not.Error(); err != nil {
+		return err
+	}
+	c.Merge(not)
+	return nil
+}
+
+// checkUniqueTogether checks the defined composite unique keys of the config against the database.
+// If the primary keys are set, the entry itself is excluded.
+// Error will return if a field does not exist or the combination of values already exists (query.ErrUniqueViolation).
+func (s scope) checkUniqueTogether() error {
+	b := s.model.builder
+	for _, fields := range s.Config().uniqueTogether {
+		c := condition.New()
+		var columns []string
+		for _, field := range fields {
+			f, err := s.Field(field)
+			if err != nil {
+				return err
+			}
+			c.SetWhere(b.QuoteIdentifier(f.Information.Name)+" = ?", s.FieldValue(f.Name).Interface())
+			columns = append(columns, f.Information.Name)
+		}
+		if err := s.excludeSelf(c); err != nil {
+			return err
+		}
+
+		row, err := b.Query(s.model.tx).Select(s.FqdnTable()).Columns(query.DbExpr("COUNT(*)")).Condition(c).First()
+		if err != nil {
+			return err
+		}
+		var count int
+		err = row.Scan(&count)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf(ErrUniqueTogether, query.ErrUniqueViolation, strings.Join(columns, ", "), s.Name(true))
+		}
+	}
+	return nil
+}
+
 func (s scope) SetParent(m *Model) {
 	s.model.parentModel = m
 }
@@ -454,6 +827,9 @@ func (s scope) InitRelation(relation Interface, field string) error {
 	// copy the loopDetection, map is referenced by. so all the changes would also be in the parent models.
 	s.copyLoopDetection(relation)
 
+	// stats are shared with the root model.
+	relation.model().stats = s.model.stats
+
 	// TODO better solution - this is breaking on different providers.
 	// TODO mode addAutoTx and commitAutoTx must be rewritten.
 	// add tx if the parent scope has one and its the same builder
@@ -817,6 +1193,26 @@ func (s scope) parseStruct(_struct interface{}) ([]reflect.StructField, []reflec
 
 	}
 
+	// sort fields by the order tag weight. Fields without weight keep the declaration order after the weighted ones.
+	weights := make(map[string]int, len(fields))
+	for _, field := range fields {
+		ft, err := parseFieldTags(field, s.FqdnModel)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ft.Order != nil {
+			weights[field.Name] = *ft.Order
+		}
+	}
+	sort.SliceStable(fields, func(i, j int) bool {
+		wi, iOk := weights[fields[i].Name]
+		wj, jOk := weights[fields[j].Name]
+		if iOk && jOk {
+			return wi < wj
+		}
+		return iOk && !jOk
+	})
+
 	// sort fields that the time fields (createdAt, updatedAt, deletedAt) are at the end.
 	var timeFields []reflect.StructField
 	for i := 0; i < len(fields); i++ {
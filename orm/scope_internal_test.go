@@ -132,6 +132,37 @@ func TestScope_parseStruct(t *testing.T) {
 	asserts.Equal("CustomSlicePtr", rel[4].Name)
 	asserts.Equal("CustomPtr", rel[5].Name)
 
+	// ok: weighted fields are before the unweighted fields.
+	type UserOrder struct {
+		Model
+		Name    string
+		Surname string `orm:"order:2"`
+		Age     int    `orm:"order:1"`
+		Address
+	}
+	userOrder := UserOrder{}
+	userOrder.name = "orm_test.UserOrder"
+	fields, _, err = userOrder.scope.parseStruct(userOrder)
+	asserts.NoError(err)
+	asserts.Equal(9, len(fields))
+	asserts.Equal("Age", fields[0].Name)
+	asserts.Equal("Surname", fields[1].Name)
+	asserts.Equal("Name", fields[2].Name)
+	asserts.Equal("Street", fields[3].Name)
+	asserts.Equal(DeletedAt, fields[8].Name)
+
+	// error: order value is not an integer
+	type UserErrOrder struct {
+		Model
+		Name string `orm:"order:first"`
+	}
+	userErrOrder := UserErrOrder{}
+	userErrOrder.name = "orm_test.UserErrOrder"
+	userErrOrder.scope.model = &Model{caller: &userErrOrder}
+	fields, _, err = userErrOrder.scope.parseStruct(userErrOrder)
+	asserts.Error(err)
+	asserts.Equal(fmt.Sprintf(ErrFieldOrder, "first", "orm.UserErrOrder:Name"), err.Error())
+
 	// error: Street is not unique (error happens on embedded field)
 	user1 := UserErrEmbeddedField{}
 	user1.name = "orm_test.UserErrEmbeddedField"
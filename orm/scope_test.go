@@ -12,6 +12,7 @@ import (
 	"github.com/patrickascher/gofer/cache"
 	"github.com/patrickascher/gofer/cache/mocks"
 	"github.com/patrickascher/gofer/orm"
+	"github.com/patrickascher/gofer/query"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -83,6 +84,35 @@ func TestScope_Cache(t *testing.T) {
 	mCache.AssertExpectations(t)
 }
 
+// TestScope_ReadOnly tests:
+// - error if the orm was not init.
+// - if the read-only scope rejects write statements.
+func TestScope_ReadOnly(t *testing.T) {
+	asserts := assert.New(t)
+
+	mCache := new(mocks.Manager)
+	builder := createTestTable(asserts)
+	testOrm := &OrmIDField{OrmFieldBase: OrmFieldBase{mockCache: mCache, mockCacheTTL: cache.DefaultExpiration, mockBuilder: builder}}
+
+	// error: orm was not init
+	s, err := testOrm.ReadOnlyScope()
+	asserts.Nil(s)
+	asserts.Error(err)
+
+	// ok: write statements are rejected
+	mCache.On("Exist", "orm_", "orm_test.OrmIDField").Once().Return(false)
+	mCache.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Once().Return(nil)
+	err = testOrm.Init(testOrm)
+	asserts.NoError(err)
+	scope, err := testOrm.ReadOnlyScope()
+	asserts.NoError(err)
+	asserts.Equal("orm_test.OrmIDField", scope.Name(true))
+	_, err = scope.Builder().Query().Insert(scope.FqdnTable()).Values([]map[string]interface{}{{"id": 1}}).Exec()
+	asserts.Equal(query.ErrReadOnly, err)
+
+	mCache.AssertExpectations(t)
+}
+
 // TestScope_SQLFields tests:
 // - if internal fields are skipped.
 // - permission is working as expected.
@@ -132,6 +162,34 @@ func TestScope_SQLFields(t *testing.T) {
 	asserts.Equal(orm.DeletedAt, fields[1].Name)
 }
 
+// TestScope_SQLScanFieldsByColumns tests:
+// - if the scan fields are aligned with the projected columns.
+// - if an expression column is scanned into a placeholder.
+func TestScope_SQLScanFieldsByColumns(t *testing.T) {
+	asserts := assert.New(t)
+
+	mCache := new(mocks.Manager)
+	builder := createTestTable(asserts)
+	testOrm := &OrmIDTag{OrmFieldBase: OrmFieldBase{mockCache: mCache, mockCacheTTL: cache.DefaultExpiration, mockBuilder: builder}}
+	mCache.On("Exist", "orm_", "orm_test.OrmIDTag").Once().Return(false)
+	mCache.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Once().Return(nil)
+	err := testOrm.Init(testOrm)
+	asserts.NoError(err)
+
+	scope, err := testOrm.Scope()
+	asserts.NoError(err)
+
+	// ok: computed column between the fields.
+	cols := scope.SQLColumns(orm.Permission{})
+	asserts.Equal(2, len(cols))
+	columns := []string{cols[1], query.DbExpr("1+1 AS computed"), cols[0]}
+	fields := scope.SQLScanFieldsByColumns(columns)
+	asserts.Equal(3, len(fields))
+	asserts.Equal(scope.FieldValue(orm.DeletedAt).Addr().Interface(), fields[0])
+	asserts.IsType(new(interface{}), fields[1])
+	asserts.Equal(scope.FieldValue("IDTag").Addr().Interface(), fields[2])
+}
+
 // TestScope_Field tests:
 // - existing field
 // - change value of existing field (check if a ptr value is returned)
@@ -169,6 +227,89 @@ func TestScope_Field(t *testing.T) {
 	asserts.Nil(f)
 }
 
+// TestScope_Clone tests:
+// - if a changed field permission or validation of the cloned scope does not affect the original scope.
+// - if a changed configuration or permission list of the clone does not affect the original scope.
+// - if the clone has its own caller.
+func TestScope_Clone(t *testing.T) {
+	asserts := assert.New(t)
+
+	mCache := new(mocks.Manager)
+	builder := createTestTable(asserts)
+	testOrm := &OrmIDTag{OrmFieldBase: OrmFieldBase{mockCache: mCache, mockCacheTTL: cache.DefaultExpiration, mockBuilder: builder}}
+	mCache.On("Exist", "orm_", "orm_test.OrmIDTag").Once().Return(false)
+	mCache.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Once().Return(nil)
+	err := testOrm.Init(testOrm)
+	asserts.NoError(err)
+
+	scope, err := testOrm.Scope()
+	asserts.NoError(err)
+	scope.SetConfig(orm.NewConfig().SetShowDeletedRows(true).SetRelationSync("Toys", orm.Merge))
+	testOrm.SetPermissions(orm.WHITELIST, "ID")
+
+	field, err := scope.Field(orm.DeletedAt)
+	asserts.NoError(err)
+	write := field.Permission.Write
+	validation := field.Validator.Config()
+
+	clone, err := scope.Clone()
+	asserts.NoError(err)
+	asserts.Equal(scope.Name(true), clone.Name(true))
+	asserts.Equal(scope.Config(), clone.Config())
+	asserts.False(clone.Caller() == scope.Caller())
+
+	// ok: change the clone
+	field, err = clone.Field(orm.DeletedAt)
+	asserts.NoError(err)
+	field.Permission.Write = !write
+	field.Validator.AddConfig("omitempty")
+	cfg := clone.Config()
+	cfg.SetRelationSync("Toys", orm.Replace)
+	clone.Caller().SetPermissions(orm.BLACKLIST, "Name")
+
+	// ok: original is not affected
+	field, err = scope.Field(orm.DeletedAt)
+	asserts.NoError(err)
+	asserts.Equal(write, field.Permission.Write)
+	asserts.Equal(validation, field.Validator.Config())
+	asserts.Equal(*orm.NewConfig().SetShowDeletedRows(true).SetRelationSync("Toys", orm.Merge), scope.Config())
+	p, fields := testOrm.Permissions()
+	asserts.Equal(orm.WHITELIST, p)
+	asserts.Equal([]string{"ID"}, fields)
+	p, fields = clone.Caller().Permissions()
+	asserts.Equal(orm.BLACKLIST, p)
+	asserts.Equal([]string{"Name"}, fields)
+
+	clone.SetConfig(orm.NewConfig())
+	asserts.NotEqual(scope.Config(), clone.Config())
+}
+
+// TestScope_HasFieldRelation tests:
+// - If existing fields and relations return true.
+// - If missing names return false.
+func TestScope_HasFieldRelation(t *testing.T) {
+	asserts := assert.New(t)
+	helperCreateDatabaseAndTable(asserts)
+
+	animal := Animal{}
+	err := animal.Init(&animal)
+	asserts.NoError(err)
+	scope, err := animal.Scope()
+	asserts.NoError(err)
+
+	// ok: fields
+	asserts.True(scope.HasField("Name"))
+	asserts.True(scope.HasField("SpeciesID"))
+	asserts.False(scope.HasField("Toys"))
+	asserts.False(scope.HasField("NotExisting"))
+
+	// ok: relations
+	asserts.True(scope.HasRelation("Toys"))
+	asserts.True(scope.HasRelation("Walkers"))
+	asserts.False(scope.HasRelation("Name"))
+	asserts.False(scope.HasRelation("NotExisting"))
+}
+
 // TestScope_FieldValue tests:
 // - reflected Value of existing field.
 // - reflected Value of none existing field.
@@ -215,3 +356,37 @@ func TestScope_PrimaryKeys(t *testing.T) {
 	asserts.Equal(1, len(pk))
 	asserts.Equal("ID", pk[0].Name)
 }
+
+// TestScope_IsUnique tests:
+// - If the entry itself is excluded by its composite primary keys.
+// - If an entry which only shares a part of the composite primary key is not excluded.
+func TestScope_IsUnique(t *testing.T) {
+	asserts := assert.New(t)
+	helperCreateDatabaseAndTable(asserts)
+	_, err := builder.Query().DB().Exec("DROP TABLE IF EXISTS `tests`.`bookings`")
+	asserts.NoError(err)
+	_, err = builder.Query().DB().Exec("CREATE TABLE `tests`.`bookings` (`event_id` int(11) unsigned NOT NULL, `seat` varchar(20) NOT NULL, `reference` varchar(20) NOT NULL, PRIMARY KEY (`event_id`, `seat`), UNIQUE KEY `reference` (`reference`)) ENGINE=InnoDB DEFAULT CHARSET=utf8;")
+	asserts.NoError(err)
+	_, err = builder.Query().DB().Exec("INSERT INTO `tests`.`bookings` (`event_id`, `seat`, `reference`) VALUES (1, 'A', 'R1'), (1, 'B', 'R2')")
+	asserts.NoError(err)
+
+	booking := Booking{}
+	err = booking.Init(&booking)
+	asserts.NoError(err)
+	scope, err := booking.Scope()
+	asserts.NoError(err)
+
+	// ok: the entry itself is excluded.
+	booking.EventID = 1
+	booking.Seat = "A"
+	booking.Reference = "R1"
+	unique, err := scope.IsUnique("Reference")
+	asserts.NoError(err)
+	asserts.True(unique)
+
+	// ok: the entry (1, A) shares only the event_id and is not excluded.
+	booking.Seat = "B"
+	unique, err = scope.IsUnique("Reference")
+	asserts.NoError(err)
+	asserts.False(unique)
+}
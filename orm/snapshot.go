@@ -8,6 +8,7 @@ import (
 	"reflect"
 
 	"github.com/patrickascher/gofer/query"
+	"github.com/patrickascher/gofer/slicer"
 )
 
 // constants to define the changed values.
@@ -72,16 +73,43 @@ func (s scope) ChangedValueByFieldName(field string) *ChangedValue {
 	return nil
 }
 
+// restrictSnapshotColumns is a helper to limit the selected columns of the snapshot.
+// If snapshot fields are configured, only the primary keys, the tracked fields and the foreign keys of the relations are
+// selected. The relations are not affected.
+func restrictSnapshotColumns(snapshot scope, config config) {
+	if len(config.snapshotFields) == 0 {
+		return
+	}
+
+	keys := map[string]bool{}
+	for _, relation := range snapshot.model.relations {
+		keys[relation.Mapping.ForeignKey.Name] = true
+	}
+
+	for i, field := range snapshot.model.fields {
+		if _, tracked := slicer.StringExists(config.snapshotFields, field.Name); tracked || field.Information.PrimaryKey || keys[field.Name] {
+			continue
+		}
+		snapshot.model.fields[i].Permission.Write = false
+	}
+}
+
 // EqualWith checks if the given orm model is equal with the scope orm model.
 // A []ChangedValue will return with all the changes recursively (fields and relations).
 // On relations and slices the operation info (create, update or delete) is given.
 // All time fields are excluded of this check.
+// If snapshot fields are configured, only those fields are checked.
+// HasMany and m2m relations with the sync mode Merge will not report omitted entries as DELETE.
 // On hasMany or m2m relations on DELETE operation the index will be the Field "ID".
 func (s scope) EqualWith(snapshot Interface) ([]ChangedValue, error) {
 
 	var cv []ChangedValue
 	perm := Permission{Write: true}
 
+	// untracked fields, if the snapshot fields are limited by config.
+	config := s.Config()
+	var untracked []ChangedValue
+
 	// normal fields
 	for _, field := range s.SQLFields(perm) {
 		// skip the automatic time fields or soft delete field.
@@ -92,6 +120,15 @@ func (s scope) EqualWith(snapshot Interface) ([]ChangedValue, error) {
 			continue
 		}
 
+		if len(config.snapshotFields) > 0 {
+			if _, tracked := slicer.StringExists(config.snapshotFields, field.Name); !tracked {
+				if config.snapshotUntracked && !field.Information.PrimaryKey {
+					untracked = append(untracked, ChangedValue{Operation: UPDATE, Field: field.Name, New: s.FieldValue(field.Name).Interface()})
+				}
+				continue
+			}
+		}
+
 		oldValue := snapshot.model().scope.FieldValue(field.Name).Interface()
 		newValue := s.FieldValue(field.Name).Interface()
 		if oldValue != newValue {
@@ -101,6 +138,7 @@ func (s scope) EqualWith(snapshot Interface) ([]ChangedValue, error) {
 
 	// if there were any changes on the normal fields, the UpdatedAt field gets set as changed field.
 	if len(cv) > 0 {
+		cv = append(cv, untracked...)
 		cv = append(cv, ChangedValue{Operation: UPDATE, Field: UpdatedAt})
 	}
 
@@ -198,9 +236,14 @@ func (s scope) EqualWith(snapshot Interface) ([]ChangedValue, error) {
 				cv = append(cv, ChangedValue{Operation: CREATE, Field: relation.Field})
 				continue
 			}
+			// omitted entries are not deleted on merge.
+			merge := s.Config().relationSync[relation.Field] == Merge
+
 			// if there are no entries in the relation.
 			if newLength == 0 {
-				cv = append(cv, ChangedValue{Operation: DELETE, Field: relation.Field})
+				if !merge {
+					cv = append(cv, ChangedValue{Operation: DELETE, Field: relation.Field})
+				}
 				continue
 			}
 
@@ -298,7 +341,7 @@ func (s scope) EqualWith(snapshot Interface) ([]ChangedValue, error) {
 			*/
 
 			// all still existing snapshot slices, will get deleted. because they are represented in the new relation slice.
-			if deleteEntries.IsValid() && reflect.Indirect(deleteEntries).Len() > 0 {
+			if !merge && deleteEntries.IsValid() && reflect.Indirect(deleteEntries).Len() > 0 {
 				for n := 0; n < deleteEntries.Len(); n++ {
 					// TODO check if PK is set correctly - before it was ID hardcoded.
 					// TODO only the first pk is checked, create a function to check all pks.
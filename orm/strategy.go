@@ -20,8 +20,10 @@ type Strategy interface {
 	First(scope Scope, c condition.Condition, permission Permission) error
 	All(res interface{}, scope Scope, c condition.Condition) error
 	Create(scope Scope) error
+	CreateBatch(scopes []Scope) error
 	Update(scope Scope, c condition.Condition) error
 	Delete(scope Scope, c condition.Condition) error
+	Preload(parents interface{}, scope Scope) error
 
 	// reserved for none eager strategies to load relations
 	Load(interface{}) Strategy
@@ -8,6 +8,8 @@ import (
 	"database/sql"
 	"log"
 	"reflect"
+	"strings"
+	"sync"
 
 	"github.com/patrickascher/gofer/query"
 	"github.com/patrickascher/gofer/query/condition"
@@ -37,7 +39,9 @@ func (e *eager) Load(interface{}) Strategy {
 // addSoftDeleteCondition is a helper to add the soft deleting condition.
 func addSoftDeleteCondition(scope Scope, config config, c condition.Condition) {
 	if scope.SoftDelete() != nil && !config.showDeletedRows {
-		if scope.SoftDelete().ActiveValues == nil {
+		if scope.SoftDelete().Predicate != "" {
+			c.SetWhere(strings.ReplaceAll(scope.SoftDelete().Predicate, "%s", scope.Builder().QuoteIdentifier(scope.SoftDelete().Field)))
+		} else if scope.SoftDelete().ActiveValues == nil {
 			c.SetWhere(scope.Builder().QuoteIdentifier(scope.SoftDelete().Field) + " IS NULL")
 		} else {
 			if scope.SoftDelete().ActiveValues[0] == "!=" {
@@ -51,10 +55,21 @@ func addSoftDeleteCondition(scope Scope, config config, c condition.Condition) {
 
 // createWhere is a helper to create a where condition.
 // If the value is a slice or array, a IN(?) will be generated.
-// If a polymorphic is defined, the polymorphic condition will be generated.
+// If a polymorphic is defined, the polymorphic condition will be generated - except the config polymorphicAnyOwner is set.
 // If a soft delete is defined, it will be added set.
 // If a custom relation is defined, the default condition will be reset or the conditions will be merged.
 func (e *eager) createWhere(relScope Scope, relation Relation, config config, value interface{}) condition.Condition {
+	// the owner value is only used if a single owner is requested.
+	var owner Interface
+	if p := relScope.Model().parentModel; p != nil && reflect.TypeOf(value).Kind() != reflect.Slice {
+		owner = p.caller
+	}
+	return e.createPolymorphicWhere(relScope, relation, config, value, polymorphicValue(owner, relation))
+}
+
+// createPolymorphicWhere is a helper to create a where condition with the given polymorphic value.
+// It is used if several owners are requested at once, which are grouped by their polymorphic value.
+func (e *eager) createPolymorphicWhere(relScope Scope, relation Relation, config config, value interface{}, polymorphic string) condition.Condition {
 
 	// custom condition
 	manualCondition, reset := config.Condition()
@@ -70,11 +85,9 @@ func (e *eager) createWhere(relScope Scope, relation Relation, config config, va
 		op = " IN (?)"
 	}
 
-	if relation.IsPolymorphic() {
-		c.SetWhere(relScope.Builder().QuoteIdentifier(relation.Mapping.References.Information.Name)+op, value)
-		c.SetWhere(relScope.Builder().QuoteIdentifier(relation.Mapping.Polymorphic.TypeField.Information.Name)+" = ?", relation.Mapping.Polymorphic.Value)
-	} else {
-		c.SetWhere(relScope.Builder().QuoteIdentifier(relation.Mapping.References.Information.Name)+op, value)
+	c.SetWhere(relScope.Builder().QuoteIdentifier(relation.Mapping.References.Information.Name)+op, value)
+	if relation.IsPolymorphic() && !config.polymorphicAnyOwner {
+		c.SetWhere(relScope.Builder().QuoteIdentifier(relation.Mapping.Polymorphic.TypeField.Information.Name)+" = ?", polymorphic)
 	}
 
 	// soft deleted rows
@@ -96,7 +109,7 @@ func setValue(scope Scope, relation Relation, field reflect.Value) error {
 		return err
 	}
 	if relation.IsPolymorphic() {
-		err := SetReflectValue(field.FieldByName(relation.Mapping.Polymorphic.TypeField.Name), reflect.ValueOf(relation.Mapping.Polymorphic.Value))
+		err := SetReflectValue(field.FieldByName(relation.Mapping.Polymorphic.TypeField.Name), reflect.ValueOf(polymorphicValue(scope.Caller(), relation)))
 		if err != nil {
 			return err
 		}
@@ -104,6 +117,17 @@ func setValue(scope Scope, relation Relation, field reflect.Value) error {
 	return nil
 }
 
+// polymorphicValue returns the polymorphic value of the relation.
+// If the owner implements the PolymorphicValuer interface and returns a non empty value, it will be used instead.
+func polymorphicValue(owner Interface, relation Relation) string {
+	if p, ok := owner.(PolymorphicValuer); ok {
+		if v := p.PolymorphicValue(); v != "" {
+			return v
+		}
+	}
+	return relation.Mapping.Polymorphic.Value
+}
+
 // createOrUpdate is a helper to create an entry if the primary keys are missing.
 // It updates an entry if primary keys exist and its existing in the database, otherwise it will create the entry.
 // poly will be set to the relation model if exists - not on m2m because it must be set in the junction table.
@@ -117,7 +141,11 @@ func createOrUpdate(relModel Interface, relation Relation, selfReference bool) e
 	// add poly value - (not for m2m)
 	// m2m poly is set on the junction table - so no need for setting the relation orm value.
 	if relation.Mapping.Polymorphic.Value != "" && relation.Kind != ManyToMany {
-		err = SetReflectValue(relScope.FieldValue(relation.Mapping.Polymorphic.TypeField.Name), reflect.ValueOf(relation.Mapping.Polymorphic.Value))
+		var owner Interface
+		if p := relScope.Model().parentModel; p != nil {
+			owner = p.caller
+		}
+		err = SetReflectValue(relScope.FieldValue(relation.Mapping.Polymorphic.TypeField.Name), reflect.ValueOf(polymorphicValue(owner, relation)))
 		if err != nil {
 			return err
 		}
@@ -162,3 +190,39 @@ func compareValues(v1 interface{}, v2 interface{}) bool {
 
 	return s1 == s2
 }
+
+// loadRelations is a helper to load the relations by the given function.
+// If a relation concurrency is configured, the relations are loaded in parallel. Inside a transaction they are always
+// loaded sequentially because a transaction is bound to one connection.
+// The first error in relation order will return, wrapped in a RelationError.
+func loadRelations(scope Scope, relations []Relation, fn func(Relation) error) error {
+	n := scope.Config().relationConcurrency
+	if n <= 1 || len(relations) <= 1 || scope.Model().tx != nil {
+		for _, relation := range relations {
+			if err := fn(relation); err != nil {
+				return relationError(scope, relation, err)
+			}
+		}
+		return nil
+	}
+
+	errs := make([]error, len(relations))
+	sem := make(chan struct{}, n)
+	var wg sync.WaitGroup
+	for i, relation := range relations {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, relation Relation) {
+			defer func() { <-sem; wg.Done() }()
+			errs[i] = relationError(scope, relation, fn(relation))
+		}(i, relation)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -10,11 +10,15 @@ import (
 	"reflect"
 
 	"github.com/patrickascher/gofer/query"
+	"github.com/patrickascher/gofer/query/condition"
+	"github.com/patrickascher/gofer/query/types"
+	"github.com/patrickascher/gofer/slicer"
 )
 
 // Create a new entry.
 // BelongsTo: will be skipped on empty or if a self reference loop is detected.
 // Otherwise the entry will be created and the reference field will be set.
+// On a circular belongsTo (A belongsTo B, B belongsTo A) the foreign key of B is updated after A was inserted.
 // If the belongsTo primary key(s) are already set, it will update the entry instead of creating it (if the pkey exists in the db).
 // There is an option to only update the reference field without creating or updating the linked entry. (belongsTo, manyToMany)
 // Only fields with the write permission will be written.
@@ -38,42 +42,224 @@ import (
 // There is an option to only update the reference field without creating or updating the linked entry.
 func (e *eager) Create(scope Scope) error {
 
-	perm := Permission{Write: true}
 	b := scope.Builder()
-	// belongsTo Relations must be create before, to set the parent fk.
+	err := createBelongsTo(scope)
+	if err != nil {
+		return err
+	}
+
+	insertValue, insertColumns, autoincrement, err := insertValues(scope)
+	if err != nil {
+		return err
+	}
+
+	if len(insertColumns) == 0 {
+		return errors.New("orm: no value is given")
+	}
+	insert := b.Query(scope.Model().tx).Insert(scope.FqdnTable()).Columns(insertColumns...).Values([]map[string]interface{}{insertValue})
+	returning := b.Capabilities().Supports(query.CapReturning)
+	if returning {
+		// the whole row (defaults, triggers, sequences) is returned by the insert.
+		readPerm := Permission{Read: true}
+		insert.Returning(scope.SQLColumns(readPerm), scope.SQLScanFields(readPerm)...)
+	} else if autoincrement.Name != "" {
+		insert.LastInsertedID(scope.FieldValue(autoincrement.Name).Addr().Interface(), autoincrement.Information.Name)
+	}
+	res, err := insert.Exec()
+	if err != nil {
+		return err
+	}
+	scope.Model().addRowsAffected(res...)
+
+	// fallback, select the created row again.
+	if !returning && scope.Config().reselectOnCreate {
+		err = reselect(scope)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = updateCircularReferences(scope)
+	if err != nil {
+		return err
+	}
+
+	return createRelations(scope)
+}
+
+// CreateBatch creates all the given scopes of the same orm model.
+// The belongsTo relations are created first, then the root entries are inserted with multi value statements and
+// the autoincrement ids are set to the models. After that, the other relations of each model will be created.
+// All columns of the given models are inserted, zero values are only skipped if they are zero on all models.
+func (e *eager) CreateBatch(scopes []Scope) error {
+	if len(scopes) == 0 {
+		return nil
+	}
+
+	for _, scope := range scopes {
+		err := createBelongsTo(scope)
+		if err != nil {
+			return err
+		}
+	}
+
+	values, columns, autoincrement, ids, err := batchInsertValues(scopes)
+	if err != nil {
+		return err
+	}
+
+	scope := scopes[0]
+	insert := scope.Builder().Query(scope.Model().tx).Insert(scope.FqdnTable()).Columns(columns...).Values(values)
+	// ids can only be set if the autoincrement field is zero on all models.
+	// the ids are calculated from the first id, see the limitation of BatchCreate.
+	if _, exists := slicer.StringExists(columns, autoincrement.Information.Name); autoincrement.Name != "" && !exists && len(ids) == len(scopes) {
+		insert.LastInsertedIDs(ids...)
+	}
+	res, err := insert.Exec()
+	if err != nil {
+		return err
+	}
+	scope.Model().addRowsAffected(res...)
+
+	for _, scope := range scopes {
+		err = updateCircularReferences(scope)
+		if err != nil {
+			return err
+		}
+		err = createRelations(scope)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// createBelongsTo creates or updates the belongsTo relations and sets the foreign key to the model.
+// It must be called before the model is inserted.
+func createBelongsTo(scope Scope) error {
+	scope.Model().circularReferences = nil
+	perm := Permission{Write: true}
 	for _, relation := range scope.SQLRelations(perm) {
 		if relation.Kind == BelongsTo {
 
-			// skip if empty or self referencing loop
-			if IsValueZero(scope.FieldValue(relation.Field)) || scope.IsSelfReferenceLoop(relation) {
+			// skip if empty
+			if IsValueZero(scope.FieldValue(relation.Field)) {
+				continue
+			}
+
+			// skip on self referencing loop, a circular reference is updated after the parent was inserted.
+			if scope.IsSelfReferenceLoop(relation) {
+				addCircularReference(scope, relation)
 				continue
 			}
 
 			// init relation model
 			rel, err := scope.InitRelationByField(relation.Field, false)
 			if err != nil {
-				return err
+				return relationError(scope, relation, err)
 			}
 
 			// create or update
 			err = createOrUpdate(rel, relation, false)
 			if err != nil {
-				return err
+				return relationError(scope, relation, err)
 			}
 
 			// set related id to the parent model.
 			err = SetReflectValue(scope.FieldValue(relation.Mapping.ForeignKey.Name), rel.model().scope.FieldValue(relation.Mapping.References.Name))
 			if err != nil {
-				return err
+				return relationError(scope, relation, err)
 			}
 		}
 	}
+	return nil
+}
+
+// circularReference is a belongsTo relation of a scope which references a parent that is not inserted yet.
+type circularReference struct {
+	scope    Scope
+	relation Relation
+}
+
+// addCircularReference adds the relation to the referenced parent, if the parent is the same entry and not inserted yet.
+// The foreign key is inserted as NULL and updated by the parent after its insert.
+func addCircularReference(scope Scope, relation Relation) {
+	p, err := scope.Parent(relation.Type.String())
+	if err != nil || p.caller == nil {
+		return
+	}
+	if reflect.ValueOf(p.caller).Pointer() != scope.FieldValue(relation.Field).Pointer() || !p.scope.FieldValue(relation.Mapping.References.Name).IsZero() {
+		return
+	}
+	p.circularReferences = append(p.circularReferences, circularReference{scope: scope, relation: relation})
+}
+
+// updateCircularReferences sets and updates the foreign keys of the circular references, after the scope was inserted.
+func updateCircularReferences(scope Scope) error {
+	m := scope.Model()
+	defer func() { m.circularReferences = nil }()
+
+	for _, ref := range m.circularReferences {
+		err := SetReflectValue(ref.scope.FieldValue(ref.relation.Mapping.ForeignKey.Name), scope.FieldValue(ref.relation.Mapping.References.Name))
+		if err != nil {
+			return relationError(ref.scope, ref.relation, err)
+		}
+
+		pKeys, err := ref.scope.PrimaryKeys()
+		if err != nil {
+			return relationError(ref.scope, ref.relation, err)
+		}
+		b := ref.scope.Builder()
+		c := condition.New()
+		for _, pkey := range pKeys {
+			c.SetWhere(b.QuoteIdentifier(pkey.Information.Name)+" = ?", ref.scope.FieldValue(pkey.Name).Interface())
+		}
 
-	// get the struct variable for the scan
+		column := ref.relation.Mapping.ForeignKey.Information.Name
+		res, err := b.Query(m.tx).Update(ref.scope.FqdnTable()).Columns(column).Set(map[string]interface{}{column: ref.scope.FieldValue(ref.relation.Mapping.ForeignKey.Name).Interface()}).Condition(c).Exec()
+		if err != nil {
+			return relationError(ref.scope, ref.relation, err)
+		}
+		m.addRowsAffected(res)
+	}
+	return nil
+}
+
+// insertValues returns the values and columns of the model which should be inserted.
+// Zero values are skipped, primary keys are generated (registered generator, UUID) and a zero autoincrement field is returned separately.
+func insertValues(scope Scope) (map[string]interface{}, []string, Field, error) {
+	perm := Permission{Write: true}
 	insertValue := map[string]interface{}{}
 	var insertColumns []string
 	var autoincrement Field
+	generator, hasGenerator := pkGenerator(scope)
+	firstPrimary := true
 	for _, f := range scope.SQLFields(perm) {
+		// generate the first primary key by the registered generator if no value is set.
+		// composite keys would get duplicate values otherwise.
+		if f.Information.PrimaryKey && firstPrimary {
+			firstPrimary = false
+			if hasGenerator && scope.FieldValue(f.Name).IsZero() {
+				err := setGeneratedPK(scope.FieldValue(f.Name), f.Name, generator())
+				if err != nil {
+					return nil, nil, Field{}, err
+				}
+			}
+		}
+
+		// generate uuid primary keys if no value is set
+		if f.Information.PrimaryKey && f.Information.Type != nil && f.Information.Type.Kind() == types.UUID && scope.FieldValue(f.Name).IsZero() {
+			uuid, err := UUIDGenerator()
+			if err != nil {
+				return nil, nil, Field{}, err
+			}
+			err = SetReflectValue(scope.FieldValue(f.Name), reflect.ValueOf(uuid))
+			if err != nil {
+				return nil, nil, Field{}, err
+			}
+		}
+
 		// skipping autoincrement fields if no value is set
 		if f.Information.Autoincrement && scope.FieldValue(f.Name).IsZero() {
 			autoincrement = f
@@ -85,23 +271,64 @@ func (e *eager) Create(scope Scope) error {
 			continue
 		}
 
-		insertValue[f.Information.Name] = scope.FieldValue(f.Name).Interface()
+		insertValue[f.Information.Name] = sqlValue(scope, f)
 		insertColumns = append(insertColumns, f.Information.Name)
 	}
 
-	if len(insertColumns) == 0 {
-		return errors.New("orm: no value is given")
+	return insertValue, insertColumns, autoincrement, nil
+}
+
+// batchInsertValues returns the values and columns of a multi value insert for the given scopes.
+// The values are created by insertValues, missing columns are added with the zero value of the model.
+// The autoincrement field and the addresses of the zero autoincrement values are returned for the last inserted ids.
+// Error will return if no column is given.
+func batchInsertValues(scopes []Scope) ([]map[string]interface{}, []string, Field, []interface{}, error) {
+	var values []map[string]interface{}
+	var columns []string
+	var ids []interface{}
+	var autoincrement Field
+	for _, scope := range scopes {
+		insertValue, insertColumns, autoField, err := insertValues(scope)
+		if err != nil {
+			return nil, nil, Field{}, nil, err
+		}
+		for _, column := range insertColumns {
+			if _, exists := slicer.StringExists(columns, column); !exists {
+				columns = append(columns, column)
+			}
+		}
+		values = append(values, insertValue)
+		if autoField.Name != "" {
+			autoincrement = autoField
+			ids = append(ids, scope.FieldValue(autoField.Name).Addr().Interface())
+		}
 	}
-	insert := b.Query(scope.Model().tx).Insert(scope.FqdnTable()).Columns(insertColumns...).Values([]map[string]interface{}{insertValue})
-	if autoincrement.Name != "" {
-		insert.LastInsertedID(scope.FieldValue(autoincrement.Name).Addr().Interface(), autoincrement.Information.Name)
+
+	if len(columns) == 0 {
+		return nil, nil, Field{}, nil, errors.New("orm: no value is given")
 	}
-	_, err := insert.Exec()
-	if err != nil {
-		return err
+
+	// add the zero values of the missing columns.
+	for n, scope := range scopes {
+		for _, f := range scope.SQLFields(Permission{Write: true}) {
+			if _, exists := values[n][f.Information.Name]; !exists {
+				if _, column := slicer.StringExists(columns, f.Information.Name); column {
+					values[n][f.Information.Name] = sqlValue(scope, f)
+				}
+			}
+		}
 	}
 
-	// handle the other relations
+	return values, columns, autoincrement, ids, nil
+}
+
+// createRelations creates the hasOne, hasMany and manyToMany relations of the model.
+// It must be called after the model is inserted.
+func createRelations(scope Scope) error {
+	var err error
+	perm := Permission{Write: true}
+	b := scope.Builder()
+
 	for _, relation := range scope.SQLRelations(perm) {
 
 		// skip if no value is given
@@ -114,69 +341,72 @@ func (e *eager) Create(scope Scope) error {
 			// init relation model
 			rel, err := scope.InitRelationByField(relation.Field, false)
 			if err != nil {
-				return err
+				return relationError(scope, relation, err)
 			}
 
 			// set parent ID to relation model - and poly if exists.
 			err = setValue(scope, relation, reflect.Indirect(reflect.ValueOf(rel.model().caller)))
 			if err != nil {
-				return err
+				return relationError(scope, relation, err)
 			}
 
 			// create entry
 			err = rel.Create()
 			if err != nil {
-				return err
+				return relationError(scope, relation, err)
 			}
 		case HasMany:
 			// init relation model
 			rel, err := scope.InitRelationByField(relation.Field, false)
 			if err != nil {
-				return err
+				return relationError(scope, relation, err)
 			}
 
 			// if no relations exist, a multi-insert can be made to avoid lots of db queries.
 			if len(rel.model().scope.SQLRelations(perm)) == 0 {
 				slice := scope.FieldValue(relation.Field)
-
-				var values []map[string]interface{}
-				var cols []string
 				// needed for *[]
 				if slice.Kind() == reflect.Ptr {
 					slice = slice.Elem()
 				}
 
+				var scopes []Scope
 				for i := 0; i < slice.Len(); i++ {
 					// skip if the added value is an empty struct
 					if IsValueZero(slice.Index(i)) {
 						continue
 					}
 
-					// set parent ID to relation model - and poly if exists.
-					err = setValue(scope, relation, reflect.Indirect(slice.Index(i)))
+					// get related struct
+					var r Interface
+					if slice.Index(i).Kind() == reflect.Ptr {
+						r = slice.Index(i).Interface().(Interface)
+					} else {
+						r = slice.Index(i).Addr().Interface().(Interface)
+					}
+					err = scope.InitRelation(r, relation.Field)
 					if err != nil {
-						return err
+						return relationError(scope, relation, err)
 					}
 
-					// get the struct variable for the scan
-					value := map[string]interface{}{}
-					for _, f := range rel.model().scope.SQLFields(perm) {
-						// skipping autoincrement fields if no value is set
-						if f.Information.Autoincrement && reflect.Indirect(slice.Index(i)).FieldByName(f.Name).IsZero() {
-							continue
-						}
-						value[f.Information.Name] = reflect.Indirect(slice.Index(i)).FieldByName(f.Name).Interface()
-						if i == 0 {
-							cols = append(cols, f.Information.Name)
-						}
+					// set parent ID to relation model - and poly if exists.
+					err = setValue(scope, relation, reflect.Indirect(reflect.ValueOf(r.model().caller)))
+					if err != nil {
+						return relationError(scope, relation, err)
 					}
-					values = append(values, value)
+					scopes = append(scopes, &r.model().scope)
 				}
-				if len(values) > 0 {
-					_, err = rel.model().builder.Query(rel.model().tx).Insert(rel.model().scope.FqdnTable()).Columns(cols...).Values(values).Exec()
+				if len(scopes) > 0 {
+					// the values are created by insertValues, so the primary key generators and sensitive values are handled.
+					values, cols, _, _, err := batchInsertValues(scopes)
+					if err != nil {
+						return relationError(scope, relation, err)
+					}
+					res, err := rel.model().builder.Query(rel.model().tx).Insert(rel.model().scope.FqdnTable()).Columns(cols...).Values(values).Exec()
 					if err != nil {
-						return err
+						return relationError(scope, relation, err)
 					}
+					scope.Model().addRowsAffected(res...)
 				}
 			} else {
 				slice := scope.FieldValue(relation.Field)
@@ -198,19 +428,19 @@ func (e *eager) Create(scope Scope) error {
 					}
 					err = scope.InitRelation(r, relation.Field)
 					if err != nil {
-						return err
+						return relationError(scope, relation, err)
 					}
 
 					// set parent ID to relation model - and poly if exists.
 					err = setValue(scope, relation, reflect.Indirect(reflect.ValueOf(r.model().caller)))
 					if err != nil {
-						return err
+						return relationError(scope, relation, err)
 					}
 
 					// create the entries
 					err = r.Create()
 					if err != nil {
-						return err
+						return relationError(scope, relation, err)
 					}
 				}
 			}
@@ -238,19 +468,19 @@ func (e *eager) Create(scope Scope) error {
 				// init model
 				err = scope.InitRelation(rel, relation.Field)
 				if err != nil {
-					return err
+					return relationError(scope, relation, err)
 				}
 
 				// create or update the entry
 				err = createOrUpdate(rel, relation, scope.IsSelfReferenceLoop(relation))
 				if err != nil {
-					return err
+					return relationError(scope, relation, err)
 				}
 
 				// add last inserted id for junction table
 				v, err := query.SanitizeInterfaceValue(rel.model().scope.FieldValue(relation.Mapping.References.Name).Interface())
 				if err != nil {
-					return err
+					return relationError(scope, relation, err)
 				}
 				refIDs = append(refIDs, v)
 			}
@@ -260,7 +490,7 @@ func (e *eager) Create(scope Scope) error {
 				var val []map[string]interface{}
 				for _, refID := range refIDs {
 					if relation.IsPolymorphic() {
-						val = append(val, map[string]interface{}{relation.Mapping.Join.ForeignColumnName: scope.FieldValue(relation.Mapping.ForeignKey.Name).Interface(), relation.Mapping.Polymorphic.TypeField.Information.Name: relation.Mapping.Polymorphic.Value, relation.Mapping.Join.ReferencesColumnName: refID})
+						val = append(val, map[string]interface{}{relation.Mapping.Join.ForeignColumnName: scope.FieldValue(relation.Mapping.ForeignKey.Name).Interface(), relation.Mapping.Polymorphic.TypeField.Information.Name: polymorphicValue(scope.Caller(), relation), relation.Mapping.Join.ReferencesColumnName: refID})
 					} else {
 						val = append(val, map[string]interface{}{relation.Mapping.Join.ForeignColumnName: scope.FieldValue(relation.Mapping.ForeignKey.Name).Interface(), relation.Mapping.Join.ReferencesColumnName: refID})
 					}
@@ -273,13 +503,36 @@ func (e *eager) Create(scope Scope) error {
 				if relation.IsPolymorphic() {
 					stmt.Columns(relation.Mapping.Join.ForeignColumnName, relation.Mapping.Polymorphic.TypeField.Information.Name, relation.Mapping.Join.ReferencesColumnName)
 				}
-				_, err = stmt.Exec()
+				res, err := stmt.Exec()
 				if err != nil {
 					return fmt.Errorf("orm: eager m2m create: %w", err)
 				}
+				scope.Model().addRowsAffected(res...)
 			}
 		}
 	}
 
 	return nil
 }
+
+// reselect will select the created entry by its primary keys and scan all readable fields into the model.
+// It is used to fill db defaults, triggers or sequences if the provider does not support returning.
+func reselect(scope Scope) error {
+	pKeys, err := scope.PrimaryKeys()
+	if err != nil {
+		return err
+	}
+	b := scope.Builder()
+	c := condition.New()
+	for _, pkey := range pKeys {
+		c.SetWhere(b.QuoteIdentifier(pkey.Information.Name)+" = ?", scope.FieldValue(pkey.Name).Interface())
+	}
+
+	perm := Permission{Read: true}
+	sel := b.Query(scope.Model().tx).Select(scope.FqdnTable()).Columns(scope.SQLColumns(perm)...).Condition(c)
+	row, err := sel.First()
+	if err != nil {
+		return err
+	}
+	return row.Scan(scope.SQLScanFieldsByColumns(sel.Projection())...)
+}
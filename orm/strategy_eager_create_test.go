@@ -6,14 +6,19 @@ package orm_test
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	_ "github.com/patrickascher/gofer/cache/memory"
+	"github.com/patrickascher/gofer/logger"
+	loggerMocks "github.com/patrickascher/gofer/logger/mocks"
 	"github.com/patrickascher/gofer/orm"
 	"github.com/patrickascher/gofer/query"
 	"github.com/patrickascher/gofer/query/condition"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 // TestEager_Create_SelfRef tests:
@@ -63,6 +68,648 @@ func TestEager_Create_SelfRef(t *testing.T) {
 	asserts.Equal("RoleD", role.Roles[0].Roles[0].Name)
 }
 
+// TestEager_Create_UUID tests:
+// - If an uuid primary key is generated on create and persisted.
+// - If a given uuid is not overwritten.
+func TestEager_Create_UUID(t *testing.T) {
+	asserts := assert.New(t)
+	helperCreateDatabaseAndTable(asserts)
+	_, err := builder.Query().DB().Exec("DROP TABLE IF EXISTS `tests`.`gadgets`")
+	asserts.NoError(err)
+	_, err = builder.Query().DB().Exec("CREATE TABLE `tests`.`gadgets` (`id` char(36) NOT NULL, `name` varchar(250) NOT NULL DEFAULT '', PRIMARY KEY (`id`)) ENGINE=InnoDB DEFAULT CHARSET=utf8;")
+	asserts.NoError(err)
+
+	// ok: uuid is generated.
+	gadget := Gadget{}
+	err = gadget.Init(&gadget)
+	asserts.NoError(err)
+	gadget.Name = "Phone"
+	err = gadget.Create()
+	asserts.NoError(err)
+	asserts.Regexp("^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$", gadget.ID)
+	id := gadget.ID
+
+	gadget = Gadget{}
+	err = gadget.Init(&gadget)
+	asserts.NoError(err)
+	err = gadget.First(condition.New().SetWhere("id = ?", id))
+	asserts.NoError(err)
+	asserts.Equal("Phone", gadget.Name)
+
+	// ok: custom generator and given value.
+	defer func(generator func() (string, error)) { orm.UUIDGenerator = generator }(orm.UUIDGenerator)
+	orm.UUIDGenerator = func() (string, error) { return "00000000-0000-0000-0000-000000000001", nil }
+	gadget = Gadget{}
+	err = gadget.Init(&gadget)
+	asserts.NoError(err)
+	gadget.Name = "Tablet"
+	err = gadget.Create()
+	asserts.NoError(err)
+	asserts.Equal("00000000-0000-0000-0000-000000000001", gadget.ID)
+
+	gadget = Gadget{}
+	err = gadget.Init(&gadget)
+	asserts.NoError(err)
+	gadget.ID = "00000000-0000-0000-0000-000000000002"
+	gadget.Name = "Watch"
+	err = gadget.Create()
+	asserts.NoError(err)
+	asserts.Equal("00000000-0000-0000-0000-000000000002", gadget.ID)
+}
+
+// TestEager_Create_UUID_HasMany tests:
+// - If the uuid primary keys of hasMany entries are generated on the multi insert.
+func TestEager_Create_UUID_HasMany(t *testing.T) {
+	asserts := assert.New(t)
+	helperCreateDatabaseAndTable(asserts)
+	_, err := builder.Query().DB().Exec("DROP TABLE IF EXISTS `tests`.`kits`")
+	asserts.NoError(err)
+	_, err = builder.Query().DB().Exec("CREATE TABLE `tests`.`kits` (`id` char(36) NOT NULL, `name` varchar(250) NOT NULL DEFAULT '', PRIMARY KEY (`id`)) ENGINE=InnoDB DEFAULT CHARSET=utf8;")
+	asserts.NoError(err)
+	_, err = builder.Query().DB().Exec("DROP TABLE IF EXISTS `tests`.`kit_parts`")
+	asserts.NoError(err)
+	_, err = builder.Query().DB().Exec("CREATE TABLE `tests`.`kit_parts` (`id` char(36) NOT NULL, `kit_id` char(36) NOT NULL, `name` varchar(250) NOT NULL DEFAULT '', PRIMARY KEY (`id`)) ENGINE=InnoDB DEFAULT CHARSET=utf8;")
+	asserts.NoError(err)
+
+	kit := Kit{}
+	err = kit.Init(&kit)
+	asserts.NoError(err)
+	kit.Name = "Toolbox"
+	kit.Parts = []KitPart{{Name: "Hammer"}, {Name: "Saw"}}
+	err = kit.Create()
+	asserts.NoError(err)
+
+	kit2 := Kit{}
+	err = kit2.Init(&kit2)
+	asserts.NoError(err)
+	err = kit2.First(condition.New().SetWhere("id = ?", kit.ID))
+	asserts.NoError(err)
+	asserts.Equal(2, len(kit2.Parts))
+	for _, part := range kit2.Parts {
+		asserts.Regexp("^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$", part.ID)
+		asserts.Equal(kit.ID, part.KitID)
+	}
+	asserts.NotEqual(kit2.Parts[0].ID, kit2.Parts[1].ID)
+}
+
+// TestEager_Create_PKGenerator tests:
+// - If the registered generator sets the primary key on create.
+// - If a given primary key is not overwritten.
+// - If an error returns if the generator is already registered or the type does not match.
+func TestEager_Create_PKGenerator(t *testing.T) {
+	asserts := assert.New(t)
+	helperCreateDatabaseAndTable(asserts)
+	_, err := builder.Query().DB().Exec("DROP TABLE IF EXISTS `tests`.`tokens`")
+	asserts.NoError(err)
+	_, err = builder.Query().DB().Exec("CREATE TABLE `tests`.`tokens` (`id` char(26) NOT NULL, `name` varchar(250) NOT NULL DEFAULT '', PRIMARY KEY (`id`)) ENGINE=InnoDB DEFAULT CHARSET=utf8;")
+	asserts.NoError(err)
+
+	ulids := []interface{}{"01ARZ3NDEKTSV4RRFFQ69G5FAV", "01ARZ3NDEKTSV4RRFFQ69G5FAW", 1}
+	var i int
+	err = orm.RegisterPKGenerator("orm_test.Token", func() interface{} {
+		i++
+		return ulids[i-1]
+	})
+	asserts.NoError(err)
+
+	// error: already registered
+	err = orm.RegisterPKGenerator("orm_test.Token", func() interface{} { return "" })
+	asserts.Error(err)
+
+	// ok: primary key is generated.
+	token := Token{}
+	err = token.Init(&token)
+	asserts.NoError(err)
+	token.Name = "Session"
+	err = token.Create()
+	asserts.NoError(err)
+	asserts.Equal("01ARZ3NDEKTSV4RRFFQ69G5FAV", token.ID)
+
+	token = Token{}
+	err = token.Init(&token)
+	asserts.NoError(err)
+	err = token.First(condition.New().SetWhere("id = ?", "01ARZ3NDEKTSV4RRFFQ69G5FAV"))
+	asserts.NoError(err)
+	asserts.Equal("Session", token.Name)
+
+	// ok: given value is not overwritten.
+	token = Token{}
+	err = token.Init(&token)
+	asserts.NoError(err)
+	token.ID = "01ARZ3NDEKTSV4RRFFQ69G5FAX"
+	token.Name = "Api"
+	err = token.Create()
+	asserts.NoError(err)
+	asserts.Equal("01ARZ3NDEKTSV4RRFFQ69G5FAX", token.ID)
+	asserts.Equal(1, i)
+
+	// ok: next generated value.
+	token = Token{}
+	err = token.Init(&token)
+	asserts.NoError(err)
+	token.Name = "Refresh"
+	err = token.Create()
+	asserts.NoError(err)
+	asserts.Equal("01ARZ3NDEKTSV4RRFFQ69G5FAW", token.ID)
+
+	// error: generated type does not match the field.
+	token = Token{}
+	err = token.Init(&token)
+	asserts.NoError(err)
+	token.Name = "Invalid"
+	err = token.Create()
+	asserts.Error(err)
+	asserts.Equal(fmt.Sprintf(orm.ErrPKGenerator, "int", "ID", "string"), err.Error())
+}
+
+// TestEager_Create_PKGenerator_HasMany tests:
+// - If the registered generator sets the primary keys of hasMany entries on the multi insert.
+// - If only the first primary key of a composite key is generated.
+func TestEager_Create_PKGenerator_HasMany(t *testing.T) {
+	asserts := assert.New(t)
+	helperCreateDatabaseAndTable(asserts)
+	_, err := builder.Query().DB().Exec("DROP TABLE IF EXISTS `tests`.`vouchers`")
+	asserts.NoError(err)
+	_, err = builder.Query().DB().Exec("CREATE TABLE `tests`.`vouchers` (`id` int(11) unsigned NOT NULL AUTO_INCREMENT, `name` varchar(250) NOT NULL DEFAULT '', PRIMARY KEY (`id`)) ENGINE=InnoDB DEFAULT CHARSET=utf8;")
+	asserts.NoError(err)
+	_, err = builder.Query().DB().Exec("DROP TABLE IF EXISTS `tests`.`voucher_codes`")
+	asserts.NoError(err)
+	_, err = builder.Query().DB().Exec("CREATE TABLE `tests`.`voucher_codes` (`id` varchar(20) NOT NULL, `voucher_id` int(11) unsigned NOT NULL, PRIMARY KEY (`id`)) ENGINE=InnoDB DEFAULT CHARSET=utf8;")
+	asserts.NoError(err)
+	_, err = builder.Query().DB().Exec("DROP TABLE IF EXISTS `tests`.`tickets`")
+	asserts.NoError(err)
+	_, err = builder.Query().DB().Exec("CREATE TABLE `tests`.`tickets` (`code` varchar(20) NOT NULL, `seat` varchar(20) NOT NULL DEFAULT '', `name` varchar(250) NOT NULL DEFAULT '', PRIMARY KEY (`code`, `seat`)) ENGINE=InnoDB DEFAULT CHARSET=utf8;")
+	asserts.NoError(err)
+
+	var codes int
+	err = orm.RegisterPKGenerator("orm_test.VoucherCode", func() interface{} {
+		codes++
+		return fmt.Sprintf("CODE-%d", codes)
+	})
+	asserts.NoError(err)
+	var tickets int
+	err = orm.RegisterPKGenerator("orm_test.Ticket", func() interface{} {
+		tickets++
+		return fmt.Sprintf("T-%d", tickets)
+	})
+	asserts.NoError(err)
+
+	// ok: hasMany primary keys are generated.
+	voucher := Voucher{}
+	err = voucher.Init(&voucher)
+	asserts.NoError(err)
+	voucher.Name = "Summer"
+	voucher.Codes = []VoucherCode{{}, {}}
+	err = voucher.Create()
+	asserts.NoError(err)
+	asserts.Equal(2, codes)
+
+	voucher2 := Voucher{}
+	err = voucher2.Init(&voucher2)
+	asserts.NoError(err)
+	err = voucher2.First(condition.New().SetWhere("id = ?", voucher.ID))
+	asserts.NoError(err)
+	asserts.Equal(2, len(voucher2.Codes))
+	asserts.Equal("CODE-1", voucher2.Codes[0].ID)
+	asserts.Equal("CODE-2", voucher2.Codes[1].ID)
+
+	// ok: only the first primary key is generated.
+	ticket := Ticket{}
+	err = ticket.Init(&ticket)
+	asserts.NoError(err)
+	ticket.Seat = "A1"
+	ticket.Name = "Concert"
+	err = ticket.Create()
+	asserts.NoError(err)
+	asserts.Equal("T-1", ticket.Code)
+	asserts.Equal("A1", ticket.Seat)
+	asserts.Equal(1, tickets)
+}
+
+// TestEager_Create_ReadOnly tests:
+// - If a readonly field is scanned but never written on create and update.
+func TestEager_Create_ReadOnly(t *testing.T) {
+	asserts := assert.New(t)
+	helperCreateDatabaseAndTable(asserts)
+	_, err := builder.Query().DB().Exec("DROP TABLE IF EXISTS `tests`.`contacts`")
+	asserts.NoError(err)
+	_, err = builder.Query().DB().Exec("CREATE TABLE `tests`.`contacts` (`id` int(11) unsigned NOT NULL AUTO_INCREMENT, `first_name` varchar(50) NOT NULL DEFAULT '', `last_name` varchar(50) NOT NULL DEFAULT '', `full_name` varchar(101) AS (CONCAT(`first_name`,' ',`last_name`)) VIRTUAL NOT NULL, PRIMARY KEY (`id`)) ENGINE=InnoDB DEFAULT CHARSET=utf8;")
+	asserts.NoError(err)
+
+	contact := Contact{}
+	err = contact.Init(&contact)
+	asserts.NoError(err)
+	scope, err := contact.Scope()
+	asserts.NoError(err)
+	asserts.Equal([]string{"id", "first_name", "last_name"}, scope.SQLColumns(orm.Permission{Write: true}))
+	asserts.Equal([]string{"id", "first_name", "last_name", "full_name"}, scope.SQLColumns(orm.Permission{Read: true}))
+
+	// ok: create - full_name is not inserted.
+	contact.FirstName = "John"
+	contact.LastName = "Doe"
+	contact.FullName = "ignored"
+	err = contact.Create()
+	asserts.NoError(err)
+
+	err = contact.First(condition.New().SetWhere("id = ?", contact.ID))
+	asserts.NoError(err)
+	asserts.Equal("John Doe", contact.FullName)
+
+	// ok: update - full_name is not updated.
+	contact.FirstName = "Jane"
+	contact.FullName = "ignored"
+	err = contact.Update()
+	asserts.NoError(err)
+
+	err = contact.First(condition.New().SetWhere("id = ?", contact.ID))
+	asserts.NoError(err)
+	asserts.Equal("Jane Doe", contact.FullName)
+}
+
+// TestEager_Create_UniqueTogether tests:
+// - If a duplicate combination of the composite unique key is rejected on create and update.
+// - If the entry itself is excluded on update.
+func TestEager_Create_UniqueTogether(t *testing.T) {
+	asserts := assert.New(t)
+	helperCreateDatabaseAndTable(asserts)
+	insertUserData(asserts)
+
+	toy := Toy{}
+	err := toy.Init(&toy)
+	asserts.NoError(err)
+	scope, err := toy.Scope()
+	asserts.NoError(err)
+	scope.SetConfig(orm.NewConfig().SetUniqueTogether("Name", "AnimalID"))
+
+	// ok: first entry.
+	toy.Name = "UniqueBall"
+	toy.AnimalID = 1
+	err = toy.Create()
+	asserts.NoError(err)
+
+	// ok: entry itself is excluded on update.
+	toy.Brand = query.NewNullString("Brand", true)
+	err = toy.Update()
+	asserts.NoError(err)
+
+	// error: duplicate (name, animal_id) pair.
+	toy2 := Toy{}
+	err = toy2.Init(&toy2)
+	asserts.NoError(err)
+	scope, err = toy2.Scope()
+	asserts.NoError(err)
+	scope.SetConfig(orm.NewConfig().SetUniqueTogether("Name", "AnimalID"))
+	toy2.Name = "UniqueBall"
+	toy2.AnimalID = 1
+	err = toy2.Create()
+	asserts.Error(err)
+	asserts.True(errors.Is(err, query.ErrUniqueViolation))
+	asserts.Equal(fmt.Errorf(orm.ErrUniqueTogether, query.ErrUniqueViolation, "name, animal_id", "orm_test.Toy").Error(), err.Error())
+
+	// ok: same name but different animal.
+	toy2.AnimalID = 2
+	err = toy2.Create()
+	asserts.NoError(err)
+
+	// error: update to an existing pair.
+	toy2.AnimalID = 1
+	err = toy2.Update()
+	asserts.True(errors.Is(err, query.ErrUniqueViolation))
+}
+
+// TestEager_Create_UniqueTogether_CompositePrimary tests:
+// - If the entry itself is excluded by its composite primary keys.
+// - If an entry which only shares a part of the composite primary key is not excluded.
+func TestEager_Create_UniqueTogether_CompositePrimary(t *testing.T) {
+	asserts := assert.New(t)
+	helperCreateDatabaseAndTable(asserts)
+	_, err := builder.Query().DB().Exec("DROP TABLE IF EXISTS `tests`.`bookings`")
+	asserts.NoError(err)
+	_, err = builder.Query().DB().Exec("CREATE TABLE `tests`.`bookings` (`event_id` int(11) unsigned NOT NULL, `seat` varchar(20) NOT NULL, `reference` varchar(20) NOT NULL, PRIMARY KEY (`event_id`, `seat`)) ENGINE=InnoDB DEFAULT CHARSET=utf8;")
+	asserts.NoError(err)
+	_, err = builder.Query().DB().Exec("INSERT INTO `tests`.`bookings` (`event_id`, `seat`, `reference`) VALUES (1, 'A', 'R1'), (1, 'B', 'R2')")
+	asserts.NoError(err)
+
+	booking := Booking{}
+	err = booking.Init(&booking)
+	asserts.NoError(err)
+	scope, err := booking.Scope()
+	asserts.NoError(err)
+	scope.SetConfig(orm.NewConfig().SetUniqueTogether("EventID", "Reference"))
+
+	// ok: entry itself is excluded on update.
+	booking.EventID = 1
+	booking.Seat = "A"
+	booking.Reference = "R1"
+	err = booking.Update()
+	asserts.NoError(err)
+
+	// error: the entry (1, A) shares only the event_id and is not excluded.
+	booking.Seat = "B"
+	err = booking.Update()
+	asserts.Error(err)
+	asserts.True(errors.Is(err, query.ErrUniqueViolation))
+	asserts.Equal(fmt.Errorf(orm.ErrUniqueTogether, query.ErrUniqueViolation, "event_id, reference", "orm_test.Booking").Error(), err.Error())
+}
+
+// RoleCustomJoin is a self referencing m2m with custom junction column names.
+type RoleCustomJoin struct {
+	Base
+	Name string
+
+	Roles []RoleCustomJoin `orm:"join_table:role_hierarchies;join_fk:parent_role_id;join_refs:sub_role_id"`
+}
+
+func (r RoleCustomJoin) DefaultTableName() string {
+	return "roles"
+}
+
+// TestEager_Create_SelfRefCustomJoin tests:
+// - If the custom junction columns of a self referencing m2m are used on create.
+// - If the custom junction columns of a self referencing m2m are used on select.
+func TestEager_Create_SelfRefCustomJoin(t *testing.T) {
+	asserts := assert.New(t)
+	helperCreateDatabaseAndTable(asserts)
+
+	_, err := builder.Query().DB().Exec("CREATE TABLE `tests`.`role_hierarchies` (`parent_role_id` int(11) unsigned NOT NULL, `sub_role_id` int(11) unsigned NOT NULL) ENGINE=InnoDB DEFAULT CHARSET=utf8;")
+	asserts.NoError(err)
+
+	// ok: create
+	role := RoleCustomJoin{}
+	err = role.Init(&role)
+	asserts.NoError(err)
+	role.Name = "RoleA"
+	role.Roles = []RoleCustomJoin{{Name: "RoleB", Roles: []RoleCustomJoin{{Name: "RoleC"}}}}
+	err = role.Create()
+	asserts.NoError(err)
+
+	var junction [][2]int
+	rows, err := builder.Query().DB().Query("SELECT `parent_role_id`, `sub_role_id` FROM `tests`.`role_hierarchies` ORDER BY `parent_role_id`")
+	asserts.NoError(err)
+	for rows.Next() {
+		var j [2]int
+		asserts.NoError(rows.Scan(&j[0], &j[1]))
+		junction = append(junction, j)
+	}
+	asserts.NoError(rows.Close())
+	asserts.Equal([][2]int{{role.ID, role.Roles[0].ID}, {role.Roles[0].ID, role.Roles[0].Roles[0].ID}}, junction)
+
+	// ok: select
+	role = RoleCustomJoin{}
+	err = role.Init(&role)
+	asserts.NoError(err)
+	err = role.First(condition.New().SetWhere("name = ?", "RoleA"))
+	asserts.NoError(err)
+	asserts.Equal(1, len(role.Roles))
+	asserts.Equal("RoleB", role.Roles[0].Name)
+	asserts.Equal(1, len(role.Roles[0].Roles))
+	asserts.Equal("RoleC", role.Roles[0].Roles[0].Name)
+}
+
+// TestModel_BatchCreate tests:
+// - error if the items are not a slice of the orm model.
+// - If all entries are created and the ids are set.
+// - If the relations of each item are created.
+func TestModel_BatchCreate(t *testing.T) {
+	asserts := assert.New(t)
+	helperCreateDatabaseAndTable(asserts)
+
+	animal := Animal{}
+	err := animal.Init(&animal)
+	asserts.NoError(err)
+
+	// error: no slice of the model.
+	err = animal.BatchCreate([]Human{{Name: "Pat"}})
+	asserts.Error(err)
+	asserts.Equal(fmt.Sprintf(orm.ErrBatch, "orm_test.Animal"), err.Error())
+
+	// ok: 100 animals, the first with a relation.
+	animals := make([]*Animal, 100)
+	for i := range animals {
+		animals[i] = &Animal{Name: fmt.Sprintf("Animal-%d", i)}
+	}
+	animals[0].Toys = []Toy{{Name: "Ball"}}
+	err = animal.BatchCreate(animals)
+	asserts.NoError(err)
+	for i := range animals {
+		asserts.Equal(animals[0].ID+i, animals[i].ID)
+	}
+
+	count, err := animal.Count()
+	asserts.NoError(err)
+	asserts.Equal(100, count)
+
+	animal = Animal{}
+	err = animal.Init(&animal)
+	asserts.NoError(err)
+	err = animal.First(condition.New().SetWhere("id = ?", animals[0].ID))
+	asserts.NoError(err)
+	asserts.Equal("Animal-0", animal.Name)
+	asserts.Equal(1, len(animal.Toys))
+	asserts.Equal("Ball", animal.Toys[0].Name)
+}
+
+// AnimalMinToys is used to test the relation validation.
+type AnimalMinToys struct {
+	Base
+	Name string
+	Toys []Toy `orm:"refs:AnimalID" validate:"min=1"`
+}
+
+func (a AnimalMinToys) DefaultTableName() string {
+	return "animals"
+}
+
+// TestEager_Create_RelationValidation tests:
+// - If the validate tag of a relation is checked on create.
+func TestEager_Create_RelationValidation(t *testing.T) {
+	asserts := assert.New(t)
+	helperCreateDatabaseAndTable(asserts)
+
+	animal := AnimalMinToys{Name: "Blacky"}
+	err := animal.Init(&animal)
+	asserts.NoError(err)
+
+	// error: no toys.
+	err = animal.Create()
+	asserts.Error(err)
+	asserts.Contains(err.Error(), "'orm_test.AnimalMinToys' field 'Toys' on tag 'min'")
+	asserts.Equal(0, animal.ID)
+
+	// ok: one toy.
+	animal.Toys = []Toy{{Name: "Ball"}}
+	err = animal.Create()
+	asserts.NoError(err)
+	asserts.True(animal.ID > 0)
+	asserts.Equal(1, len(animal.Toys))
+}
+
+// AnimalKind is used to test the PolymorphicValuer interface.
+type AnimalKind struct {
+	Base
+	Name    string
+	Kind    string    `orm:"custom"`
+	ToyPoly []ToyPoly `orm:"poly:Toy;refs:AnimalID"`
+}
+
+func (a AnimalKind) DefaultTableName() string {
+	return "animals"
+}
+
+func (a AnimalKind) PolymorphicValue() string {
+	return a.Kind
+}
+
+// TestEager_Create_PolymorphicValuer tests:
+// - If the polymorphic value of the caller is used on create.
+// - If the default polymorphic value is used if the caller returns an empty string.
+func TestEager_Create_PolymorphicValuer(t *testing.T) {
+	asserts := assert.New(t)
+	helperCreateDatabaseAndTable(asserts)
+
+	values := map[string]string{"Cat": "Cat", "Dog": "Dog", "": "AnimalKind"}
+	for kind, polyValue := range values {
+		animal := AnimalKind{Name: "Blacky", Kind: kind, ToyPoly: []ToyPoly{{Name: "Ball"}}}
+		err := animal.Init(&animal)
+		asserts.NoError(err)
+
+		// ok: poly value of the caller.
+		err = animal.Create()
+		asserts.NoError(err)
+		asserts.Equal(polyValue, animal.ToyPoly[0].ToyType)
+
+		var toyType string
+		row, err := builder2.Query().Select("tests2.toy_polies").Columns("toy_type").Where("animal_id = ?", animal.ID).First()
+		asserts.NoError(err)
+		asserts.NoError(row.Scan(&toyType))
+		asserts.Equal(polyValue, toyType)
+
+		// ok: relation is loaded with the poly value of the caller.
+		animal.ToyPoly = nil
+		err = animal.First(condition.New().SetWhere("id = ?", animal.ID))
+		asserts.NoError(err)
+		asserts.Equal(1, len(animal.ToyPoly))
+	}
+}
+
+// builderLogValues is used to test the sensitive fields.
+var builderLogValues query.Builder
+
+// AnimalSecret is used to test the sensitive fields.
+type AnimalSecret struct {
+	Base
+	Name string `orm:"sensitive"`
+
+	Toys []ToySecret `orm:"refs:AnimalID"`
+}
+
+func (a AnimalSecret) DefaultTableName() string {
+	return "animals"
+}
+
+func (a AnimalSecret) DefaultBuilder() query.Builder {
+	return builderLogValues
+}
+
+// ToySecret is used to test the sensitive fields of hasMany relations.
+type ToySecret struct {
+	Base
+	Name     string `orm:"sensitive"`
+	AnimalID int
+}
+
+func (t ToySecret) DefaultTableName() string {
+	return "toys"
+}
+
+func (t ToySecret) DefaultBuilder() query.Builder {
+	return builderLogValues
+}
+
+// TestEager_Create_Sensitive tests:
+// - If the value of a sensitive field is redacted in the log.
+// - If the value of a sensitive field of a hasMany relation is redacted in the log.
+// - If the value is written to the database.
+func TestEager_Create_Sensitive(t *testing.T) {
+	asserts := assert.New(t)
+	helperCreateDatabaseAndTable(asserts)
+
+	cfg := testConfig()
+	cfg.LogValues = true
+	var err error
+	builderLogValues, err = query.New("mysql", cfg)
+	asserts.NoError(err)
+
+	animal := AnimalSecret{Name: "secret"}
+	err = animal.Init(&animal)
+	asserts.NoError(err)
+
+	var fields []logger.Fields
+	mLogger := new(loggerMocks.Manager)
+	mLogger.On("WithTimer").Return(mLogger)
+	mLogger.On("WithFields", mock.Anything).Run(func(args mock.Arguments) {
+		fields = append(fields, args.Get(0).(logger.Fields))
+	}).Return(mLogger)
+	mLogger.On("Debug", mock.Anything).Return()
+	builderLogValues.SetLogger(mLogger)
+	defer builderLogValues.SetLogger(nil)
+
+	// ok: value is redacted.
+	animal.Toys = []ToySecret{{Name: "secret-ball"}, {Name: "secret-bone"}}
+	err = animal.Create()
+	asserts.NoError(err)
+	var inserts []logger.Fields
+	for _, f := range fields {
+		if f["operation"] == "INSERT" {
+			inserts = append(inserts, f)
+		}
+	}
+	asserts.Equal(2, len(inserts))
+	for _, insert := range inserts {
+		asserts.Contains(insert["values"], query.Redacted)
+		asserts.NotContains(insert["values"], "secret")
+		asserts.NotContains(insert["values"], "secret-ball")
+		asserts.NotContains(insert["values"], "secret-bone")
+	}
+
+	// ok: value is written.
+	var name string
+	err = builder.Query().Select("animals").Columns("name").Where("id = ?", animal.ID).Scalar(&name)
+	asserts.NoError(err)
+	asserts.Equal("secret", name)
+	var toys int
+	err = builder.Query().Select("toys").Columns(query.DbExpr("COUNT(*)")).Where("animal_id = ? AND name IN (?)", animal.ID, []string{"secret-ball", "secret-bone"}).Scalar(&toys)
+	asserts.NoError(err)
+	asserts.Equal(2, toys)
+}
+
+// TestModel_Stats tests:
+// - If the affected rows of the root and relations are aggregated.
+// - If the stats are reset on the next operation.
+func TestModel_Stats(t *testing.T) {
+	asserts := assert.New(t)
+	helperCreateDatabaseAndTable(asserts)
+
+	animal := Animal{}
+	err := animal.Init(&animal)
+	asserts.NoError(err)
+	asserts.Equal(orm.Stats{}, animal.Stats())
+
+	// ok: parent with 2 children.
+	animal.Name = "Blacky"
+	animal.Toys = []Toy{{Name: "Ball"}, {Name: "Bone"}}
+	err = animal.Create()
+	asserts.NoError(err)
+	asserts.Equal(int64(3), animal.Stats().RowsAffected)
+	asserts.Equal(int64(0), animal.Stats().RowsRead)
+
+	// ok: stats are reset.
+	err = animal.First(condition.New().SetWhere("id = ?", animal.ID))
+	asserts.NoError(err)
+	asserts.Equal(int64(0), animal.Stats().RowsAffected)
+	asserts.True(animal.Stats().RowsRead >= 3)
+}
+
 // TestEager_Create tests:
 // - 0-3: tests all struct, ptr, slice, ptr slice values on hasOne, belongsTo, hasMany and m2m relations (+poly).
 // - 4	: belongsTo, m2m values are changed with an existing ID. The reference value should get updated.
@@ -70,6 +717,7 @@ func TestEager_Create_SelfRef(t *testing.T) {
 // - 6	: belongsTo, m2m values are set but only the reference (junction) entry should get created.
 // - 7  : test hasOne, belongsTo, hasMany and m2m with a nil as value. No entry/reference should get created.
 // - 8 	: test hasOne, belongsTo, hasMany and m2m with an empty value. No entry/reference should get created.
+// - db defaults are reselected after create, if the provider does not support returning.
 func TestEager_Create(t *testing.T) {
 	asserts := assert.New(t)
 	helperCreateDatabaseAndTable(asserts)
@@ -368,6 +1016,43 @@ func TestEager_Create(t *testing.T) {
 		asserts.True(animal.CreatedAt.Valid)
 	}
 
+	// ok: timestamps are not managed, the given created_at is preserved.
+	animal := Animal{}
+	err = animal.Init(&animal)
+	asserts.NoError(err)
+	scope, err := animal.Scope()
+	asserts.NoError(err)
+	scope.SetConfig(orm.NewConfig().SetAllowHasOneZero(true).SetManageTimestamps(false))
+	imported, _ := time.Parse("2006-01-02 15:04:05", "2019-05-05 08:00:00")
+	createdAt := query.NewNullTime(imported, true)
+	animal.Name = "Imported"
+	animal.CreatedAt = &createdAt
+	err = animal.Create()
+	asserts.NoError(err)
+	err = animal.First(condition.New().SetWhere("id = ?", animal.ID))
+	asserts.NoError(err)
+	asserts.True(animal.CreatedAt.Valid)
+	asserts.Equal(imported, animal.CreatedAt.Time)
+
+	// ok: db default is reselected after create, because mysql does not support returning.
+	_, err = builder.Query().DB().Exec("ALTER TABLE `animals` ALTER `created_at` SET DEFAULT '2019-05-05 08:00:00';")
+	asserts.NoError(err)
+	animal = Animal{}
+	err = animal.Init(&animal)
+	asserts.NoError(err)
+	scope, err = animal.Scope()
+	asserts.NoError(err)
+	scope.SetConfig(orm.NewConfig().SetAllowHasOneZero(true).SetManageTimestamps(false).SetReselectOnCreate(true))
+	animal.Name = "Defaulted"
+	err = animal.Create()
+	asserts.NoError(err)
+	asserts.True(animal.ID > 0)
+	asserts.NotNil(animal.CreatedAt)
+	asserts.True(animal.CreatedAt.Valid)
+	asserts.Equal(imported, animal.CreatedAt.Time)
+	_, err = builder.Query().DB().Exec("ALTER TABLE `animals` ALTER `created_at` DROP DEFAULT;")
+	asserts.NoError(err)
+
 	// tear down created_at test.
 	// delete because of the other tests which does not include the created_at field anymore in the db.
 	if c.Exist("orm_", "orm_test.Animal") {
@@ -375,3 +1060,66 @@ func TestEager_Create(t *testing.T) {
 		asserts.NoError(err)
 	}
 }
+
+// Husband is used to test circular belongsTo relations.
+type Husband struct {
+	Base
+	Name   string
+	WifeID query.NullInt
+	Wife   *Wife `orm:"relation:belongsTo"`
+}
+
+func (h Husband) DefaultTableName() string {
+	return "husbands"
+}
+
+// Wife is used to test circular belongsTo relations.
+type Wife struct {
+	Base
+	Name      string
+	HusbandID query.NullInt
+	Husband   *Husband `orm:"relation:belongsTo"`
+}
+
+func (w Wife) DefaultTableName() string {
+	return "wives"
+}
+
+// TestEager_Create_CircularBelongsTo tests:
+// - if a two-way circular belongsTo graph is created.
+// - if the foreign key of the second entry is updated after the root entry was inserted.
+func TestEager_Create_CircularBelongsTo(t *testing.T) {
+	asserts := assert.New(t)
+	helperCreateDatabaseAndTable(asserts)
+
+	_, err := builder.Query().DB().Exec("CREATE TABLE `husbands` (`id` int(11) unsigned NOT NULL AUTO_INCREMENT, `name` varchar(250) NOT NULL DEFAULT '', `wife_id` int(11) unsigned DEFAULT NULL, PRIMARY KEY (`id`)) ENGINE=InnoDB DEFAULT CHARSET=utf8;")
+	asserts.NoError(err)
+	_, err = builder.Query().DB().Exec("CREATE TABLE `wives` (`id` int(11) unsigned NOT NULL AUTO_INCREMENT, `name` varchar(250) NOT NULL DEFAULT '', `husband_id` int(11) unsigned DEFAULT NULL, PRIMARY KEY (`id`), CONSTRAINT `wives_husband` FOREIGN KEY (`husband_id`) REFERENCES `husbands` (`id`)) ENGINE=InnoDB DEFAULT CHARSET=utf8;")
+	asserts.NoError(err)
+	_, err = builder.Query().DB().Exec("ALTER TABLE `husbands` ADD CONSTRAINT `husbands_wife` FOREIGN KEY (`wife_id`) REFERENCES `wives` (`id`);")
+	asserts.NoError(err)
+
+	husband := Husband{Name: "John", Wife: &Wife{Name: "Jane"}}
+	husband.Wife.Husband = &husband
+	err = husband.Init(&husband)
+	asserts.NoError(err)
+
+	// ok
+	err = husband.Create()
+	asserts.NoError(err)
+	asserts.True(husband.ID > 0)
+	asserts.True(husband.Wife.ID > 0)
+	asserts.Equal(query.NewNullInt(int64(husband.Wife.ID), true), husband.WifeID)
+	asserts.Equal(query.NewNullInt(int64(husband.ID), true), husband.Wife.HusbandID)
+
+	// check the db entries.
+	var wifeID, husbandID int
+	row, err := builder.Query().Select("husbands").Columns("wife_id").Where("id = ?", husband.ID).First()
+	asserts.NoError(err)
+	asserts.NoError(row.Scan(&wifeID))
+	asserts.Equal(husband.Wife.ID, wifeID)
+	row, err = builder.Query().Select("wives").Columns("husband_id").Where("id = ?", husband.Wife.ID).First()
+	asserts.NoError(err)
+	asserts.NoError(row.Scan(&husbandID))
+	asserts.Equal(husband.ID, husbandID)
+}
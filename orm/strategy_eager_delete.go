@@ -33,7 +33,7 @@ func (e *eager) Delete(scope Scope, c condition.Condition) error {
 		// get builder...
 		relationScope, err := scope.NewScopeFromType(relation.Type)
 		if err != nil {
-			return err
+			return relationError(scope, relation, err)
 		}
 
 		switch relation.Kind {
@@ -45,33 +45,36 @@ func (e *eager) Delete(scope Scope, c condition.Condition) error {
 			if relation.IsPolymorphic() {
 				deleteSQL = relationScope.Builder().Query(scope.Model().tx).Delete(relationScope.FqdnTable()) // TODO tx is wrong, must be of relationScope to work on different dbs...
 				deleteSQL.Where(b.QuoteIdentifier(relation.Mapping.References.Information.Name)+" = ?", scope.FieldValue(relation.Mapping.ForeignKey.Name).Interface())
-				deleteSQL.Where(b.QuoteIdentifier(relation.Mapping.Polymorphic.TypeField.Information.Name)+" = ?", relation.Mapping.Polymorphic.Value)
+				deleteSQL.Where(b.QuoteIdentifier(relation.Mapping.Polymorphic.TypeField.Information.Name)+" = ?", polymorphicValue(scope.Caller(), relation))
 			} else {
 				deleteSQL = relationScope.Builder().Query(scope.Model().tx).Delete(relationScope.FqdnTable()) // TODO tx is wrong, must be of relationScope to work on different dbs...
 				deleteSQL.Where(b.QuoteIdentifier(relation.Mapping.References.Information.Name)+" = ?", scope.FieldValue(relation.Mapping.ForeignKey.Name).Interface())
 			}
-			_, err := deleteSQL.Exec()
+			res, err := deleteSQL.Exec()
 			if err != nil {
-				return err
+				return relationError(scope, relation, err)
 			}
+			scope.Model().addRowsAffected(res)
 		case ManyToMany:
 			// hasManyToMany - only junction table entries are getting deleted - for the association table use SQL CASCADE or a callbacks
 			deleteSQL := relationScope.Builder().Query(scope.Model().tx).Delete(relation.Mapping.Join.Table).Where(b.QuoteIdentifier(relation.Mapping.Join.ForeignColumnName)+" = ?", scope.FieldValue(relation.Mapping.ForeignKey.Name).Interface())
 			if relation.IsPolymorphic() {
-				deleteSQL.Where(relation.Mapping.Polymorphic.TypeField.Information.Name+" = ?", relation.Mapping.Polymorphic.Value)
+				deleteSQL.Where(relation.Mapping.Polymorphic.TypeField.Information.Name+" = ?", polymorphicValue(scope.Caller(), relation))
 			}
-			_, err := deleteSQL.Exec()
+			res, err := deleteSQL.Exec()
 			if err != nil {
-				return err
+				return relationError(scope, relation, err)
 			}
+			scope.Model().addRowsAffected(res)
 		}
 	}
 
 	// exec
-	_, err := b.Query(scope.Model().tx).Delete(scope.FqdnTable()).Condition(c).Exec()
+	res, err := b.Query(scope.Model().tx).Delete(scope.FqdnTable()).Condition(c).Exec()
 	if err != nil {
 		return err
 	}
+	scope.Model().addRowsAffected(res)
 
 	return nil
 }
@@ -6,6 +6,7 @@ package orm_test
 
 import (
 	"database/sql"
+	"fmt"
 	"testing"
 
 	_ "github.com/patrickascher/gofer/cache/memory"
@@ -78,6 +79,63 @@ func TestEager_Delete_SoftDelete(t *testing.T) {
 	helperTestResults(asserts, err, helperTestCases()[0], animal, false)
 }
 
+// AnimalFlag is used to test a soft delete with a custom predicate.
+type AnimalFlag struct {
+	Base
+	Name      string
+	IsDeleted bool
+}
+
+func (a AnimalFlag) DefaultTableName() string {
+	return "animals"
+}
+
+func (a AnimalFlag) DefaultSoftDelete() orm.SoftDelete {
+	return orm.SoftDelete{Field: "IsDeleted", Value: 1, Predicate: "%s = 0"}
+}
+
+// TestEager_Delete_SoftDeletePredicate tests:
+// - If the custom predicate is used to exclude soft deleted rows.
+// - If the custom flag is set on delete.
+func TestEager_Delete_SoftDeletePredicate(t *testing.T) {
+	asserts := assert.New(t)
+	helperCreateDatabaseAndTable(asserts)
+	insertUserData(asserts)
+
+	_, err := builder.Query().DB().Exec("ALTER TABLE `animals` ADD `is_deleted` tinyint(1) NOT NULL DEFAULT 0;")
+	asserts.NoError(err)
+	_, err = builder.Query().DB().Exec("UPDATE `animals` SET `is_deleted` = 1 WHERE `id` = 2;")
+	asserts.NoError(err)
+
+	// init orm model
+	animal := AnimalFlag{}
+	err = animal.Init(&animal)
+	asserts.NoError(err)
+
+	// no result because the row is flagged as deleted.
+	err = animal.First(condition.New().SetWhere("id = ?", 2))
+	asserts.Error(err)
+	asserts.Equal(sql.ErrNoRows, err)
+
+	// ok: fetch id 1
+	err = animal.First(condition.New().SetWhere("id = ?", 1))
+	asserts.NoError(err)
+	asserts.Equal("Blacky", animal.Name)
+
+	// delete entry.
+	err = animal.Delete()
+	asserts.NoError(err)
+
+	// flag is set.
+	var flag int
+	err = builder.Query().DB().QueryRow("SELECT `is_deleted` FROM `animals` WHERE `id` = 1").Scan(&flag)
+	asserts.NoError(err)
+	asserts.Equal(1, flag)
+	err = animal.First(condition.New().SetWhere("id = ?", 1))
+	asserts.Error(err)
+	asserts.Equal(sql.ErrNoRows, err)
+}
+
 // TestEager_Delete tests:
 // - If the orm gets completely deleted if no soft_delete exists.
 // - If all relations are getting deleted correctly (hasOne, hasMany - all, m2m, belongsTo only reference)
@@ -169,3 +227,102 @@ func TestEager_Delete(t *testing.T) {
 	err = res.Close()
 	asserts.Equal(0, rows)
 }
+
+// TestEager_Delete_All tests:
+// - If an error returns if no primary key is set.
+// - If all rows are deleted if SetAllowDeleteAll is set.
+// - If the relation entries are not deleted if SetAllowDeleteAll is set.
+func TestEager_Delete_All(t *testing.T) {
+	asserts := assert.New(t)
+	helperCreateDatabaseAndTable(asserts)
+	insertUserData(asserts)
+
+	// init orm model
+	toy := Toy{}
+	err := toy.Init(&toy)
+	asserts.NoError(err)
+	count, err := toy.Count()
+	asserts.NoError(err)
+	asserts.True(count > 0)
+
+	// error - no condition.
+	err = toy.Delete()
+	asserts.Error(err)
+	asserts.Equal(fmt.Sprintf(orm.ErrNoCondition, "orm_test.Toy"), err.Error())
+	count2, err := toy.Count()
+	asserts.NoError(err)
+	asserts.Equal(count, count2)
+
+	// ok - delete all is allowed.
+	scope, err := toy.Scope()
+	asserts.NoError(err)
+	scope.SetConfig(orm.NewConfig().SetAllowDeleteAll(true))
+	err = toy.Delete()
+	asserts.NoError(err)
+	count, err = toy.Count()
+	asserts.NoError(err)
+	asserts.Equal(0, count)
+
+	// ok - relation entries are not deleted (animals without soft delete).
+	helperCreateDatabaseAndTable(asserts)
+	insertUserData(asserts)
+	_, err = builder.Query().DB().Exec("ALTER TABLE `tests`.`animals` DROP COLUMN `deleted_at`")
+	asserts.NoError(err)
+	_, err = builder.Query().DB().Exec("INSERT INTO `tests`.`toys` (`name`, `animal_id`) VALUES ('Orphan', 0)")
+	asserts.NoError(err)
+	_, err = builder.Query().DB().Exec("INSERT INTO `tests`.`animal_walkers` (`animal_id`, `human_id`) VALUES (0, 1)")
+	asserts.NoError(err)
+	var toys, walkers int
+	asserts.NoError(builder.Query().DB().QueryRow("SELECT COUNT(*) FROM `tests`.`toys`").Scan(&toys))
+	asserts.NoError(builder.Query().DB().QueryRow("SELECT COUNT(*) FROM `tests`.`animal_walkers`").Scan(&walkers))
+
+	animal := Animal{}
+	err = animal.Init(&animal)
+	asserts.NoError(err)
+	scope, err = animal.Scope()
+	asserts.NoError(err)
+	scope.SetConfig(orm.NewConfig().SetAllowDeleteAll(true))
+	err = animal.Delete()
+	asserts.NoError(err)
+	var animals, toys2, walkers2 int
+	asserts.NoError(builder.Query().DB().QueryRow("SELECT COUNT(*) FROM `tests`.`animals`").Scan(&animals))
+	asserts.NoError(builder.Query().DB().QueryRow("SELECT COUNT(*) FROM `tests`.`toys`").Scan(&toys2))
+	asserts.NoError(builder.Query().DB().QueryRow("SELECT COUNT(*) FROM `tests`.`animal_walkers`").Scan(&walkers2))
+	asserts.Equal(0, animals)
+	asserts.Equal(toys, toys2)
+	asserts.Equal(walkers, walkers2)
+}
+
+// TestModel_DeleteReturning tests:
+// - error if no where condition is set.
+// - If the deleted rows are returned and do not exist afterwards.
+func TestModel_DeleteReturning(t *testing.T) {
+	asserts := assert.New(t)
+	helperCreateDatabaseAndTable(asserts)
+	insertUserData(asserts)
+
+	toy := Toy{}
+	err := toy.Init(&toy)
+	asserts.NoError(err)
+
+	// error: no condition.
+	var toys []Toy
+	err = toy.DeleteReturning(condition.New(), &toys)
+	asserts.Error(err)
+	asserts.Equal(fmt.Sprintf(orm.ErrNoCondition, "orm_test.Toy"), err.Error())
+
+	// ok: deleted rows are returned.
+	err = toy.DeleteReturning(condition.New().SetWhere("animal_id = ?", 1), &toys)
+	asserts.NoError(err)
+	asserts.Equal(2, len(toys))
+	asserts.Equal("Bone", toys[0].Name)
+	asserts.Equal("Kong", toys[1].Name)
+	asserts.Equal(int64(2), toy.Stats().RowsAffected)
+
+	count, err := toy.Count(condition.New().SetWhere("animal_id = ?", 1))
+	asserts.NoError(err)
+	asserts.Equal(0, count)
+	count, err = toy.Count()
+	asserts.NoError(err)
+	asserts.Equal(2, count)
+}
@@ -8,6 +8,7 @@ import (
 	"database/sql"
 	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/patrickascher/gofer/query"
 	"github.com/patrickascher/gofer/query/condition"
@@ -27,6 +28,8 @@ var (
 //
 // HasOne, BelongsTo: will call orm First().
 // HasMany, ManyToMany will call orm All().
+// If a relation loader is configured, it will be called instead of the sql select.
+// A requested row lock, the configured index hints and select expressions are added to the select.
 func (e *eager) First(scope Scope, c condition.Condition, perm Permission) error {
 
 	b := scope.Builder()
@@ -35,20 +38,44 @@ func (e *eager) First(scope Scope, c condition.Condition, perm Permission) error
 	addSoftDeleteCondition(scope, scope.Config(), c)
 
 	// create the select
-	row, err := b.Query().Select(scope.FqdnTable()).Columns(scope.SQLColumns(perm)...).Condition(c).First()
+	sel := b.Query(scope.Model().tx).Select(scope.FqdnTable()).Columns(scope.SQLColumns(perm)...).Condition(c)
+	if lock := scope.Model().lock; lock != "" {
+		sel.Lock(lock)
+	}
+	for _, hint := range scope.Config().indexHints {
+		sel.IndexHint("", hint.Index, hint.Kind)
+	}
+	for _, expr := range scope.Config().selectExpressions {
+		sel.ColumnExpression(expr.expr, expr.alias)
+	}
+	row, err := sel.First()
 	if err != nil {
 		return err
 	}
 
-	err = row.Scan(scope.SQLScanFields(perm)...)
+	err = row.Scan(scope.SQLScanFieldsByColumns(sel.Projection())...)
+	if err != nil {
+		return err
+	}
+	scope.Model().addRowsRead(1)
+	err = readTransform(scope, perm)
 	if err != nil {
 		return err
 	}
 
+	// set back reference on example for belongsTo and hasOne if the relations was already loaded.
+	var relations []Relation
 	for _, relation := range scope.SQLRelations(perm) {
-		// set back reference on example for belongsTo and hasOne if the relations was already loaded.
 		if err := scope.SetBackReference(relation); err == nil {
-			return nil
+			break
+		}
+		relations = append(relations, relation)
+	}
+
+	return loadRelations(scope, relations, func(relation Relation) error {
+		// custom relation loader.
+		if loader, ok := scope.Config().relationLoader[relation.Field]; ok {
+			return loader([]Interface{scope.Caller()})
 		}
 
 		// initialize the relation.
@@ -71,9 +98,9 @@ func (e *eager) First(scope Scope, c condition.Condition, perm Permission) error
 				if err == sql.ErrNoRows && config.allowHasOneZero {
 					// set a zero value
 					scope.FieldValue(relation.Field).Set(reflect.New(relation.Type).Elem())
-					continue
+					return nil
 				}
-				return fmt.Errorf(ErrNoRows, scope.FqdnModel(relation.Field), err)
+				return err
 			}
 		case HasMany, ManyToMany:
 			// create condition
@@ -91,8 +118,8 @@ func (e *eager) First(scope Scope, c condition.Condition, perm Permission) error
 					subQuery := b.Query().
 						Select(scope.Builder().QuoteIdentifier(relation.Mapping.Join.Table)).
 						Columns(relation.Mapping.Join.ReferencesColumnName).Where(scope.Builder().QuoteIdentifier(relation.Mapping.Join.ForeignColumnName)+" = ?", scope.FieldValue(relation.Mapping.ForeignKey.Name).Interface())
-					if relation.IsPolymorphic() {
-						subQuery.Where(scope.Builder().QuoteIdentifier(relation.Mapping.Polymorphic.TypeField.Information.Name)+" = ?", relation.Mapping.Polymorphic.Value)
+					if relation.IsPolymorphic() && !config.polymorphicAnyOwner {
+						subQuery.Where(scope.Builder().QuoteIdentifier(relation.Mapping.Polymorphic.TypeField.Information.Name)+" = ?", polymorphicValue(scope.Caller(), relation))
 					}
 					rows, err := subQuery.All()
 					if err != nil {
@@ -132,7 +159,7 @@ func (e *eager) First(scope Scope, c condition.Condition, perm Permission) error
 						}
 					} else {
 						// no result to load
-						continue
+						return nil
 					}
 				}
 			}
@@ -141,15 +168,22 @@ func (e *eager) First(scope Scope, c condition.Condition, perm Permission) error
 			// this is needed if something like orm - update - first was called (there could be some manually added slices).
 			scope.FieldValue(relation.Field).Set(reflect.New(relation.Type).Elem())
 
+			// top-N of the relation.
+			if limit := scope.Config().relationLimit[relation.Field]; limit > 0 && relation.Kind == HasMany {
+				c = c.Copy().SetLimit(limit)
+				if len(c.Order()) == 0 {
+					c.SetOrder(relationOrder(&rel.model().scope)...)
+				}
+			}
+
 			// fetch data
 			err = rel.All(scope.FieldValue(relation.Field).Addr().Interface(), c)
 			if err != nil {
 				return err
 			}
 		}
-	}
-
-	return nil
+		return nil
+	})
 }
 
 // All rows by the given condition will be fetched.
@@ -167,7 +201,17 @@ func (e *eager) All(res interface{}, scope Scope, c condition.Condition) error {
 	addSoftDeleteCondition(scope, scope.Config(), c)
 
 	// build select
-	rows, err := b.Query().Select(scope.FqdnTable()).Columns(scope.SQLColumns(perm)...).Condition(c).All()
+	sel := b.Query(scope.Model().tx).Select(scope.FqdnTable()).Columns(scope.SQLColumns(perm)...).Condition(c)
+	if lock := scope.Model().lock; lock != "" {
+		sel.Lock(lock)
+	}
+	for _, hint := range scope.Config().indexHints {
+		sel.IndexHint("", hint.Index, hint.Kind)
+	}
+	for _, expr := range scope.Config().selectExpressions {
+		sel.ColumnExpression(expr.expr, expr.alias)
+	}
+	rows, err := sel.All()
 	if err != nil {
 		return err
 	}
@@ -190,7 +234,12 @@ func (e *eager) All(res interface{}, scope Scope, c condition.Condition) error {
 			return err
 		}
 		//add the values
-		err = rows.Scan(cScope.SQLScanFields(perm)...)
+		err = rows.Scan(cScope.SQLScanFieldsByColumns(sel.Projection())...)
+		if err != nil {
+			return err
+		}
+		scope.Model().addRowsRead(1)
+		err = readTransform(cScope, perm)
 		if err != nil {
 			return err
 		}
@@ -209,7 +258,37 @@ func (e *eager) All(res interface{}, scope Scope, c condition.Condition) error {
 		return nil
 	}
 
+	err = e.allRelations(scope, c, resultSlice)
+	if err != nil {
+		return err
+	}
+
+	// TODO Backref for ALL
+	// Here must be checked if its the root level (model.parent == nil). Then the struct has to get checked against the BelongsTo Back reference in a for loop and has to get set.
+	// At the moment this is not important and will maybe be implemented in the future. If its implemented, the back reference which exists now, can be deleted.
+
+	reflect.ValueOf(res).Elem().Set(resultSlice)
+
+	return nil
+}
+
+// Preload loads the relations into the already populated parents slice.
+func (e *eager) Preload(parents interface{}, scope Scope) error {
+	resultSlice := reflect.ValueOf(parents)
+	if resultSlice.Len() == 0 {
+		return nil
+	}
+	return e.allRelations(scope, condition.New(), resultSlice)
+}
+
+// allRelations loads the relations of all entries in resultSlice.
+// The foreign keys of all parents are collected, so that every relation is requested only once.
+func (e *eager) allRelations(scope Scope, c condition.Condition, resultSlice reflect.Value) error {
+	b := scope.Builder()
+	perm := Permission{Read: true}
+
 	in := map[string][]interface{}{}
+	var relations []Relation
 	for _, relation := range scope.SQLRelations(perm) {
 
 		// set back reference on example for belongsTo and hasOne if the relations was already loaded.
@@ -251,41 +330,63 @@ func (e *eager) All(res interface{}, scope Scope, c condition.Condition) error {
 			}
 		}
 
+		relations = append(relations, relation)
+	}
+
+	err := loadRelations(scope, relations, func(relation Relation) error {
+		// custom relation loader, all parents are passed at once.
+		if loader, ok := scope.Config().relationLoader[relation.Field]; ok {
+			parents := make([]Interface, resultSlice.Len())
+			for n := 0; n < resultSlice.Len(); n++ {
+				parents[n] = reflect.Indirect(resultSlice.Index(n)).Addr().Interface().(Interface)
+			}
+			return loader(parents)
+		}
+
+		f := relation.Mapping.ForeignKey.Name
+
 		// fetch all m2m keys of the result map
 		// m2mMapping will hold all mapping information to remap the result later on. The mapping is kept as string type.
 		// m2mAll keeps all IDs which should be loaded to minimize the sql queries.
 		// m2m poly is implemented
+		// the parents are grouped by their polymorphic value, because it can differ per parent (PolymorphicValuer).
+		groups, err := polymorphicGroups(resultSlice, relation, scope.Config(relation.Field).polymorphicAnyOwner, in[f])
+		if err != nil {
+			return err
+		}
+
 		m2mMapping := map[string][]interface{}{}
 		var m2mAll []interface{}
 		if relation.Kind == ManyToMany {
-
-			c := condition.New().SetWhere(b.QuoteIdentifier(relation.Mapping.Join.ForeignColumnName)+" IN (?)", in[relation.Mapping.ForeignKey.Name])
-			cols := []string{relation.Mapping.Join.ForeignColumnName, relation.Mapping.Join.ReferencesColumnName}
-			if relation.IsPolymorphic() {
-				c.SetWhere(b.QuoteIdentifier(relation.Mapping.Polymorphic.TypeField.Information.Name)+" = ?", relation.Mapping.Polymorphic.Value)
-			}
-			rows, err := b.Query().Select(relation.Mapping.Join.Table).Columns(cols...).Condition(c).All()
-			if err != nil {
-				return err
-			}
-
-			// map fk,afk
-			for rows.Next() {
-				var fk string
-				var afk string
-				err = rows.Scan(&fk, &afk)
+			for _, group := range groups {
+				c := condition.New().SetWhere(b.QuoteIdentifier(relation.Mapping.Join.ForeignColumnName)+" IN (?)", group.in)
+				cols := []string{relation.Mapping.Join.ForeignColumnName, relation.Mapping.Join.ReferencesColumnName}
+				if group.polymorphic {
+					c.SetWhere(b.QuoteIdentifier(relation.Mapping.Polymorphic.TypeField.Information.Name)+" = ?", group.value)
+				}
+				rows, err := b.Query().Select(relation.Mapping.Join.Table).Columns(cols...).Condition(c).All()
 				if err != nil {
 					return err
 				}
-				m2mMapping[fk] = append(m2mMapping[fk], afk)
-				if _, exists := slicer.InterfaceExists(m2mAll, afk); !exists {
-					m2mAll = append(m2mAll, afk)
+
+				// map fk,afk
+				for rows.Next() {
+					var fk string
+					var afk string
+					err = rows.Scan(&fk, &afk)
+					if err != nil {
+						return err
+					}
+					m2mMapping[fk] = append(m2mMapping[fk], afk)
+					if _, exists := slicer.InterfaceExists(m2mAll, afk); !exists {
+						m2mAll = append(m2mAll, afk)
+					}
 				}
-			}
 
-			err = rows.Close()
-			if err != nil {
-				return err
+				err = rows.Close()
+				if err != nil {
+					return err
+				}
 			}
 		}
 
@@ -311,7 +412,23 @@ func (e *eager) All(res interface{}, scope Scope, c condition.Condition) error {
 			// create condition
 			var c condition.Condition
 			if relation.Kind != ManyToMany {
-				c = e.createWhere(&rModel.model().scope, relation, config, in[f])
+				// every polymorphic group is requested with its own value.
+				for _, group := range groups {
+					if len(group.in) == 0 {
+						continue
+					}
+					c = e.createPolymorphicWhere(&rModel.model().scope, relation, config, group.in, group.value)
+					gRes := reflect.New(reflect.ValueOf(rRes).Elem().Type()).Interface()
+					if limit := scope.Config().relationLimit[relation.Field]; limit > 0 && relation.Kind == HasMany {
+						err = allLimited(rModel, relation, c, group.in, limit, gRes)
+					} else {
+						err = rModel.All(gRes, c)
+					}
+					if err != nil {
+						return err
+					}
+					reflect.ValueOf(rRes).Elem().Set(reflect.AppendSlice(reflect.ValueOf(rRes).Elem(), reflect.ValueOf(gRes).Elem()))
+				}
 			} else {
 				manualCondition, reset := config.Condition()
 				if reset {
@@ -325,12 +442,12 @@ func (e *eager) All(res interface{}, scope Scope, c condition.Condition) error {
 				if manualCondition != nil {
 					c.Merge(manualCondition)
 				}
-			}
 
-			// request all relation data
-			err = rModel.All(rRes, c)
-			if err != nil {
-				return err
+				// request all relation data
+				err = rModel.All(rRes, c)
+				if err != nil {
+					return err
+				}
 			}
 
 			// mapping the result data back to the orm models.
@@ -375,13 +492,97 @@ func (e *eager) All(res interface{}, scope Scope, c condition.Condition) error {
 				}
 			}
 		}
+		return nil
+	})
+	return err
+}
+
+// polymorphicGroup holds the foreign keys of all parents with the same polymorphic value.
+type polymorphicGroup struct {
+	value       string
+	polymorphic bool
+	in          []interface{}
+}
+
+// polymorphicGroups groups the foreign keys of the parents by their polymorphic value.
+// If the relation is not polymorphic or any owner is allowed, one group with all foreign keys is returned.
+func polymorphicGroups(resultSlice reflect.Value, relation Relation, anyOwner bool, in []interface{}) ([]polymorphicGroup, error) {
+	if !relation.IsPolymorphic() || anyOwner {
+		return []polymorphicGroup{{in: in}}, nil
 	}
 
-	// TODO Backref for ALL
-	// Here must be checked if its the root level (model.parent == nil). Then the struct has to get checked against the BelongsTo Back reference in a for loop and has to get set.
-	// At the moment this is not important and will maybe be implemented in the future. If its implemented, the back reference which exists now, can be deleted.
+	var groups []polymorphicGroup
+	index := map[string]int{}
+	for n := 0; n < resultSlice.Len(); n++ {
+		parent := reflect.Indirect(resultSlice.Index(n))
+		fk, err := query.SanitizeInterfaceValue(parent.FieldByName(relation.Mapping.ForeignKey.Name).Interface())
+		if err != nil {
+			return nil, err
+		}
+		value := polymorphicValue(parent.Addr().Interface().(Interface), relation)
+		i, ok := index[value]
+		if !ok {
+			i = len(groups)
+			index[value] = i
+			groups = append(groups, polymorphicGroup{value: value, polymorphic: true})
+		}
+		if _, exist := slicer.InterfaceExists(groups[i].in, fk); !exist {
+			groups[i].in = append(groups[i].in, fk)
+		}
+	}
+	return groups, nil
+}
 
-	reflect.ValueOf(res).Elem().Set(resultSlice)
+// relationOrder returns the primary keys of the scope as order columns.
+func relationOrder(relScope Scope) []string {
+	var order []string
+	pk, _ := relScope.PrimaryKeys()
+	for _, p := range pk {
+		order = append(order, relScope.Builder().QuoteIdentifier(p.Information.Name)+" ASC")
+	}
+	return order
+}
+
+// allLimited loads max limit relation entries per parent.
+// If the provider supports window functions, the entries are numbered by ROW_NUMBER() partitioned by the reference column.
+// Otherwise the relation is requested per parent.
+func allLimited(rel Interface, relation Relation, c condition.Condition, in []interface{}, limit int, res interface{}) error {
+	relScope := &rel.model().scope
+	b := relScope.Builder()
+
+	order := c.Order()
+	if len(order) == 0 {
+		order = relationOrder(relScope)
+	}
+	pk, err := relScope.PrimaryKeys()
+	if err != nil {
+		return err
+	}
+	ref := b.QuoteIdentifier(relation.Mapping.References.Information.Name)
+
+	// window function
+	if b.Capabilities().Supports(query.CapWindowFunctions) && len(pk) == 1 {
+		var where []string
+		var args []interface{}
+		for _, w := range c.Where() {
+			where = append(where, "("+w.Condition()+")")
+			args = append(args, w.Arguments()...)
+		}
+		id := b.QuoteIdentifier(pk[0].Information.Name)
+		wc := c.Copy().SetWhere(fmt.Sprintf("%s IN (SELECT %s FROM (SELECT %s, ROW_NUMBER() OVER (PARTITION BY %s ORDER BY %s) AS orm_row FROM %s WHERE %s) orm_limit WHERE orm_row <= %d)",
+			id, id, id, ref, strings.Join(order, ", "), relScope.FqdnTable(), strings.Join(where, " AND "), limit), args...)
+		return rel.All(res, wc)
+	}
 
+	// request per parent.
+	rv := reflect.ValueOf(res).Elem()
+	for _, id := range in {
+		tmp := reflect.New(rv.Type())
+		err = rel.All(tmp.Interface(), c.Copy().SetWhere(ref+" = ?", id).SetLimit(limit).SetOrder(order...))
+		if err != nil {
+			return err
+		}
+		rv.Set(reflect.AppendSlice(rv, tmp.Elem()))
+	}
 	return nil
 }
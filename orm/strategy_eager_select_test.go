@@ -6,9 +6,12 @@ package orm_test
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -46,7 +49,882 @@ func TestEager_First_DBLoopDetection(t *testing.T) {
 	// infinity loop Loop1 has Loop2 has Loop1 = infinity loop.
 	err = role.First(condition.New().SetWhere("id = ?", 4))
 	asserts.Error(err)
-	asserts.Equal(fmt.Sprintf(orm.ErrInfinityLoop, "orm_test.Role"), err.Error())
+	var relErr *orm.RelationError
+	asserts.True(errors.As(err, &relErr))
+	asserts.Equal("Roles", relErr.Field)
+	asserts.Contains(err.Error(), fmt.Sprintf(orm.ErrInfinityLoop, "orm_test.Role"))
+}
+
+// TestEager_First_ReadTransform tests:
+// - If a registered read transform is called after First.
+// - If the transform can be removed again.
+func TestEager_First_ReadTransform(t *testing.T) {
+	asserts := assert.New(t)
+
+	helperCreateDatabaseAndTable(asserts)
+	insertUserData(asserts)
+
+	// Init user model.
+	role := Role{}
+	err := role.Init(&role)
+	asserts.NoError(err)
+
+	orm.RegisterReadTransform("orm_test.Role:Name", func(v reflect.Value) error {
+		v.SetString(strings.ToUpper(v.String()))
+		return nil
+	})
+
+	// ok - name is uppercased.
+	err = role.First(condition.New().SetWhere("id = ?", 1))
+	asserts.NoError(err)
+	asserts.Equal("ROLEA", role.Name)
+
+	// ok - transform removed.
+	orm.RegisterReadTransform("orm_test.Role:Name", nil)
+	err = role.First(condition.New().SetWhere("id = ?", 1))
+	asserts.NoError(err)
+	asserts.Equal("RoleA", role.Name)
+}
+
+// TestModel_Paginate tests:
+// - If the page rows and the pagination information are returned.
+// - If an error returns on an invalid page or perPage.
+func TestModel_Paginate(t *testing.T) {
+	asserts := assert.New(t)
+
+	helperCreateDatabaseAndTable(asserts)
+	insertUserData(asserts)
+
+	animal := Animal{}
+	err := animal.Init(&animal)
+	asserts.NoError(err)
+
+	// ok - first page, soft deleted Nala is excluded.
+	res, p, err := animal.Paginate(condition.New().SetOrder("id"), 1, 2)
+	asserts.NoError(err)
+	asserts.Equal(orm.Pagination{Total: 3, TotalPages: 2, CurrentPage: 1, PerPage: 2}, p)
+	asserts.Equal(2, len(res.([]Animal)))
+	asserts.Equal("Blacky", res.([]Animal)[0].Name)
+	asserts.Equal("Snowflake", res.([]Animal)[1].Name)
+
+	// ok - last page.
+	res, p, err = animal.Paginate(condition.New().SetOrder("id"), 2, 2)
+	asserts.NoError(err)
+	asserts.Equal(orm.Pagination{Total: 3, TotalPages: 2, CurrentPage: 2, PerPage: 2}, p)
+	asserts.Equal(1, len(res.([]Animal)))
+	asserts.Equal("Simba", res.([]Animal)[0].Name)
+
+	// ok - page out of range.
+	res, _, err = animal.Paginate(nil, 3, 2)
+	asserts.NoError(err)
+	asserts.Equal(0, len(res.([]Animal)))
+
+	// error - invalid page.
+	_, _, err = animal.Paginate(nil, 0, 2)
+	asserts.Error(err)
+	asserts.Equal(fmt.Sprintf(orm.ErrPaginate, 0, 2, "orm_test.Animal"), err.Error())
+}
+
+// TestModel_Chunk tests:
+// - if the rows are fetched in batches ordered by the primary key.
+// - if the requested relations are used for all batches.
+// - if chunk stops when the callback returns an error.
+// - error: invalid size.
+func TestModel_Chunk(t *testing.T) {
+	asserts := assert.New(t)
+
+	helperCreateDatabaseAndTable(asserts)
+	_, err := builder.Query().DB().Exec("DELETE FROM `tests`.`toys`")
+	asserts.NoError(err)
+	var values []map[string]interface{}
+	for i := 1; i <= 250; i++ {
+		values = append(values, map[string]interface{}{"id": i, "name": fmt.Sprintf("Toy %d", i), "animal_id": 1})
+	}
+	_, err = builder.Query().Insert("tests.toys").Values(values).Exec()
+	asserts.NoError(err)
+
+	toy := Toy{}
+	err = toy.Init(&toy)
+	asserts.NoError(err)
+
+	// ok
+	var sizes []int
+	last := 0
+	err = toy.WithoutRelations().Chunk(100, nil, func(batch interface{}) error {
+		toys := batch.([]Toy)
+		sizes = append(sizes, len(toys))
+		for _, item := range toys {
+			asserts.Equal(last+1, item.ID)
+			asserts.Nil(item.AnimalRef)
+			last = item.ID
+		}
+		return nil
+	})
+	asserts.NoError(err)
+	asserts.Equal([]int{100, 100, 50}, sizes)
+
+	// ok: stop on callback error.
+	calls := 0
+	err = toy.Chunk(100, condition.New().SetWhere("id > ?", 50), func(batch interface{}) error {
+		calls++
+		return errors.New("stop")
+	})
+	asserts.Error(err)
+	asserts.Equal("stop", err.Error())
+	asserts.Equal(1, calls)
+
+	// error: invalid size.
+	err = toy.Chunk(0, nil, func(batch interface{}) error { return nil })
+	asserts.Error(err)
+	asserts.Equal(fmt.Sprintf(orm.ErrChunk, 0, "orm_test.Toy"), err.Error())
+}
+
+// TestEager_First_PolymorphicAnyOwner tests:
+// - If only the rows of the owner type are loaded by default.
+// - If all rows of the owner are loaded if the config is set.
+func TestEager_First_PolymorphicAnyOwner(t *testing.T) {
+	asserts := assert.New(t)
+
+	helperCreateDatabaseAndTable(asserts)
+	insertUserData(asserts)
+
+	// ok - only type Animal.
+	animal := Animal{}
+	err := animal.Init(&animal)
+	asserts.NoError(err)
+	err = animal.First(condition.New().SetWhere("id = ?", 1))
+	asserts.NoError(err)
+	asserts.Equal(2, len(animal.ToyPoly))
+	for _, toy := range animal.ToyPoly {
+		asserts.Equal("Animal", toy.ToyType)
+	}
+
+	// ok - any owner type.
+	animal = Animal{}
+	err = animal.Init(&animal)
+	asserts.NoError(err)
+	scope, err := animal.Scope()
+	asserts.NoError(err)
+	scope.SetConfig(orm.NewConfig().SetPolymorphicAnyOwner(true), "ToyPoly")
+	err = animal.First(condition.New().SetWhere("id = ?", 1))
+	asserts.NoError(err)
+	asserts.Equal(3, len(animal.ToyPoly))
+}
+
+// AnimalNamed is used to test the PolymorphicValuer interface on loading all owners.
+type AnimalNamed struct {
+	Base
+	Name    string
+	ToyPoly []ToyPoly `orm:"poly:Toy;refs:AnimalID"`
+}
+
+func (a AnimalNamed) DefaultTableName() string {
+	return "animals"
+}
+
+func (a AnimalNamed) PolymorphicValue() string {
+	return a.Name
+}
+
+// TestEager_All_PolymorphicValuer tests:
+// - If the relations of owners with different polymorphic values are loaded with their own value.
+func TestEager_All_PolymorphicValuer(t *testing.T) {
+	asserts := assert.New(t)
+	helperCreateDatabaseAndTable(asserts)
+
+	for name, toy := range map[string]string{"Cat": "Mouse", "Dog": "Bone"} {
+		animal := AnimalNamed{Name: name, ToyPoly: []ToyPoly{{Name: toy}}}
+		err := animal.Init(&animal)
+		asserts.NoError(err)
+		err = animal.Create()
+		asserts.NoError(err)
+		asserts.Equal(name, animal.ToyPoly[0].ToyType)
+	}
+
+	animal := AnimalNamed{}
+	err := animal.Init(&animal)
+	asserts.NoError(err)
+	var res []AnimalNamed
+	err = animal.All(&res, condition.New().SetOrder("name"))
+	asserts.NoError(err)
+	asserts.Equal(2, len(res))
+	asserts.Equal("Cat", res[0].Name)
+	asserts.Equal(1, len(res[0].ToyPoly))
+	asserts.Equal("Mouse", res[0].ToyPoly[0].Name)
+	asserts.Equal("Cat", res[0].ToyPoly[0].ToyType)
+	asserts.Equal("Dog", res[1].Name)
+	asserts.Equal(1, len(res[1].ToyPoly))
+	asserts.Equal("Bone", res[1].ToyPoly[0].Name)
+	asserts.Equal("Dog", res[1].ToyPoly[0].ToyType)
+}
+
+// TestModel_Reset tests:
+// - If the field and relation values are cleared.
+// - If no stale relation rows are carried over to the next fetch.
+func TestModel_Reset(t *testing.T) {
+	asserts := assert.New(t)
+
+	helperCreateDatabaseAndTable(asserts)
+	insertUserData(asserts)
+
+	// error - not init.
+	animal := Animal{}
+	err := animal.Reset()
+	asserts.Error(err)
+
+	err = animal.Init(&animal)
+	asserts.NoError(err)
+	err = animal.First(condition.New().SetWhere("id = ?", 1))
+	asserts.NoError(err)
+	asserts.Equal(2, len(animal.Toys))
+
+	// ok - values are cleared.
+	err = animal.Reset()
+	asserts.NoError(err)
+	asserts.Equal(0, animal.ID)
+	asserts.Equal("", animal.Name)
+	asserts.Equal(0, len(animal.Toys))
+	asserts.Nil(animal.SpeciesPtr)
+
+	// ok - fresh fetch.
+	err = animal.First(condition.New().SetWhere("id = ?", 3))
+	asserts.NoError(err)
+	asserts.Equal("Simba", animal.Name)
+	asserts.Equal(1, len(animal.Toys))
+	asserts.Equal("Nala", animal.Toys[0].Name)
+}
+
+// TestScope_LoadedRelations tests:
+// - If the loaded relations are returned.
+// - If a blacklisted relation is not returned.
+func TestScope_LoadedRelations(t *testing.T) {
+	asserts := assert.New(t)
+
+	helperCreateDatabaseAndTable(asserts)
+	insertUserData(asserts)
+
+	// ok - all relations.
+	animal := Animal{}
+	err := animal.Init(&animal)
+	asserts.NoError(err)
+	err = animal.First(condition.New().SetWhere("id = ?", 1))
+	asserts.NoError(err)
+	scope, err := animal.Scope()
+	asserts.NoError(err)
+	asserts.Contains(scope.LoadedRelations(), "Toys")
+	asserts.Contains(scope.LoadedRelations(), "ToyPoly")
+
+	// ok - toys are blacklisted.
+	animal = Animal{}
+	err = animal.Init(&animal)
+	asserts.NoError(err)
+	animal.SetPermissions(orm.BLACKLIST, "Toys")
+	err = animal.First(condition.New().SetWhere("id = ?", 1))
+	asserts.NoError(err)
+	scope, err = animal.Scope()
+	asserts.NoError(err)
+	asserts.NotContains(scope.LoadedRelations(), "Toys")
+	asserts.Contains(scope.LoadedRelations(), "ToyPoly")
+}
+
+// TestModel_With tests:
+// - If only the requested relations are loaded.
+// - If the permissions are restored after the call.
+// - If an error returns on an unknown relation.
+func TestModel_With(t *testing.T) {
+	asserts := assert.New(t)
+
+	helperCreateDatabaseAndTable(asserts)
+	insertUserData(asserts)
+
+	// ok - only Toys and Species are loaded.
+	animal := Animal{}
+	err := animal.Init(&animal)
+	asserts.NoError(err)
+	err = animal.With("Toys", "Species").First(condition.New().SetWhere("id = ?", 1))
+	asserts.NoError(err)
+	scope, err := animal.Scope()
+	asserts.NoError(err)
+	asserts.Contains(scope.LoadedRelations(), "Toys")
+	asserts.NotContains(scope.LoadedRelations(), "ToyPoly")
+	asserts.NotContains(scope.LoadedRelations(), "Walkers")
+	asserts.Equal(0, len(animal.Walkers))
+
+	// ok - the next call loads all relations again.
+	err = animal.First(condition.New().SetWhere("id = ?", 1))
+	asserts.NoError(err)
+	asserts.Contains(scope.LoadedRelations(), "ToyPoly")
+
+	// ok - All
+	var animals []Animal
+	err = animal.With("Toys").All(&animals)
+	asserts.NoError(err)
+	asserts.True(len(animals) > 0)
+	for _, a := range animals {
+		asserts.Equal(0, len(a.Walkers))
+		asserts.Equal(0, len(a.ToyPoly))
+	}
+
+	// error - relation does not exist.
+	err = animal.With("Toys", "Unknown").First(condition.New().SetWhere("id = ?", 1))
+	asserts.Error(err)
+	asserts.Equal(fmt.Sprintf(orm.ErrFieldName, "orm_test.Animal:Unknown"), err.Error())
+}
+
+// TestScope_PreloadRelations tests:
+// - If the requested relations are loaded into the pre-built parents with one query per relation.
+// - If only the requested relations are loaded.
+// - If an error returns if the parents are not a slice of the model.
+func TestScope_PreloadRelations(t *testing.T) {
+	asserts := assert.New(t)
+
+	helperCreateDatabaseAndTable(asserts)
+	insertUserData(asserts)
+
+	animal := Animal{}
+	err := animal.Init(&animal)
+	asserts.NoError(err)
+	scope, err := animal.Scope()
+	asserts.NoError(err)
+
+	var stmts []string
+	builder.SetSQLRewriter(func(kind string, stmt string, args []interface{}) (string, []interface{}) {
+		stmts = append(stmts, stmt)
+		return stmt, args
+	})
+	defer builder.SetSQLRewriter(nil)
+
+	// ok - toys are loaded in one batched query.
+	animals := []Animal{{Base: Base{ID: 1}}, {Base: Base{ID: 2}}, {Base: Base{ID: 3}}}
+	err = scope.PreloadRelations(animals, "Toys")
+	asserts.NoError(err)
+	asserts.Equal(2, len(animals[0].Toys))
+	asserts.Equal(1, len(animals[1].Toys))
+	asserts.Equal(1, len(animals[2].Toys))
+	asserts.Equal(0, len(animals[0].Walkers))
+	asserts.Equal(0, len(animals[0].ToyPoly))
+	var toyQueries int
+	for _, stmt := range stmts {
+		if strings.Contains(stmt, "FROM `tests`.`toys`") {
+			toyQueries++
+		}
+	}
+	asserts.Equal(1, toyQueries)
+
+	// ok - ptr to a slice of ptrs.
+	animalsPtr := []*Animal{{Base: Base{ID: 1}}}
+	err = scope.PreloadRelations(&animalsPtr, "Toys")
+	asserts.NoError(err)
+	asserts.Equal(2, len(animalsPtr[0].Toys))
+
+	// error - parents are not a slice of the model.
+	err = scope.PreloadRelations([]Toy{}, "Toys")
+	asserts.Error(err)
+	asserts.Equal(fmt.Sprintf(orm.ErrPreload, "orm_test.Animal"), err.Error())
+	err = scope.PreloadRelations(nil)
+	asserts.Error(err)
+}
+
+// TestScope_CountRelation tests:
+// - If the relation entries of the parent are counted without loading them (hasMany, m2m, poly m2m).
+// - If the given condition is added.
+// - If an error returns if the relation does not exist.
+func TestScope_CountRelation(t *testing.T) {
+	asserts := assert.New(t)
+
+	helperCreateDatabaseAndTable(asserts)
+	insertUserData(asserts)
+
+	animal := Animal{}
+	err := animal.Init(&animal)
+	asserts.NoError(err)
+	scope, err := animal.Scope()
+	asserts.NoError(err)
+	animal.ID = 1
+
+	// ok - m2m
+	count, err := scope.CountRelation("Walkers", nil)
+	asserts.NoError(err)
+	asserts.Equal(2, count)
+	asserts.Equal(0, len(animal.Walkers))
+
+	// ok - m2m with condition
+	count, err = scope.CountRelation("Walkers", condition.New().SetWhere("`humans`.`id` = ?", 1))
+	asserts.NoError(err)
+	asserts.Equal(1, count)
+
+	// ok - m2m polymorphic
+	animal.ID = 2
+	count, err = scope.CountRelation("WalkersPoly", nil)
+	asserts.NoError(err)
+	asserts.Equal(1, count)
+
+	// ok - hasMany
+	animal.ID = 1
+	count, err = scope.CountRelation("Toys", nil)
+	asserts.NoError(err)
+	asserts.Equal(2, count)
+	asserts.Equal(0, len(animal.Toys))
+
+	// ok - no entries
+	animal.ID = 3
+	count, err = scope.CountRelation("Walkers", nil)
+	asserts.NoError(err)
+	asserts.Equal(0, count)
+
+	// error - relation does not exist
+	_, err = scope.CountRelation("Unknown", nil)
+	asserts.Error(err)
+	asserts.Equal(fmt.Sprintf(orm.ErrFieldName, "Unknown"), err.Error())
+}
+
+// TestEager_First_IndexHint tests:
+// - if the index hint of the root and relation config is added and the rows are scanned.
+// - error: the hinted index does not exist.
+func TestEager_First_IndexHint(t *testing.T) {
+	asserts := assert.New(t)
+
+	helperCreateDatabaseAndTable(asserts)
+	insertUserData(asserts)
+
+	// ok
+	animal := Animal{}
+	err := animal.Init(&animal)
+	asserts.NoError(err)
+	scope, err := animal.Scope()
+	asserts.NoError(err)
+	scope.SetConfig(orm.NewConfig().SetAllowHasOneZero(true).SetIndexHint("PRIMARY", query.HintForce))
+	scope.SetConfig(orm.NewConfig().SetIndexHint("PRIMARY", query.HintForce), "Toys")
+	err = animal.First(condition.New().SetWhere("id = ?", 1))
+	asserts.NoError(err)
+	asserts.Equal(1, animal.ID)
+	asserts.True(len(animal.Toys) > 0)
+
+	// error: index does not exist.
+	animal = Animal{}
+	err = animal.Init(&animal)
+	asserts.NoError(err)
+	scope, err = animal.Scope()
+	asserts.NoError(err)
+	scope.SetConfig(orm.NewConfig().SetAllowHasOneZero(true).SetIndexHint("idx_missing", query.HintForce))
+	err = animal.First(condition.New().SetWhere("id = ?", 1))
+	asserts.Error(err)
+	asserts.Contains(err.Error(), "idx_missing")
+}
+
+// TestEager_SelectExpression tests:
+// - If the aliased select expression is scanned into the custom field on First and All.
+// - If the custom field is excluded from the insert and update.
+func TestEager_SelectExpression(t *testing.T) {
+	asserts := assert.New(t)
+
+	helperCreateDatabaseAndTable(asserts)
+	insertUserData(asserts)
+
+	var count int
+	err := builder.Query().DB().QueryRow("SELECT COUNT(*) FROM `tests`.`toys` WHERE animal_id = 1").Scan(&count)
+	asserts.NoError(err)
+	asserts.True(count > 0)
+
+	expr := "(SELECT COUNT(*) FROM `tests`.`toys` WHERE `toys`.`animal_id` = `animals`.`id`)"
+
+	// ok: first
+	animal := AnimalToyCount{}
+	err = animal.Init(&animal)
+	asserts.NoError(err)
+	scope, err := animal.Scope()
+	asserts.NoError(err)
+	scope.SetConfig(orm.NewConfig().SetSelectExpression(expr, "toy_count"))
+	err = animal.First(condition.New().SetWhere("id = ?", 1))
+	asserts.NoError(err)
+	asserts.Equal(1, animal.ID)
+	asserts.Equal(count, animal.ToyCount)
+
+	// ok: all
+	var animals []AnimalToyCount
+	err = animal.All(&animals, condition.New().SetWhere("id = ?", 1))
+	asserts.NoError(err)
+	asserts.Equal(1, len(animals))
+	asserts.Equal(count, animals[0].ToyCount)
+
+	// ok: field is not scanned without the expression.
+	animal = AnimalToyCount{}
+	err = animal.Init(&animal)
+	asserts.NoError(err)
+	err = animal.First(condition.New().SetWhere("id = ?", 1))
+	asserts.NoError(err)
+	asserts.Equal(0, animal.ToyCount)
+
+	// ok: custom field is excluded from insert and update.
+	animal = AnimalToyCount{}
+	err = animal.Init(&animal)
+	asserts.NoError(err)
+	animal.Name = "Scan"
+	animal.ToyCount = 5
+	err = animal.Create()
+	asserts.NoError(err)
+	animal.Name = "ScanUpdated"
+	err = animal.Update()
+	asserts.NoError(err)
+}
+
+// TestModel_SkipMissingRelations tests:
+// - error: if a relation table does not exist.
+// - If the relation is skipped and marked unavailable if the config is set.
+// - If the other relations are loaded.
+// - error: if the unavailable relation is requested.
+func TestModel_SkipMissingRelations(t *testing.T) {
+	asserts := assert.New(t)
+
+	helperCreateDatabaseAndTable(asserts)
+	insertUserData(asserts)
+
+	// error: relation table does not exist.
+	animal := AnimalMissing{}
+	err := animal.Init(&animal)
+	asserts.Error(err)
+
+	prev := orm.DefaultConfig()
+	orm.SetDefaultConfig(orm.DefaultConfig().SetSkipMissingRelations(true))
+	defer orm.SetDefaultConfig(prev)
+
+	// ok: relation is skipped.
+	animal = AnimalMissing{}
+	err = animal.Init(&animal)
+	asserts.NoError(err)
+	scope, err := animal.Scope()
+	asserts.NoError(err)
+	asserts.True(scope.HasRelation("Missing"))
+	for _, relation := range scope.Relations(orm.Permission{}) {
+		asserts.Equal(relation.Field == "Missing", relation.Unavailable != nil)
+	}
+
+	// ok: other relations are loaded.
+	err = animal.First(condition.New().SetWhere("id = ?", 1))
+	asserts.NoError(err)
+	asserts.Equal(1, animal.ID)
+	asserts.True(len(animal.Toys) > 0)
+	asserts.Equal(0, len(animal.Missing))
+
+	// error: unavailable relation is requested.
+	animal = AnimalMissing{}
+	err = animal.Init(&animal)
+	asserts.NoError(err)
+	err = animal.With("Missing").First(condition.New().SetWhere("id = ?", 1))
+	asserts.Error(err)
+	asserts.Contains(err.Error(), "orm_test.AnimalMissing:Missing is not available")
+}
+
+// TestModel_WithoutRelations tests:
+// - If no relation is loaded on First and All.
+// - If only the root row is selected.
+func TestModel_WithoutRelations(t *testing.T) {
+	asserts := assert.New(t)
+
+	helperCreateDatabaseAndTable(asserts)
+	insertUserData(asserts)
+
+	// ok - First
+	animal := Animal{}
+	err := animal.Init(&animal)
+	asserts.NoError(err)
+	err = animal.WithoutRelations().First(condition.New().SetWhere("id = ?", 1))
+	asserts.NoError(err)
+	asserts.Equal(1, animal.ID)
+	asserts.Equal(int64(1), animal.Stats().RowsRead)
+	scope, err := animal.Scope()
+	asserts.NoError(err)
+	asserts.Equal(0, len(scope.LoadedRelations()))
+	asserts.Equal(0, len(animal.Toys))
+	asserts.Equal(0, len(animal.ToyPoly))
+	asserts.Equal(0, len(animal.Walkers))
+	asserts.Equal(0, animal.Species.ID)
+
+	// ok - the next call loads all relations again.
+	err = animal.First(condition.New().SetWhere("id = ?", 1))
+	asserts.NoError(err)
+	asserts.Contains(scope.LoadedRelations(), "Toys")
+
+	// ok - All
+	var animals []Animal
+	err = animal.WithoutRelations().All(&animals)
+	asserts.NoError(err)
+	asserts.True(len(animals) > 0)
+	asserts.Equal(int64(len(animals)), animal.Stats().RowsRead)
+	for _, a := range animals {
+		asserts.Equal(0, len(a.Toys))
+		asserts.Equal(0, len(a.Walkers))
+	}
+}
+
+// TestModel_FirstOrError tests:
+// - If the result is loaded.
+// - If ErrRecordNotFound returns if no result was found.
+func TestModel_FirstOrError(t *testing.T) {
+	asserts := assert.New(t)
+
+	helperCreateDatabaseAndTable(asserts)
+	insertUserData(asserts)
+
+	animal := Animal{}
+	err := animal.Init(&animal)
+	asserts.NoError(err)
+
+	// ok
+	err = animal.FirstOrError(condition.New().SetWhere("id = ?", 1))
+	asserts.NoError(err)
+	asserts.Equal(1, animal.ID)
+
+	// error - no result
+	err = animal.FirstOrError(condition.New().SetWhere("id = ?", 999))
+	asserts.Error(err)
+	asserts.True(errors.Is(err, orm.ErrRecordNotFound))
+	asserts.True(errors.Is(err, sql.ErrNoRows))
+}
+
+// TestEager_RelationLoader tests:
+// - If a custom relation loader is called instead of the sql select on First and All.
+// - If all parents are passed at once on All.
+// - If an error of the loader returns.
+func TestEager_RelationLoader(t *testing.T) {
+	asserts := assert.New(t)
+
+	helperCreateDatabaseAndTable(asserts)
+	insertUserData(asserts)
+
+	animal := Animal{}
+	err := animal.Init(&animal)
+	asserts.NoError(err)
+
+	calls := 0
+	loader := func(parents []orm.Interface) error {
+		calls++
+		for _, p := range parents {
+			a := p.(*Animal)
+			a.Toys = []Toy{{Name: fmt.Sprint("external-", a.ID)}}
+		}
+		return nil
+	}
+	scope, err := animal.Scope()
+	asserts.NoError(err)
+	scope.SetConfig(orm.NewConfig().SetAllowHasOneZero(true).SetRelationLoader("Toys", loader))
+
+	// ok: First
+	err = animal.First(condition.New().SetWhere("id = ?", 1))
+	asserts.NoError(err)
+	asserts.Equal(1, calls)
+	asserts.Equal([]Toy{{Name: "external-1"}}, animal.Toys)
+	// other relations are loaded by sql.
+	asserts.Equal(2, len(animal.ToysSlicePtr))
+
+	// ok: All
+	calls = 0
+	var animals []Animal
+	err = animal.All(&animals, condition.New().SetWhere("id IN (?)", []int{1, 2}))
+	asserts.NoError(err)
+	asserts.Equal(1, calls)
+	asserts.Equal(2, len(animals))
+	asserts.Equal([]Toy{{Name: "external-1"}}, animals[0].Toys)
+	asserts.Equal([]Toy{{Name: "external-2"}}, animals[1].Toys)
+
+	// error: loader returns an error
+	scope.SetConfig(orm.NewConfig().SetAllowHasOneZero(true).SetRelationLoader("Toys", func(parents []orm.Interface) error {
+		return errors.New("loader error")
+	}))
+	err = animal.First(condition.New().SetWhere("id = ?", 1))
+	asserts.Error(err)
+	asserts.Equal("loader error", err.Error())
+}
+
+// TestEager_RelationLimit tests:
+// - If max N hasMany entries are loaded per parent on First and All.
+// - If the order of the relation condition is used.
+func TestEager_RelationLimit(t *testing.T) {
+	asserts := assert.New(t)
+
+	helperCreateDatabaseAndTable(asserts)
+	insertUserData(asserts)
+
+	animal := Animal{}
+	err := animal.Init(&animal)
+	asserts.NoError(err)
+
+	scope, err := animal.Scope()
+	asserts.NoError(err)
+	scope.SetConfig(orm.NewConfig().SetAllowHasOneZero(true).SetRelationLimit("Toys", 1))
+	scope.SetConfig(orm.NewConfig().SetCondition(condition.New().SetOrder("-id")), "Toys")
+
+	// ok: First
+	err = animal.First(condition.New().SetWhere("id = ?", 1))
+	asserts.NoError(err)
+	asserts.Equal(1, len(animal.Toys))
+	asserts.Equal(2, animal.Toys[0].ID)
+	// ok: other relations are not limited
+	asserts.Equal(2, len(animal.ToysSlicePtr))
+
+	// ok: All
+	var animals []Animal
+	err = animal.All(&animals, condition.New().SetWhere("id IN (?)", []int{1, 2, 3}).SetOrder("id"))
+	asserts.NoError(err)
+	asserts.Equal(3, len(animals))
+	asserts.Equal(1, len(animals[0].Toys))
+	asserts.Equal(2, animals[0].Toys[0].ID)
+	asserts.Equal(1, len(animals[1].Toys))
+	asserts.Equal(3, animals[1].Toys[0].ID)
+	asserts.Equal(1, len(animals[2].Toys))
+	asserts.Equal(4, animals[2].Toys[0].ID)
+}
+
+// TestEager_RelationConcurrency tests:
+// - If the relations are loaded in parallel with the same result as sequential on First and All.
+// - If the relations are loaded inside a transaction.
+// - If the read rows are counted correctly.
+// - If the relations are running in parallel (loaders block until all loads have started).
+// - If the relations are running sequentially inside a transaction.
+func TestEager_RelationConcurrency(t *testing.T) {
+	asserts := assert.New(t)
+
+	helperCreateDatabaseAndTable(asserts)
+	insertUserData(asserts)
+
+	animal := Animal{}
+	err := animal.Init(&animal)
+	asserts.NoError(err)
+
+	// sequential reference.
+	err = animal.First(condition.New().SetWhere("id = ?", 1))
+	asserts.NoError(err)
+	rowsRead := animal.Stats().RowsRead
+
+	scope, err := animal.Scope()
+	asserts.NoError(err)
+	scope.SetConfig(orm.NewConfig().SetAllowHasOneZero(true).SetRelationConcurrency(4))
+
+	// ok: First
+	for _, test := range helperTestCases() {
+		err = animal.First(condition.New().SetWhere("id = ?", test.fetchID))
+		helperTestResults(asserts, err, test, animal, false)
+	}
+	err = animal.First(condition.New().SetWhere("id = ?", 1))
+	asserts.NoError(err)
+	asserts.Equal(rowsRead, animal.Stats().RowsRead)
+
+	// ok: All
+	var animals []Animal
+	err = animal.All(&animals, condition.New().SetWhere("id IN (?)", []int{1, 2}))
+	asserts.NoError(err)
+	asserts.Equal(2, len(animals))
+	for i, a := range animals {
+		helperTestResults(asserts, err, helperTestCases()[i], a, true)
+	}
+
+	// ok: inside a transaction.
+	tx, err := builder.Query().Tx()
+	asserts.NoError(err)
+	err = animal.WithTx(tx).First(condition.New().SetWhere("id = ?", 1))
+	helperTestResults(asserts, err, helperTestCases()[0], animal, false)
+	asserts.NoError(tx.Commit())
+
+	// ok: loads are running in parallel (new model, because the tx is still set).
+	loader, maxRunning := helperBlockingLoader(3)
+	animal = Animal{}
+	err = animal.Init(&animal)
+	asserts.NoError(err)
+	scope, err = animal.Scope()
+	asserts.NoError(err)
+	scope.SetConfig(orm.NewConfig().SetAllowHasOneZero(true).SetRelationConcurrency(4).
+		SetRelationLoader("Toys", loader).SetRelationLoader("ToysSlicePtr", loader).SetRelationLoader("ToysPtrSlice", loader))
+	err = animal.First(condition.New().SetWhere("id = ?", 1))
+	asserts.NoError(err)
+	asserts.Equal(int32(3), atomic.LoadInt32(maxRunning))
+
+	// ok: loads are running sequentially inside a transaction.
+	loader, maxRunning = helperBlockingLoader(3)
+	scope.SetConfig(orm.NewConfig().SetAllowHasOneZero(true).SetRelationConcurrency(4).
+		SetRelationLoader("Toys", loader).SetRelationLoader("ToysSlicePtr", loader).SetRelationLoader("ToysPtrSlice", loader))
+	tx, err = builder.Query().Tx()
+	asserts.NoError(err)
+	err = animal.WithTx(tx).First(condition.New().SetWhere("id = ?", 1))
+	asserts.NoError(err)
+	asserts.NoError(tx.Commit())
+	asserts.Equal(int32(1), atomic.LoadInt32(maxRunning))
+}
+
+// helperBlockingLoader returns a relation loader which blocks until n loads have started or a timeout is reached.
+// The max number of loads which were running at the same time is recorded.
+func helperBlockingLoader(n int32) (orm.RelationLoader, *int32) {
+	var running, started, maxRunning int32
+	ready := make(chan struct{})
+	return func(parents []orm.Interface) error {
+		cur := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if cur <= m || atomic.CompareAndSwapInt32(&maxRunning, m, cur) {
+				break
+			}
+		}
+		if atomic.AddInt32(&started, 1) == n {
+			close(ready)
+		}
+		select {
+		case <-ready:
+		case <-time.After(500 * time.Millisecond):
+		}
+		return nil
+	}, &maxRunning
+}
+
+// TestModel_ForUpdate tests:
+// - If the lock is ignored outside of a transaction.
+// - If an error returns outside of a transaction if configured.
+// - If the selected row is locked until the transaction is committed.
+func TestModel_ForUpdate(t *testing.T) {
+	asserts := assert.New(t)
+
+	helperCreateDatabaseAndTable(asserts)
+	insertUserData(asserts)
+
+	animal := Animal{}
+	err := animal.Init(&animal)
+	asserts.NoError(err)
+
+	// ok: no transaction
+	err = animal.ForUpdate().First(condition.New().SetWhere("id = ?", 1))
+	asserts.NoError(err)
+
+	// error: no transaction
+	scope, err := animal.Scope()
+	asserts.NoError(err)
+	scope.SetConfig(orm.NewConfig().SetAllowHasOneZero(true).SetLockRequireTx(true))
+	err = animal.ForShare().First(condition.New().SetWhere("id = ?", 1))
+	asserts.Error(err)
+	asserts.Equal(fmt.Sprintf(orm.ErrLockTx, "orm_test.Animal"), err.Error())
+
+	// ok: lock is only used once.
+	err = animal.First(condition.New().SetWhere("id = ?", 1))
+	asserts.NoError(err)
+
+	// ok: row is locked until commit.
+	tx, err := builder.Query().Tx()
+	asserts.NoError(err)
+	err = animal.WithTx(tx).ForUpdate().First(condition.New().SetWhere("id = ?", 1))
+	asserts.NoError(err)
+
+	done := make(chan error)
+	go func() {
+		_, err := builder.Query().Update("tests.animals").Set(map[string]interface{}{"name": "Locked"}).Where("id = ?", 1).Exec()
+		done <- err
+	}()
+
+	select {
+	case <-done:
+		asserts.Fail("update was not blocked")
+	case <-time.After(200 * time.Millisecond):
+	}
+	asserts.NoError(tx.Commit())
+	asserts.NoError(<-done)
+	animal.WithTx(nil)
 }
 
 // TestEager_All_DBLoopDetection tests:
@@ -87,7 +965,9 @@ func TestEager_All_DBLoopDetection(t *testing.T) {
 	// error: id 4 infinity loop.
 	err = role.All(&roles)
 	asserts.Error(err)
-	asserts.Equal(fmt.Sprintf(orm.ErrInfinityLoop, "orm_test.Role"), err.Error())
+	var relErr *orm.RelationError
+	asserts.True(errors.As(err, &relErr))
+	asserts.Contains(err.Error(), fmt.Sprintf(orm.ErrInfinityLoop, "orm_test.Role"))
 }
 
 // TestEager_First tests:
@@ -142,6 +1022,12 @@ func TestEager_First(t *testing.T) {
 	err = animal.First(condition.New().SetWhere("id = ?", 3))
 	asserts.Error(err)
 	asserts.Equal(fmt.Errorf(orm.ErrNoRows, s.FqdnModel("Address"), sql.ErrNoRows).Error(), err.Error())
+	var relErr *orm.RelationError
+	asserts.True(errors.As(err, &relErr))
+	asserts.Equal("orm_test.Animal", relErr.Model)
+	asserts.Equal("Address", relErr.Field)
+	asserts.Equal(sql.ErrNoRows, relErr.Err)
+	asserts.True(errors.Is(err, sql.ErrNoRows))
 
 	// check if soft deleted rows will be displayed.
 	s.SetConfig(orm.NewConfig().SetAllowHasOneZero(true), "Address")
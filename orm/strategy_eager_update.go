@@ -5,11 +5,17 @@
 package orm
 
 import (
+	"fmt"
 	"reflect"
 
 	"github.com/patrickascher/gofer/query/condition"
 )
 
+// Error messages.
+var (
+	ErrBelongsToNull = "orm: belongsTo relation %s was cleared but the foreign key column %s is not null able"
+)
+
 // Update entry by the given condition.
 // Only fields with the wrote permission will be written.
 // There is an option to only update the reference field without creating or updating the linked entry. (BelongsTo, ManyToMany)
@@ -18,7 +24,7 @@ import (
 // BelongsTo:
 // - CREATE: create or update (pk exist) the orm model.
 // - UPDATE: Update the parent orm model.
-// - DELETE: Only the reference is deleted at the moment.
+// - DELETE: Only the reference is deleted at the moment. The foreign key is set to NULL, an error returns if the column is not null able.
 //
 // Field(s): gets updated if the value changed.
 //
@@ -48,59 +54,65 @@ func (e *eager) Update(scope Scope, c condition.Condition) error {
 	b := scope.Builder()
 
 	// handling belongsTo relations first
+	// nullFields holds the foreign keys of cleared belongsTo relations.
+	nullFields := map[string]bool{}
 	for _, relation := range scope.SQLRelations(perm) {
 		if relation.Kind == BelongsTo {
 			if changes := scope.ChangedValueByFieldName(relation.Field); changes != nil {
 				if changes.Field == relation.Field {
 					rel, err := scope.InitRelationByField(relation.Field, true)
 					if err != nil {
-						return err
+						return relationError(scope, relation, err)
 					}
 					switch changes.Operation {
 					case CREATE:
 						// create or update the entry
 						err = createOrUpdate(rel, relation, false)
 						if err != nil {
-							return err
+							return relationError(scope, relation, err)
 						}
 						// TODO delete old reference? id: scope.FieldValue(relation.Mapping.ForeignKey.Name)
 						// TODO logic of the belongsTo,m2m must be clear before implementing this solution.
 						err = SetReflectValue(scope.FieldValue(relation.Mapping.ForeignKey.Name), rel.model().scope.FieldValue(relation.Mapping.References.Name))
 						if err != nil {
-							return err
+							return relationError(scope, relation, err)
 						}
 						if relation.IsPolymorphic() {
-							err = SetReflectValue(rel.model().scope.FieldValue(relation.Mapping.Polymorphic.TypeField.Name), reflect.ValueOf(relation.Mapping.Polymorphic.Value))
+							err = SetReflectValue(rel.model().scope.FieldValue(relation.Mapping.Polymorphic.TypeField.Name), reflect.ValueOf(polymorphicValue(scope.Caller(), relation)))
 							if err != nil {
-								return err
+								return relationError(scope, relation, err)
 							}
 						}
 						scope.AppendChangedValue(ChangedValue{Field: relation.Mapping.ForeignKey.Name})
 					case UPDATE:
 						err = SetReflectValue(scope.FieldValue(relation.Mapping.ForeignKey.Name), rel.model().scope.FieldValue(relation.Mapping.References.Name))
 						if err != nil {
-							return err
+							return relationError(scope, relation, err)
 						}
 						// skip if reference only
 						if root, err := rel.model().scope.Parent(RootStruct); err == nil && root.config[RootStruct].updateReferencesOnly {
 							continue
 						}
 						if relation.IsPolymorphic() {
-							err = SetReflectValue(rel.model().scope.FieldValue(relation.Mapping.Polymorphic.TypeField.Name), reflect.ValueOf(relation.Mapping.Polymorphic.Value))
+							err = SetReflectValue(rel.model().scope.FieldValue(relation.Mapping.Polymorphic.TypeField.Name), reflect.ValueOf(polymorphicValue(scope.Caller(), relation)))
 							if err != nil {
-								return err
+								return relationError(scope, relation, err)
 							}
 						}
 						rel.model().scope.SetChangedValues(changes.Children)
 						err = rel.Update()
 						if err != nil {
-							return err
+							return relationError(scope, relation, err)
 						}
 					case DELETE:
+						if !relation.Mapping.ForeignKey.Information.NullAble {
+							return relationError(scope, relation, fmt.Errorf(ErrBelongsToNull, scope.FqdnModel(relation.Field), relation.Mapping.ForeignKey.Information.Name))
+						}
 						err = SetReflectValue(scope.FieldValue(relation.Mapping.ForeignKey.Name), reflect.Zero(scope.FieldValue(relation.Mapping.ForeignKey.Name).Type()))
 						if err != nil {
-							return err
+							return relationError(scope, relation, err)
 						}
+						nullFields[relation.Mapping.ForeignKey.Name] = true
 						scope.AppendChangedValue(ChangedValue{Field: relation.Mapping.ForeignKey.Name})
 						// No real delete of belongsTo because there could be references? needed to really delete?
 						// TODO config if belongsTo should be deleted if no more refs?
@@ -117,16 +129,21 @@ func (e *eager) Update(scope Scope, c condition.Condition) error {
 	for _, field := range scope.SQLFields(perm) {
 		if scope.ChangedValueByFieldName(field.Name) != nil {
 			column = append(column, field.Information.Name)
-			value[field.Information.Name] = scope.FieldValue(field.Name).Interface()
+			if nullFields[field.Name] {
+				value[field.Information.Name] = nil
+				continue
+			}
+			value[field.Information.Name] = sqlValue(scope, field)
 		}
 	}
 
 	// only update if columns are writeable
 	if len(value) > 0 {
-		_, err := b.Query(scope.Model().tx).Update(scope.FqdnTable()).Condition(c).Columns(column...).Set(value).Exec()
+		res, err := b.Query(scope.Model().tx).Update(scope.FqdnTable()).Condition(c).Columns(column...).Set(value).Exec()
 		if err != nil {
 			return err
 		}
+		scope.Model().addRowsAffected(res)
 	}
 
 	for _, relation := range scope.SQLRelations(perm) {
@@ -137,7 +154,7 @@ func (e *eager) Update(scope Scope, c condition.Condition) error {
 
 					relationModel, err := scope.InitRelationByField(relation.Field, true)
 					if err != nil {
-						return err
+						return relationError(scope, relation, err)
 					}
 					relationScope := relationModel.model().scope
 
@@ -147,24 +164,25 @@ func (e *eager) Update(scope Scope, c condition.Condition) error {
 						// set parent ID + poly to relation model
 						err = setValue(scope, relation, reflect.Indirect(reflect.ValueOf(relationScope.Caller())))
 						if err != nil {
-							return err
+							return relationError(scope, relation, err)
 						}
 
 						// delete old db references. This could happen if a user adds a new model and an old exists already.
 						deleteModel := relationScope.Builder().Query(relationScope.Model().tx).Delete(relationScope.FqdnTable())
 						c := e.createWhere(&relationScope, relation, relationScope.Config(), scope.FieldValue(relation.Mapping.ForeignKey.Name).Interface())
 						// TODO this should be a model.Delete instead of builder - callback wise.
-						_, err = deleteModel.Condition(c).Exec()
+						res, err := deleteModel.Condition(c).Exec()
 						if err != nil {
-							return err
+							return relationError(scope, relation, err)
 						}
+						scope.Model().addRowsAffected(res)
 
 						err = relationModel.Create()
 					case UPDATE:
 						// set parent ID + poly to relation model
 						err = setValue(scope, relation, reflect.Indirect(reflect.ValueOf(relationScope.Caller())))
 						if err != nil {
-							return err
+							return relationError(scope, relation, err)
 						}
 
 						relationScope.SetChangedValues(cV.Children)
@@ -172,14 +190,15 @@ func (e *eager) Update(scope Scope, c condition.Condition) error {
 					case DELETE:
 						deleteModel := relationScope.Builder().Query(relationScope.model.tx).Delete(relationScope.FqdnTable())
 						c := e.createWhere(&relationScope, relation, relationScope.Config(), scope.FieldValue(relation.Mapping.ForeignKey.Name).Interface())
-						_, err = deleteModel.Condition(c).Exec()
+						res, err := deleteModel.Condition(c).Exec()
 						if err != nil {
-							return err
+							return relationError(scope, relation, err)
 						}
+						scope.Model().addRowsAffected(res)
 
 					}
 					if err != nil {
-						return err
+						return relationError(scope, relation, err)
 					}
 				}
 			}
@@ -188,7 +207,7 @@ func (e *eager) Update(scope Scope, c condition.Condition) error {
 			if change := scope.ChangedValueByFieldName(relation.Field); change != nil {
 				rel, err := scope.InitRelationByField(relation.Field, true)
 				if err != nil {
-					return err
+					return relationError(scope, relation, err)
 				}
 				relScope := rel.model().scope
 
@@ -199,17 +218,17 @@ func (e *eager) Update(scope Scope, c condition.Condition) error {
 						// set parent ID + poly to relation model
 						err = setValue(scope, relation, reflect.Indirect(reflect.Indirect(scope.FieldValue(relation.Field)).Index(i)))
 						if err != nil {
-							return err
+							return relationError(scope, relation, err)
 						}
 
 						err = scope.InitRelation(reflect.Indirect(reflect.Indirect(scope.FieldValue(relation.Field)).Index(i)).Addr().Interface().(Interface), relation.Field)
 						if err != nil {
-							return err
+							return relationError(scope, relation, err)
 						}
 
 						err = reflect.Indirect(reflect.Indirect(scope.FieldValue(relation.Field)).Index(i)).Addr().Interface().(Interface).Create()
 						if err != nil {
-							return err
+							return relationError(scope, relation, err)
 						}
 					}
 				case UPDATE:
@@ -220,36 +239,36 @@ func (e *eager) Update(scope Scope, c condition.Condition) error {
 							// set parent ID + poly to relation model
 							err = setValue(scope, relation, reflect.Indirect(reflect.Indirect(scope.FieldValue(relation.Field)).Index(subChange.Index.(int))))
 							if err != nil {
-								return err
+								return relationError(scope, relation, err)
 							}
 
 							err = scope.InitRelation(reflect.Indirect(reflect.Indirect(scope.FieldValue(relation.Field)).Index(subChange.Index.(int))).Addr().Interface().(Interface), relation.Field)
 							if err != nil {
-								return err
+								return relationError(scope, relation, err)
 							}
 
 							// TODO what if the id already exists - possible?.
 							err = reflect.Indirect(reflect.Indirect(scope.FieldValue(relation.Field)).Index(subChange.Index.(int))).Addr().Interface().(Interface).Create()
 							if err != nil {
-								return err
+								return relationError(scope, relation, err)
 							}
 						case UPDATE:
 							tmpUpdate := reflect.Indirect(reflect.Indirect(scope.FieldValue(relation.Field)).Index(subChange.Index.(int))).Addr().Interface().(Interface)
 							err = scope.InitRelation(tmpUpdate, relation.Field)
 							if err != nil {
-								return err
+								return relationError(scope, relation, err)
 							}
 
 							// set parent ID + poly to relation model
 							err = setValue(scope, relation, reflect.Indirect(reflect.Indirect(scope.FieldValue(relation.Field)).Index(subChange.Index.(int))))
 							if err != nil {
-								return err
+								return relationError(scope, relation, err)
 							}
 
 							tmpUpdate.model().scope.SetChangedValues(subChange.Children)
 							err = tmpUpdate.Update()
 							if err != nil {
-								return err
+								return relationError(scope, relation, err)
 							}
 						case DELETE:
 							deleteID = append(deleteID, subChange.Index)
@@ -259,24 +278,26 @@ func (e *eager) Update(scope Scope, c condition.Condition) error {
 						deleteModel := relScope.Builder().Query(relScope.model.tx).Delete(relScope.FqdnTable())
 						pKeys, err := relScope.PrimaryKeys()
 						if err != nil {
-							return err
+							return relationError(scope, relation, err)
 						}
 						deleteModel.Where(b.QuoteIdentifier(pKeys[0].Information.Name)+" IN (?)", deleteID)
 						if relation.IsPolymorphic() {
-							deleteModel.Where(relation.Mapping.Polymorphic.TypeField.Information.Name+" = ?", relation.Mapping.Polymorphic.Value)
+							deleteModel.Where(relation.Mapping.Polymorphic.TypeField.Information.Name+" = ?", polymorphicValue(scope.Caller(), relation))
 						}
-						_, err = deleteModel.Exec()
+						res, err := deleteModel.Exec()
 						if err != nil {
-							return err
+							return relationError(scope, relation, err)
 						}
+						scope.Model().addRowsAffected(res)
 					}
 				case DELETE:
 					deleteModel := relScope.Builder().Query(relScope.model.tx).Delete(relScope.FqdnTable())
 					c := e.createWhere(&relScope, relation, relScope.Config(), scope.FieldValue(relation.Mapping.ForeignKey.Name).Interface())
-					_, err = deleteModel.Condition(c).Exec()
+					res, err := deleteModel.Condition(c).Exec()
 					if err != nil {
-						return err
+						return relationError(scope, relation, err)
 					}
+					scope.Model().addRowsAffected(res)
 				}
 			}
 
@@ -290,16 +311,16 @@ func (e *eager) Update(scope Scope, c condition.Condition) error {
 						tmpCreate := reflect.Indirect(reflect.Indirect(scope.FieldValue(relation.Field)).Index(i)).Addr().Interface().(Interface)
 						err := scope.InitRelation(tmpCreate, relation.Field)
 						if err != nil {
-							return err
+							return relationError(scope, relation, err)
 						}
 
 						// create or update the entry
 						err = createOrUpdate(tmpCreate, relation, scope.Model().isSelfReferencing(relation.Type))
 						if err != nil {
-							return err
+							return relationError(scope, relation, err)
 						}
 						if relation.IsPolymorphic() {
-							joinTable = append(joinTable, map[string]interface{}{relation.Mapping.Polymorphic.TypeField.Information.Name: relation.Mapping.Polymorphic.Value, relation.Mapping.Join.ForeignColumnName: scope.FieldValue(relation.Mapping.ForeignKey.Name).Interface(), relation.Mapping.Join.ReferencesColumnName: reflect.Indirect(reflect.Indirect(scope.FieldValue(relation.Field)).Index(i)).FieldByName(relation.Mapping.References.Name).Interface()})
+							joinTable = append(joinTable, map[string]interface{}{relation.Mapping.Polymorphic.TypeField.Information.Name: polymorphicValue(scope.Caller(), relation), relation.Mapping.Join.ForeignColumnName: scope.FieldValue(relation.Mapping.ForeignKey.Name).Interface(), relation.Mapping.Join.ReferencesColumnName: reflect.Indirect(reflect.Indirect(scope.FieldValue(relation.Field)).Index(i)).FieldByName(relation.Mapping.References.Name).Interface()})
 						} else {
 							joinTable = append(joinTable, map[string]interface{}{relation.Mapping.Join.ForeignColumnName: scope.FieldValue(relation.Mapping.ForeignKey.Name).Interface(), relation.Mapping.Join.ReferencesColumnName: reflect.Indirect(reflect.Indirect(scope.FieldValue(relation.Field)).Index(i)).FieldByName(relation.Mapping.References.Name).Interface()})
 						}
@@ -307,10 +328,11 @@ func (e *eager) Update(scope Scope, c condition.Condition) error {
 					// batch insert
 					if len(joinTable) > 0 {
 						// must be the parent scope tx
-						_, err := b.Query(scope.Model().tx).Insert(relation.Mapping.Join.Table).Values(joinTable).Exec()
+						res, err := b.Query(scope.Model().tx).Insert(relation.Mapping.Join.Table).Values(joinTable).Exec()
 						if err != nil {
-							return err
+							return relationError(scope, relation, err)
 						}
+						scope.Model().addRowsAffected(res...)
 					}
 				case UPDATE:
 
@@ -324,17 +346,17 @@ func (e *eager) Update(scope Scope, c condition.Condition) error {
 							tmpCreate := reflect.Indirect(reflect.Indirect(scope.FieldValue(relation.Field)).Index(changes.Index.(int))).Addr().Interface().(Interface)
 							err := scope.InitRelation(tmpCreate, relation.Field)
 							if err != nil {
-								return err
+								return relationError(scope, relation, err)
 							}
 
 							// create or update the entry
 							err = createOrUpdate(tmpCreate, relation, false)
 							if err != nil {
-								return err
+								return relationError(scope, relation, err)
 							}
 
 							if relation.IsPolymorphic() {
-								createID = append(createID, map[string]interface{}{relation.Mapping.Polymorphic.TypeField.Information.Name: relation.Mapping.Polymorphic.Value, relation.Mapping.Join.ForeignColumnName: scope.FieldValue(relation.Mapping.ForeignKey.Name).Interface(), relation.Mapping.Join.ReferencesColumnName: reflect.Indirect(reflect.Indirect(scope.FieldValue(relation.Field)).Index(changes.Index.(int))).FieldByName(relation.Mapping.References.Name).Interface()})
+								createID = append(createID, map[string]interface{}{relation.Mapping.Polymorphic.TypeField.Information.Name: polymorphicValue(scope.Caller(), relation), relation.Mapping.Join.ForeignColumnName: scope.FieldValue(relation.Mapping.ForeignKey.Name).Interface(), relation.Mapping.Join.ReferencesColumnName: reflect.Indirect(reflect.Indirect(scope.FieldValue(relation.Field)).Index(changes.Index.(int))).FieldByName(relation.Mapping.References.Name).Interface()})
 							} else {
 								createID = append(createID, map[string]interface{}{relation.Mapping.Join.ForeignColumnName: scope.FieldValue(relation.Mapping.ForeignKey.Name).Interface(), relation.Mapping.Join.ReferencesColumnName: reflect.Indirect(reflect.Indirect(scope.FieldValue(relation.Field)).Index(changes.Index.(int))).FieldByName(relation.Mapping.References.Name).Interface()})
 							}
@@ -343,7 +365,7 @@ func (e *eager) Update(scope Scope, c condition.Condition) error {
 							tmpUpdate := reflect.Indirect(reflect.Indirect(scope.FieldValue(relation.Field)).Index(changes.Index.(int))).Addr().Interface().(Interface)
 							err := scope.InitRelation(tmpUpdate, relation.Field)
 							if err != nil {
-								return err
+								return relationError(scope, relation, err)
 							}
 
 							// skip if reference only
@@ -356,7 +378,7 @@ func (e *eager) Update(scope Scope, c condition.Condition) error {
 							tmpUpdate.model().scope.SetChangedValues(changes.Children)
 							err = tmpUpdate.Update()
 							if err != nil {
-								return err
+								return relationError(scope, relation, err)
 							}
 						case DELETE:
 							deleteID = append(deleteID, changes.Index)
@@ -368,30 +390,33 @@ func (e *eager) Update(scope Scope, c condition.Condition) error {
 							Where(b.QuoteIdentifier(relation.Mapping.Join.ForeignColumnName)+" = ?", scope.FieldValue(relation.Mapping.ForeignKey.Name).Interface()).
 							Where(b.QuoteIdentifier(relation.Mapping.Join.ReferencesColumnName)+" IN (?)", deleteID)
 						if relation.IsPolymorphic() {
-							stmt.Where(relation.Mapping.Polymorphic.TypeField.Information.Name+" = ?", relation.Mapping.Polymorphic.Value)
+							stmt.Where(relation.Mapping.Polymorphic.TypeField.Information.Name+" = ?", polymorphicValue(scope.Caller(), relation))
 						}
-						_, err := stmt.Exec()
+						res, err := stmt.Exec()
 						if err != nil {
-							return err
+							return relationError(scope, relation, err)
 						}
+						scope.Model().addRowsAffected(res)
 					}
 					if len(createID) > 0 {
 						// poly is added in values.
-						_, err := b.Query(scope.Model().tx).Insert(relation.Mapping.Join.Table).Values(createID).Exec()
+						res, err := b.Query(scope.Model().tx).Insert(relation.Mapping.Join.Table).Values(createID).Exec()
 						if err != nil {
-							return err
+							return relationError(scope, relation, err)
 						}
+						scope.Model().addRowsAffected(res...)
 					}
 
 				case DELETE:
 					stmt := b.Query(scope.Model().tx).Delete(relation.Mapping.Join.Table).Where(b.QuoteIdentifier(relation.Mapping.Join.ForeignColumnName)+" = ?", scope.FieldValue(relation.Mapping.ForeignKey.Name).Interface())
 					if relation.IsPolymorphic() {
-						stmt.Where(relation.Mapping.Polymorphic.TypeField.Information.Name+" = ?", relation.Mapping.Polymorphic.Value)
+						stmt.Where(relation.Mapping.Polymorphic.TypeField.Information.Name+" = ?", polymorphicValue(scope.Caller(), relation))
 					}
-					_, err := stmt.Exec()
+					res, err := stmt.Exec()
 					if err != nil {
-						return err
+						return relationError(scope, relation, err)
 					}
+					scope.Model().addRowsAffected(res)
 				}
 			}
 		}
@@ -5,6 +5,10 @@
 package orm_test
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"reflect"
 	"testing"
 	"time"
@@ -57,6 +61,111 @@ func TestEager_Update_SelfRef(t *testing.T) {
 	asserts.Equal(0, len(role.Roles[0].Roles)) // deleted role
 }
 
+// TestModel_Save tests:
+// - If the entry is created if no primary key is set.
+// - If the entry is updated if the primary key is set.
+// - If the entry is created if the primary key is set but does not exist in the db.
+// - If the primary key is locked in the transaction.
+// - If a transaction set by WithTx is used and not committed.
+func TestModel_Save(t *testing.T) {
+	asserts := assert.New(t)
+	helperCreateDatabaseAndTable(asserts)
+	insertUserData(asserts)
+
+	// init orm model
+	human := Human{}
+	err := human.Init(&human)
+	asserts.NoError(err)
+
+	// ok: create
+	human.Name = "John"
+	err = human.Save()
+	asserts.NoError(err)
+	asserts.Equal(4, human.ID)
+
+	// ok: update
+	human.Name = "Jane"
+	err = human.Save()
+	asserts.NoError(err)
+	err = human.First(condition.New().SetWhere("id = ?", 4))
+	asserts.NoError(err)
+	asserts.Equal("Jane", human.Name)
+
+	// ok: create with a supplied primary key.
+	human = Human{}
+	err = human.Init(&human)
+	asserts.NoError(err)
+	human.ID = 10
+	human.Name = "Doe"
+	err = human.Save()
+	asserts.NoError(err)
+	err = human.First(condition.New().SetWhere("id = ?", 10))
+	asserts.NoError(err)
+	asserts.Equal("Doe", human.Name)
+
+	// ok: the primary key is locked.
+	var stmts []string
+	builder.SetSQLRewriter(func(kind string, stmt string, args []interface{}) (string, []interface{}) {
+		stmts = append(stmts, stmt)
+		return stmt, args
+	})
+	human.Name = "Doe-Locked"
+	err = human.Save()
+	builder.SetSQLRewriter(nil)
+	asserts.NoError(err)
+	asserts.True(len(stmts) > 0)
+	asserts.Contains(stmts[0], "FOR UPDATE")
+
+	// ok: the given transaction is used and not committed.
+	tx, err := builder.Query().Tx()
+	asserts.NoError(err)
+	human = Human{}
+	err = human.Init(&human)
+	asserts.NoError(err)
+	human.ID = 11
+	human.Name = "Rollback"
+	err = human.WithTx(tx).Save()
+	asserts.NoError(err)
+	asserts.True(tx.HasTx())
+	asserts.NoError(tx.Rollback())
+	human = Human{}
+	err = human.Init(&human)
+	asserts.NoError(err)
+	err = human.First(condition.New().SetWhere("id = ?", 11))
+	asserts.True(errors.Is(err, sql.ErrNoRows))
+}
+
+// TestModel_WithContext tests:
+// - If the context is accessible in the hook and the value is persisted.
+func TestModel_WithContext(t *testing.T) {
+	asserts := assert.New(t)
+	helperCreateDatabaseAndTable(asserts)
+	insertUserData(asserts)
+
+	human := HumanAudit{}
+	err := human.Init(&human)
+	asserts.NoError(err)
+	err = human.First(condition.New().SetWhere("id = ?", 1))
+	asserts.NoError(err)
+	asserts.Equal("Pat", human.Name)
+
+	// ok: without context, nothing is stamped.
+	scope, err := human.Scope()
+	asserts.NoError(err)
+	asserts.Equal(context.Background(), scope.Context())
+
+	// ok: value of the context is persisted by the hook.
+	human.WithContext(context.WithValue(context.Background(), ctxKey("user"), "Admin"))
+	err = human.Update()
+	asserts.NoError(err)
+	human = HumanAudit{}
+	err = human.Init(&human)
+	asserts.NoError(err)
+	err = human.First(condition.New().SetWhere("id = ?", 1))
+	asserts.NoError(err)
+	asserts.Equal("Admin", human.Name)
+}
+
 // TestEager_Update tests:
 // - If all values are getting updated correctly. (ensure id stays the same on relations)
 // - If UpdatedAt gets set - if exists.
@@ -122,6 +231,93 @@ func TestEager_Update(t *testing.T) {
 	}
 }
 
+// TestModel_Touch tests:
+// - error if the model has no UpdatedAt field.
+// - If only the updated_at column of the matching rows is set.
+// - error if neither a condition nor the primary keys are set.
+// - If the row of the primary keys is touched without a condition.
+// - If soft deleted rows are not touched.
+func TestModel_Touch(t *testing.T) {
+	asserts := assert.New(t)
+	helperCreateDatabaseAndTable(asserts)
+	insertUserData(asserts)
+
+	// error: no updated_at column.
+	toy := Toy{}
+	err := toy.Init(&toy)
+	asserts.NoError(err)
+	n, err := toy.Touch(condition.New().SetWhere("animal_id = ?", 1))
+	asserts.Error(err)
+	asserts.Equal(fmt.Sprintf(orm.ErrTouch, "orm_test.Toy"), err.Error())
+	asserts.Equal(0, n)
+
+	// add updated_at column and delete the cached model.
+	_, err = builder.Query().DB().Exec("ALTER TABLE `toys` ADD `updated_at` DATETIME NULL, ADD `deleted_at` DATETIME NULL;")
+	asserts.NoError(err)
+	err = c.Delete("orm_", "orm_test.Toy")
+	asserts.NoError(err)
+
+	// ok
+	toy = Toy{}
+	err = toy.Init(&toy)
+	asserts.NoError(err)
+	n, err = toy.Touch(condition.New().SetWhere("animal_id = ?", 1))
+	asserts.NoError(err)
+	asserts.Equal(2, n)
+	asserts.Equal(int64(2), toy.Stats().RowsAffected)
+
+	count, err := toy.Count(condition.New().SetWhere("updated_at IS NOT NULL"))
+	asserts.NoError(err)
+	asserts.Equal(2, count)
+	count, err = toy.Count(condition.New().SetWhere("animal_id = ? AND updated_at IS NOT NULL", 1))
+	asserts.NoError(err)
+	asserts.Equal(2, count)
+
+	// ok: other columns are not changed.
+	var toys []Toy
+	err = toy.All(&toys, condition.New().SetWhere("animal_id = ?", 1))
+	asserts.NoError(err)
+	asserts.Equal(2, len(toys))
+	asserts.Equal("Bone", toys[0].Name)
+	asserts.Equal("Kong", toys[1].Name)
+
+	// error: no condition and no primary keys.
+	_, err = builder.Query().DB().Exec("UPDATE `toys` SET `updated_at` = NULL")
+	asserts.NoError(err)
+	toy = Toy{}
+	err = toy.Init(&toy)
+	asserts.NoError(err)
+	n, err = toy.Touch(nil)
+	asserts.Error(err)
+	asserts.Equal(fmt.Sprintf(orm.ErrTouchAll, "orm_test.Toy"), err.Error())
+	asserts.Equal(0, n)
+	n, err = toy.Touch(condition.New())
+	asserts.Error(err)
+	asserts.Equal(0, n)
+
+	// ok: primary key is used.
+	toy.ID = toys[0].ID
+	n, err = toy.Touch(nil)
+	asserts.NoError(err)
+	asserts.Equal(1, n)
+	count, err = toy.Count(condition.New().SetWhere("updated_at IS NOT NULL"))
+	asserts.NoError(err)
+	asserts.Equal(1, count)
+
+	// ok: soft deleted rows are not touched.
+	_, err = builder.Query().DB().Exec("UPDATE `toys` SET `updated_at` = NULL")
+	asserts.NoError(err)
+	_, err = builder.Query().Update("tests.toys").Set(map[string]interface{}{"deleted_at": query.NewNullTime(time.Now(), true)}).Where("id = ?", toys[1].ID).Exec()
+	asserts.NoError(err)
+	n, err = toy.Touch(condition.New().SetWhere("animal_id = ?", 1))
+	asserts.NoError(err)
+	asserts.Equal(1, n)
+
+	// tear down updated_at test.
+	err = c.Delete("orm_", "orm_test.Toy")
+	asserts.NoError(err)
+}
+
 // TestEager_Update_HasOneDel tests:
 // - If belongsTo gets added, updated and deleted correctly.
 // - If belongsTo updates on the Reference if configured so.
@@ -160,6 +356,7 @@ func TestEager_Update_HasOne_BelongsTo(t *testing.T) {
 	err = animal.First(condition.New().SetWhere("id = ?", 1))
 	asserts.NoError(err)
 	asserts.Equal(int64(0), animal.SpeciesID.Int64)
+	asserts.False(animal.SpeciesID.Valid)
 	asserts.Equal(0, animal.Species.ID)
 	asserts.Equal((*Species)(nil), animal.SpeciesPtr)
 	asserts.Equal(0, animal.SpeciesPoly.ID)
@@ -267,6 +464,166 @@ func TestEager_Update_HasOne_BelongsTo(t *testing.T) {
 	asserts.Equal("updated-NewSpeciesPoly", animal.SpeciesPolyPtr.Name)
 }
 
+// TestEager_Update_BelongsToNull tests:
+// - If the foreign key is set to NULL if a nullable belongsTo relation gets cleared.
+// - If an error returns if the foreign key column is not null able, wrapped as RelationError.
+func TestEager_Update_BelongsToNull(t *testing.T) {
+	asserts := assert.New(t)
+	helperCreateDatabaseAndTable(asserts)
+	insertUserData(asserts)
+
+	// ok - species_id is nullable.
+	animal := Animal{}
+	err := animal.Init(&animal)
+	asserts.NoError(err)
+	err = animal.First(condition.New().SetWhere("id = ?", 1))
+	asserts.NoError(err)
+	animal.Species = Species{}
+	animal.SpeciesPtr = nil
+	err = animal.Update()
+	asserts.NoError(err)
+	row, err := builder.Query().Select("tests.animals").Columns("species_id").Where("id = ?", 1).First()
+	asserts.NoError(err)
+	var speciesID query.NullInt
+	asserts.NoError(row.Scan(&speciesID))
+	asserts.False(speciesID.Valid)
+
+	// error - animal_id is not null able.
+	toy := Toy{}
+	err = toy.Init(&toy)
+	asserts.NoError(err)
+	err = toy.First(condition.New().SetWhere("id = ?", 1))
+	asserts.NoError(err)
+	toy.AnimalRef = nil
+	err = toy.Update()
+	asserts.Error(err)
+	var relErr *orm.RelationError
+	asserts.True(errors.As(err, &relErr))
+	asserts.Equal("AnimalRef", relErr.Field)
+	asserts.Equal(fmt.Sprintf(orm.ErrBelongsToNull, "orm_test.Toy:AnimalRef", "animal_id"), relErr.Err.Error())
+}
+
+// TestEager_Update_SnapshotFields tests:
+// - If the snapshot only selects the primary keys, the tracked fields and the foreign keys.
+// - If untracked fields are ignored by the change detection and not written.
+// - If untracked fields are written if configured.
+func TestEager_Update_SnapshotFields(t *testing.T) {
+	asserts := assert.New(t)
+	helperCreateDatabaseAndTable(asserts)
+	insertUserData(asserts)
+
+	toy := Toy{}
+	err := toy.Init(&toy)
+	asserts.NoError(err)
+	scope, err := toy.Scope()
+	asserts.NoError(err)
+	scope.SetConfig(orm.NewConfig().SetAllowHasOneZero(true).SetSnapshotFields("Name"))
+
+	// ok: only the tracked field is written.
+	err = toy.First(condition.New().SetWhere("id = ?", 1))
+	asserts.NoError(err)
+	toy.Name = "Ball"
+	toy.Brand = query.NewNullString("Changed", true)
+	var stmts []string
+	builder.SetSQLRewriter(func(kind string, stmt string, args []interface{}) (string, []interface{}) {
+		stmts = append(stmts, stmt)
+		return stmt, args
+	})
+	err = toy.Update()
+	builder.SetSQLRewriter(nil)
+	asserts.NoError(err)
+	// ok: the snapshot only selects the primary key, the tracked field and the foreign key.
+	asserts.True(len(stmts) > 0)
+	asserts.Contains(stmts[0], "SELECT")
+	asserts.Contains(stmts[0], "`id`")
+	asserts.Contains(stmts[0], "`name`")
+	asserts.Contains(stmts[0], "`animal_id`")
+	asserts.NotContains(stmts[0], "`brand`")
+	asserts.NotContains(stmts[0], "`bought_at`")
+	asserts.Equal(1, len(scope.ChangedValues()))
+	asserts.Equal("Name", scope.ChangedValues()[0].Field)
+	row, err := builder.Query().Select("tests.toys").Columns("name", "brand").Where("id = ?", 1).First()
+	asserts.NoError(err)
+	var name string
+	var brand query.NullString
+	asserts.NoError(row.Scan(&name, &brand))
+	asserts.Equal("Ball", name)
+	asserts.Equal("Trixie", brand.String)
+
+	// ok: no tracked field changed.
+	toy.Brand = query.NewNullString("Changed", true)
+	err = toy.Update()
+	asserts.NoError(err)
+	asserts.Equal(0, len(scope.ChangedValues()))
+
+	// ok: untracked fields are written.
+	scope.SetConfig(orm.NewConfig().SetAllowHasOneZero(true).SetSnapshotFields("Name").SetSnapshotWriteUntracked(true))
+	toy.Name = "Bone"
+	toy.Brand = query.NewNullString("Changed", true)
+	err = toy.Update()
+	asserts.NoError(err)
+	row, err = builder.Query().Select("tests.toys").Columns("name", "brand").Where("id = ?", 1).First()
+	asserts.NoError(err)
+	asserts.NoError(row.Scan(&name, &brand))
+	asserts.Equal("Bone", name)
+	asserts.Equal("Changed", brand.String)
+}
+
+// TestEager_Update_RelationSync tests:
+// - If Merge creates new and updates existing entries but keeps omitted entries.
+// - If Replace deletes omitted entries.
+func TestEager_Update_RelationSync(t *testing.T) {
+	asserts := assert.New(t)
+	helperCreateDatabaseAndTable(asserts)
+	insertUserData(asserts)
+
+	animal := Animal{}
+	err := animal.Init(&animal)
+	asserts.NoError(err)
+	scope, err := animal.Scope()
+	asserts.NoError(err)
+	scope.SetConfig(orm.NewConfig().SetAllowHasOneZero(true).SetRelationSync("Toys", orm.Merge))
+
+	toyNames := func() []string {
+		var names []string
+		rows, err := builder.Query().Select("tests.toys").Columns("name").Where("animal_id = ?", 1).Order("id").All()
+		asserts.NoError(err)
+		for rows.Next() {
+			var name string
+			asserts.NoError(rows.Scan(&name))
+			names = append(names, name)
+		}
+		asserts.NoError(rows.Close())
+		return names
+	}
+
+	// ok: merge
+	err = animal.First(condition.New().SetWhere("id = ?", 1))
+	asserts.NoError(err)
+	asserts.Equal(2, len(animal.Toys))
+	kong := animal.Toys[1]
+	kong.Name = "Kong2"
+	animal.Toys = []Toy{kong, {Name: "Ball"}}
+	err = animal.Update()
+	asserts.NoError(err)
+	asserts.Equal([]string{"Bone", "Kong2", "Ball"}, toyNames())
+
+	// ok: merge with an empty slice.
+	animal.Toys = nil
+	err = animal.Update()
+	asserts.NoError(err)
+	asserts.Equal([]string{"Bone", "Kong2", "Ball"}, toyNames())
+
+	// ok: replace
+	scope.SetConfig(orm.NewConfig().SetAllowHasOneZero(true).SetRelationSync("Toys", orm.Replace))
+	err = animal.First(condition.New().SetWhere("id = ?", 1))
+	asserts.NoError(err)
+	animal.Toys = animal.Toys[:1]
+	err = animal.Update()
+	asserts.NoError(err)
+	asserts.Equal([]string{"Bone"}, toyNames())
+}
+
 // TestEager_Update_HasMany_M2M tests:
 // - If hasMany gets added, updated and deleted correctly.
 // - If m2m gets added, updated and deleted correctly.
@@ -310,6 +310,185 @@ func (h Human) DefaultTableName() string {
 	return "humans"
 }
 
+// ctxKey for the hook tests.
+type ctxKey string
+
+// HumanAudit stamps the name from the context on update.
+type HumanAudit struct {
+	Base
+	Name string
+}
+
+func (h HumanAudit) DefaultTableName() string {
+	return "humans"
+}
+
+func (h *HumanAudit) BeforeUpdate(scope orm.Scope) error {
+	if user, ok := scope.Context().Value(ctxKey("user")).(string); ok {
+		h.Name = user
+	}
+	return nil
+}
+
+// AnimalToyCount scans the number of toys from a select expression.
+type AnimalToyCount struct {
+	Base
+	Name     string
+	ToyCount int `orm:"custom;scan:toy_count"`
+}
+
+func (a AnimalToyCount) DefaultTableName() string {
+	return "animals"
+}
+
+// AnimalMissing has a relation to a table which does not exist.
+type AnimalMissing struct {
+	Base
+	Name    string
+	Toys    []Toy          `orm:"refs:AnimalID"`
+	Missing []MissingTable `orm:"refs:AnimalID"`
+}
+
+func (a AnimalMissing) DefaultTableName() string {
+	return "animals"
+}
+
+// MissingTable has no database table.
+type MissingTable struct {
+	Base
+	AnimalID int
+}
+
+// Gadget has an uuid primary key.
+type Gadget struct {
+	orm.Model
+	ID   string
+	Name string
+}
+
+func (g Gadget) DefaultCache() (cache.Manager, time.Duration) {
+	return c, cache.DefaultExpiration
+}
+func (g Gadget) DefaultBuilder() query.Builder {
+	return builder
+}
+
+// Kit has an uuid primary key and hasMany parts with an uuid primary key.
+type Kit struct {
+	orm.Model
+	ID    string
+	Name  string
+	Parts []KitPart
+}
+
+func (k Kit) DefaultCache() (cache.Manager, time.Duration) {
+	return c, cache.DefaultExpiration
+}
+func (k Kit) DefaultBuilder() query.Builder {
+	return builder
+}
+
+type KitPart struct {
+	orm.Model
+	ID    string
+	KitID string
+	Name  string
+}
+
+func (k KitPart) DefaultCache() (cache.Manager, time.Duration) {
+	return c, cache.DefaultExpiration
+}
+func (k KitPart) DefaultBuilder() query.Builder {
+	return builder
+}
+
+// Token has a string primary key, which is generated by a registered generator.
+type Token struct {
+	orm.Model
+	ID   string
+	Name string
+}
+
+func (t Token) DefaultCache() (cache.Manager, time.Duration) {
+	return c, cache.DefaultExpiration
+}
+func (t Token) DefaultBuilder() query.Builder {
+	return builder
+}
+
+// Voucher has hasMany codes, which primary keys are generated by a registered generator.
+type Voucher struct {
+	orm.Model
+	ID    int
+	Name  string
+	Codes []VoucherCode
+}
+
+func (v Voucher) DefaultCache() (cache.Manager, time.Duration) {
+	return c, cache.DefaultExpiration
+}
+func (v Voucher) DefaultBuilder() query.Builder {
+	return builder
+}
+
+type VoucherCode struct {
+	orm.Model
+	ID        string
+	VoucherID int
+}
+
+func (v VoucherCode) DefaultCache() (cache.Manager, time.Duration) {
+	return c, cache.DefaultExpiration
+}
+func (v VoucherCode) DefaultBuilder() query.Builder {
+	return builder
+}
+
+// Ticket has a composite primary key.
+type Ticket struct {
+	orm.Model
+	Code string `orm:"primary"`
+	Seat string `orm:"primary"`
+	Name string
+}
+
+func (t Ticket) DefaultCache() (cache.Manager, time.Duration) {
+	return c, cache.DefaultExpiration
+}
+func (t Ticket) DefaultBuilder() query.Builder {
+	return builder
+}
+
+// Booking has a composite primary key and an unique field.
+type Booking struct {
+	orm.Model
+	EventID   int    `orm:"primary"`
+	Seat      string `orm:"primary"`
+	Reference string
+}
+
+func (b Booking) DefaultCache() (cache.Manager, time.Duration) {
+	return c, cache.DefaultExpiration
+}
+func (b Booking) DefaultBuilder() query.Builder {
+	return builder
+}
+
+type Contact struct {
+	orm.Model
+	ID        int
+	FirstName string
+	LastName  string
+	FullName  string `orm:"readonly"`
+}
+
+func (t Contact) DefaultCache() (cache.Manager, time.Duration) {
+	return c, cache.DefaultExpiration
+}
+func (t Contact) DefaultBuilder() query.Builder {
+	return builder
+}
+
 type HumanPoly struct {
 	Base
 	Name string
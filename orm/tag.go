@@ -0,0 +1,156 @@
+// Copyright (c) 2021 Patrick Ascher <development@fullhouse-productions.com>. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package orm
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/patrickascher/gofer/structer"
+)
+
+// FieldTags is the parsed representation of the orm and validate tags of a struct field.
+// It can be used by external tools (codegen, docs) to get the same view of the tags as the orm.
+type FieldTags struct {
+	Skip        bool
+	Custom      bool
+	Column      string
+	SQLSelect   string
+	Permission  *Permission // nil if no permission tag is defined.
+	Primary     bool
+	Unique      bool
+	UniqueWhere string
+	Order       *int // nil if no order tag is defined.
+	ReadOnly    bool
+	Sensitive   bool
+	Scan        string
+	Validate    string
+
+	// Relation kind (HasOne, BelongsTo, HasMany or ManyToMany). Empty if the field is no relation.
+	// Self referencing slices are reported as HasMany, the model resolves them to ManyToMany on Init.
+	Relation         string
+	ForeignKey       string
+	References       string
+	Polymorphic      bool
+	PolymorphicName  string // value of the poly tag, empty if the default should be used.
+	PolymorphicValue string
+	JoinTable        string
+	JoinForeignKey   string
+	JoinReferences   string
+}
+
+// ParseFieldTags will return the parsed orm and validate tags of the given struct field.
+// The relation kind is set if the field type implements the orm.Interface or is a custom struct, slice or ptr.
+// Error will return if the order tag is no integer or the relation tag is not allowed on the field type.
+func ParseFieldTags(field reflect.StructField) (FieldTags, error) {
+	return parseFieldTags(field, nil)
+}
+
+// parseFieldTags is used by ParseFieldTags and the model Init, so that both have the same view of the tags.
+// The fqdn func is used to name the field in the error messages, if nil the plain field name is used.
+func parseFieldTags(field reflect.StructField, fqdn func(string) string) (FieldTags, error) {
+	ft := FieldTags{Validate: field.Tag.Get(TagValidate)}
+	if field.Tag.Get(TagKey) == tagSkip {
+		ft.Skip = true
+		return ft, nil
+	}
+
+	tags := structer.ParseTag(field.Tag.Get(TagKey))
+	for k, v := range tags {
+		switch k {
+		case tagNoSQLField:
+			ft.Custom = true
+		case tagColumn:
+			ft.Column = v
+		case tagSQLSelect:
+			ft.SQLSelect = v
+		case tagPermission:
+			ft.Permission = &Permission{Read: strings.Contains(v, "r"), Write: strings.Contains(v, "w")}
+		case tagPrimary:
+			ft.Primary = true
+		case tagUnique:
+			ft.Unique = true
+			ft.UniqueWhere = v
+		case tagOrder:
+			order, err := strconv.Atoi(v)
+			if err != nil {
+				return FieldTags{}, fmt.Errorf(ErrFieldOrder, v, fieldName(field, fqdn))
+			}
+			ft.Order = &order
+		case tagReadOnly:
+			ft.ReadOnly = true
+		case tagSensitive:
+			ft.Sensitive = true
+		case tagScan:
+			ft.Scan = v
+		case tagForeignKey:
+			ft.ForeignKey = v
+		case tagReferences:
+			ft.References = v
+		case tagPolymorphic:
+			ft.Polymorphic = true
+			ft.PolymorphicName = v
+		case tagPolymorphicValue:
+			ft.PolymorphicValue = v
+		case tagJoinTable:
+			ft.JoinTable = v
+		case tagJoinFk:
+			ft.JoinForeignKey = v
+		case tagJoinRefs:
+			ft.JoinReferences = v
+		}
+	}
+
+	// relation kind
+	if !implementsInterface(reflect.New(field.Type).Elem()) && !hasCustomTag(field) {
+		return ft, nil
+	}
+	kind, _, err := fieldRelationKind(field, tags, fqdn)
+	if err != nil {
+		return FieldTags{}, err
+	}
+	ft.Relation = kind
+
+	return ft, nil
+}
+
+// fieldRelationKind returns the relation kind defined by tag or the default kind of the field type.
+// The bool reports if the kind was defined by tag. Self referencing slices are reported as HasMany.
+// An empty kind will return if the field type is not supported.
+// Error will return if the relation tag is not allowed on the field type.
+func fieldRelationKind(field reflect.StructField, tags map[string]string, fqdn func(string) string) (string, bool, error) {
+	if tagRel, ok := tags[tagRelation]; ok {
+		if !isTagRelationAllowed(field, tagRel) {
+			return "", true, fmt.Errorf(ErrRelationKind, tagRel, field.Type.Kind().String(), fieldName(field, fqdn))
+		}
+		return tagRel, true, nil
+	}
+
+	switch field.Type.Kind() {
+	case reflect.Struct:
+		return HasOne, false, nil
+	case reflect.Ptr:
+		if field.Type.Elem().Kind() == reflect.Struct {
+			return HasOne, false, nil
+		}
+		if field.Type.Elem().Kind() == reflect.Slice {
+			return HasMany, false, nil
+		}
+	case reflect.Slice:
+		return HasMany, false, nil
+	}
+	return "", false, nil
+}
+
+// fieldName is a helper to return the field name for the error messages.
+// The fqdn func is only called on an error, because the scope name gets cached on the first call.
+func fieldName(field reflect.StructField, fqdn func(string) string) string {
+	if fqdn == nil {
+		return field.Name
+	}
+	return fqdn(field.Name)
+}
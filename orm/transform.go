@@ -0,0 +1,68 @@
+// Copyright (c) 2021 Patrick Ascher <development@fullhouse-productions.com>. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package orm
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Error messages.
+var (
+	ErrReadTransform = "orm: read transform of %s failed: %w"
+)
+
+// readTransforms holds all registered read transforms.
+var readTransforms = struct {
+	sync.RWMutex
+	fn map[string]func(reflect.Value) error
+}{fn: make(map[string]func(reflect.Value) error)}
+
+// RegisterReadTransform will register a function which is called after a field was scanned by the select strategy.
+// The modelField must be defined in the form of scope.FqdnModel (example: "orm_test.Animal:Name").
+// NULL values are skipped. If the function is nil, the transform will be removed.
+func RegisterReadTransform(modelField string, fn func(reflect.Value) error) {
+	readTransforms.Lock()
+	defer readTransforms.Unlock()
+	if fn == nil {
+		delete(readTransforms.fn, modelField)
+		return
+	}
+	readTransforms.fn[modelField] = fn
+}
+
+// readTransform will call all registered read transforms on the scanned fields.
+func readTransform(scope Scope, p Permission) error {
+	readTransforms.RLock()
+	defer readTransforms.RUnlock()
+	if len(readTransforms.fn) == 0 {
+		return nil
+	}
+	for _, field := range scope.SQLFields(p) {
+		name := scope.FqdnModel(field.Name)
+		if fn, ok := readTransforms.fn[name]; ok {
+			if err := transformValue(scope.FieldValue(field.Name), fn); err != nil {
+				return fmt.Errorf(ErrReadTransform, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// transformValue will call the function on the given value.
+// Nil pointers and NULL values are skipped.
+func transformValue(v reflect.Value, fn func(reflect.Value) error) error {
+	if !v.IsValid() || (v.Kind() == reflect.Ptr && v.IsNil()) {
+		return nil
+	}
+	if valuer, ok := v.Interface().(driver.Valuer); ok {
+		if value, err := valuer.Value(); err == nil && value == nil {
+			return nil
+		}
+	}
+	return fn(v)
+}
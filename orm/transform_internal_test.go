@@ -0,0 +1,48 @@
+// Copyright (c) 2021 Patrick Ascher <development@fullhouse-productions.com>. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package orm
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/patrickascher/gofer/query"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTransformValue tests:
+// - If the function is called on the value.
+// - If NULL values and nil pointers are skipped.
+// - If the error of the function returns.
+func TestTransformValue(t *testing.T) {
+	asserts := assert.New(t)
+
+	upper := func(v reflect.Value) error {
+		v.SetString(strings.ToUpper(v.String()))
+		return nil
+	}
+
+	// ok: string gets transformed.
+	s := "gofer"
+	err := transformValue(reflect.ValueOf(&s).Elem(), upper)
+	asserts.NoError(err)
+	asserts.Equal("GOFER", s)
+
+	// ok: NULL value is skipped.
+	ns := query.NullString{}
+	err = transformValue(reflect.ValueOf(&ns).Elem(), func(v reflect.Value) error { return errors.New("called") })
+	asserts.NoError(err)
+
+	// ok: nil ptr is skipped.
+	var ptr *string
+	err = transformValue(reflect.ValueOf(&ptr).Elem(), func(v reflect.Value) error { return errors.New("called") })
+	asserts.NoError(err)
+
+	// error: function returns an error.
+	err = transformValue(reflect.ValueOf(&s).Elem(), func(v reflect.Value) error { return errors.New("called") })
+	asserts.Error(err)
+}
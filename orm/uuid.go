@@ -0,0 +1,24 @@
+// Copyright (c) 2021 Patrick Ascher <development@fullhouse-productions.com>. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package orm
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// UUIDGenerator is used to generate the primary key of UUID columns on create, if the value is zero.
+// By default a random (version 4) UUID is generated. It can be replaced by a custom generator.
+// Only char(36) columns are mapped as UUID. binary(16) columns are not supported, because the generated uuid is a 36 char string.
+var UUIDGenerator = func() (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // variant 10
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
@@ -96,6 +96,12 @@ func (v validator) Config() string {
 	return rv
 }
 
+// copy returns a copy of the validator with its own configuration slice.
+func (v validator) copy() validator {
+	v.config = append([]validatorKeyValue(nil), v.config...)
+	return v
+}
+
 // SetConfig will set the validation configuration.
 func (v *validator) SetConfig(c string) {
 	if skip(c) {
@@ -5,6 +5,7 @@
 package query
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -15,19 +16,105 @@ import (
 
 // Error messages.
 var (
-	ErrDbNotSet = errors.New("query: DB is not set")
+	ErrDbNotSet          = errors.New("query: DB is not set")
+	ErrConnectionTimeout = errors.New("query: connection timeout")
+	ErrUniqueViolation   = errors.New("query: unique violation")
+	ErrScript            = "query: script statement %d failed: %w"
+	ErrLock              = "query: lock %s is not supported by the provider"
+	ErrInterceptResult   = "query: interceptor returned %T instead of %s"
 )
 
+// timeoutError wraps the driver error of a connection timeout.
+type timeoutError struct {
+	err error
+}
+
+// NewTimeoutError returns an error which can be identified by errors.Is(err, ErrConnectionTimeout).
+// The driver error is wrapped and can still be unwrapped.
+func NewTimeoutError(err error) error {
+	return &timeoutError{err: err}
+}
+
+// Error returns the error message.
+func (e *timeoutError) Error() string {
+	return ErrConnectionTimeout.Error() + ": " + e.err.Error()
+}
+
+// Unwrap returns the driver error.
+func (e *timeoutError) Unwrap() error {
+	return e.err
+}
+
+// Is reports if the target is ErrConnectionTimeout.
+func (e *timeoutError) Is(target error) bool {
+	return target == ErrConnectionTimeout
+}
+
+// Rewrite kinds.
+const (
+	RewriteQuery = "query"
+	RewriteExec  = "exec"
+)
+
+// SQLRewriter will be called before a statement gets executed.
+// The kind is RewriteQuery on First and All or RewriteExec on Exec.
+// The returned arguments must still correspond to the placeholders of the returned statement.
+type SQLRewriter func(kind string, stmt string, args []interface{}) (string, []interface{})
+
+// QueryHandler executes the statement.
+// The result is a *sql.Row on First, *sql.Rows on All and a sql.Result on Exec.
+type QueryHandler func(ctx context.Context, kind string, stmt string, args []interface{}) (interface{}, error)
+
+// QueryInterceptor wraps the execution of a statement.
+// The interceptor can modify the statement and arguments before calling next, work with the result afterwards or
+// short-circuit by returning without calling next. On a short-circuit, an error or a result of the expected type must return.
+// The kind is RewriteQuery on First and All or RewriteExec on Exec.
+type QueryInterceptor func(ctx context.Context, kind string, stmt string, args []interface{}, next QueryHandler) (interface{}, error)
+
 // Base struct includes the configuration, logger and transaction logic.
 type Base struct {
 	db       *sql.DB
 	Config   Config
 	Logger   logger.Manager
 	Provider Provider
+	Rewriter SQLRewriter
+
+	// Interceptors are called in registration order around every statement execution.
+	Interceptors []QueryInterceptor
+
+	// StmtCache is set on Open if the Config.PrepareCache is enabled.
+	StmtCache *StmtCache
+
+	ctx      context.Context
+	logTable string
 
 	TransactionBase
 }
 
+// tableLogger is implemented by the Base to pass the table of the builder to the logger.
+type tableLogger interface {
+	setLogTable(string)
+}
+
+// setLogTable is a helper to pass the table of the builder to the logger of the provider.
+func setLogTable(p Provider, table string) {
+	if l, ok := p.(tableLogger); ok {
+		l.setLogTable(table)
+	}
+}
+
+// setLogTable sets the table of the next statement for the logger.
+func (b *Base) setLogTable(table string) {
+	b.logTable = table
+}
+
+// WithContext sets the context which is passed to the interceptors and the driver.
+// If no context is set, context.Background() is used.
+func (b *Base) WithContext(ctx context.Context) Query {
+	b.ctx = ctx
+	return b.Provider
+}
+
 // SetDB sets the *sql.DB.
 func (b *Base) SetDB(db *sql.DB) {
 	b.db = db
@@ -91,48 +178,136 @@ func (b *Base) Tx() (Tx, error) {
 }
 
 // First will return a sql.Row.
-// If a logger is defined, the query will be logged on `DEBUG` lvl with a timer.
+// If a logger is defined, the query will be logged on `DEBUG` lvl with a timer and the structured fields.
 // If a transaction is set, it will run in the transaction.
+// If the prepare cache is enabled and no transaction is set, the cached statement will be used.
+// On a query error the cached statement will be evicted.
 func (b *Base) First(stmt string, args []interface{}) (*sql.Row, error) {
+	stmt, args = b.rewrite(RewriteQuery, stmt, args)
+	table := b.logTable
+	b.logTable = ""
+
 	// set logger
 	if b.Logger != nil {
 		b.Logger = b.Logger.WithTimer()
-		defer b.Logger.Debug(stmt)
+		defer b.Logger.WithFields(b.logFields(table, stmt, args, -1)).Debug(stmt)
 	}
 
-	if b.HasTx() {
-		return b.TransactionBase.Tx.QueryRow(stmt, args...), nil
-	}
+	res, err := b.intercept(RewriteQuery, stmt, args, func(ctx context.Context, kind string, stmt string, args []interface{}) (interface{}, error) {
+		if b.HasTx() {
+			return b.TransactionBase.Tx.QueryRowContext(ctx, stmt, args...), nil
+		}
 
-	return b.Provider.DB().QueryRow(stmt, args...), nil
+		if b.StmtCache != nil {
+			prepared, err := b.StmtCache.Stmt(b.Provider.DB(), stmt)
+			if err != nil {
+				return nil, err
+			}
+			// the query error of a row is only returned on Scan, the statement is evicted anyway.
+			row := prepared.QueryRowContext(ctx, args...)
+			if row.Err() != nil {
+				b.StmtCache.Evict(stmt)
+			}
+			return row, nil
+		}
+
+		return b.Provider.DB().QueryRowContext(ctx, stmt, args...), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	row, ok := res.(*sql.Row)
+	if !ok || row == nil {
+		return nil, fmt.Errorf(ErrInterceptResult, res, "*sql.Row")
+	}
+	return row, nil
 }
 
 // All will return the sql.Rows.
-// If a logger is defined, the query will be logged on `DEBUG` lvl with a timer.
+// If a logger is defined, the query will be logged on `DEBUG` lvl with a timer and the structured fields.
 // If a transaction is set, it will run in the transaction.
+// If the prepare cache is enabled and no transaction is set, the cached statement will be used.
 func (b *Base) All(stmt string, args []interface{}) (*sql.Rows, error) {
+	stmt, args = b.rewrite(RewriteQuery, stmt, args)
+	table := b.logTable
+	b.logTable = ""
+
 	// set logger
 	if b.Logger != nil {
 		b.Logger = b.Logger.WithTimer()
-		defer b.Logger.Debug(stmt)
+		defer b.Logger.WithFields(b.logFields(table, stmt, args, -1)).Debug(stmt)
 	}
 
-	if b.HasTx() {
-		return b.TransactionBase.Tx.Query(stmt, args...)
+	res, err := b.intercept(RewriteQuery, stmt, args, func(ctx context.Context, kind string, stmt string, args []interface{}) (interface{}, error) {
+		if b.HasTx() {
+			return b.TransactionBase.Tx.QueryContext(ctx, stmt, args...)
+		}
+
+		if b.StmtCache != nil {
+			prepared, err := b.StmtCache.Stmt(b.Provider.DB(), stmt)
+			if err != nil {
+				return nil, err
+			}
+			rows, err := prepared.QueryContext(ctx, args...)
+			if err != nil {
+				b.StmtCache.Evict(stmt)
+			}
+			return rows, err
+		}
+
+		return b.Provider.DB().QueryContext(ctx, stmt, args...)
+	})
+	if err != nil {
+		return nil, err
 	}
-	return b.Provider.DB().Query(stmt, args...)
+	rows, ok := res.(*sql.Rows)
+	if !ok || rows == nil {
+		return nil, fmt.Errorf(ErrInterceptResult, res, "*sql.Rows")
+	}
+	return rows, nil
 }
 
 // Exec will execute the statement.
 // Because of the Insert.Batch, multiple statements and arguments can be added and therefore an slice of sql.Result returns.
 // If a transaction is set, it will run in the transaction.
 // If its a batch exec and no transaction is set, it will automatically create one and commits it.
-func (b *Base) Exec(stmt []string, args [][]interface{}) ([]sql.Result, error) {
+// If a logger is defined, the query will be logged on `DEBUG` lvl with a timer and the structured fields.
+// If the prepare cache is enabled and no transaction is set, the cached statement will be used.
+func (b *Base) Exec(stmt []string, args [][]interface{}) (results []sql.Result, err error) {
+
+	// rewrite the statements, the given slices are not modified.
+	if b.Rewriter != nil {
+		rwStmt := make([]string, len(stmt))
+		rwArgs := make([][]interface{}, len(args))
+		copy(rwStmt, stmt)
+		for i := range args {
+			rwStmt[i], rwArgs[i] = b.rewrite(RewriteExec, stmt[i], args[i])
+		}
+		stmt, args = rwStmt, rwArgs
+	}
+
+	table := b.logTable
+	b.logTable = ""
 
 	// set logger
 	if b.Logger != nil {
 		b.Logger = b.Logger.WithTimer()
-		defer b.Logger.Debug(strings.Join(stmt, ", "))
+		defer func() {
+			var flatArgs []interface{}
+			for _, arg := range args {
+				flatArgs = append(flatArgs, arg...)
+			}
+			var rows int64
+			for _, res := range results {
+				if res == nil {
+					continue
+				}
+				if affected, err := res.RowsAffected(); err == nil {
+					rows += affected
+				}
+			}
+			b.Logger.WithFields(b.logFields(table, strings.Join(stmt, ", "), flatArgs, rows)).Debug(strings.Join(stmt, ", "))
+		}()
 	}
 
 	// set a transaction if its a batch
@@ -145,14 +320,27 @@ func (b *Base) Exec(stmt []string, args [][]interface{}) ([]sql.Result, error) {
 		}
 	}
 
-	var results []sql.Result
 	for i, arg := range args {
-		var res sql.Result
-		var err error
-		if b.HasTx() {
-			res, err = b.TransactionBase.Tx.Exec(stmt[i], arg...)
-		} else {
-			res, err = b.db.Exec(stmt[i], arg...)
+		r, err := b.intercept(RewriteExec, stmt[i], arg, func(ctx context.Context, kind string, stmt string, args []interface{}) (interface{}, error) {
+			if b.HasTx() {
+				return b.TransactionBase.Tx.ExecContext(ctx, stmt, args...)
+			}
+			if b.StmtCache != nil {
+				prepared, err := b.StmtCache.Stmt(b.db, stmt)
+				if err != nil {
+					return nil, err
+				}
+				res, err := prepared.ExecContext(ctx, args...)
+				if err != nil {
+					b.StmtCache.Evict(stmt)
+				}
+				return res, err
+			}
+			return b.db.ExecContext(ctx, stmt, args...)
+		})
+		res, ok := r.(sql.Result)
+		if err == nil && (!ok || res == nil) {
+			err = fmt.Errorf(ErrInterceptResult, r, "sql.Result")
 		}
 
 		results = append(results, res)
@@ -177,6 +365,7 @@ func (b *Base) Exec(stmt []string, args [][]interface{}) ([]sql.Result, error) {
 
 // Open will set some basic sql Settings and check the connection.
 // all defined config.Prequeries will run here.
+// If the config.PrepareCache is enabled, the statement cache will be created.
 func (b *Base) Open() error {
 
 	if b.db == nil {
@@ -194,6 +383,11 @@ func (b *Base) Open() error {
 		return err
 	}
 
+	// prepared statement cache
+	if b.Config.PrepareCache && b.StmtCache == nil {
+		b.StmtCache = NewStmtCache()
+	}
+
 	// add pre query
 	if len(b.Config.PreQuery) > 0 {
 		for _, v := range b.Config.PreQuery {
@@ -212,6 +406,63 @@ func (b *Base) SetLogger(logger logger.Manager) {
 	b.Logger = logger
 }
 
+// SetSQLRewriter will set a rewriter which is called before every statement execution.
+func (b *Base) SetSQLRewriter(rewriter SQLRewriter) {
+	b.Rewriter = rewriter
+}
+
+// Use adds the interceptors to the chain.
+// The interceptors are called in registration order, the first registered interceptor is the outermost.
+func (b *Base) Use(interceptors ...QueryInterceptor) {
+	b.Interceptors = append(b.Interceptors, interceptors...)
+}
+
+// intercept is a helper to call the interceptor chain around the handler.
+func (b *Base) intercept(kind string, stmt string, args []interface{}, handler QueryHandler) (interface{}, error) {
+	for i := len(b.Interceptors) - 1; i >= 0; i-- {
+		interceptor, next := b.Interceptors[i], handler
+		handler = func(ctx context.Context, kind string, stmt string, args []interface{}) (interface{}, error) {
+			return interceptor(ctx, kind, stmt, args, next)
+		}
+	}
+	ctx := b.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return handler(ctx, kind, stmt, args)
+}
+
+// rewrite is a helper to call the rewriter, if defined.
+func (b *Base) rewrite(kind string, stmt string, args []interface{}) (string, []interface{}) {
+	if b.Rewriter == nil {
+		return stmt, args
+	}
+	return b.Rewriter(kind, stmt, args)
+}
+
+// logFields is a helper to create the structured log fields of a statement.
+// The operation is taken of the statement, the table is passed by the builder and will not be added if empty.
+// If rows is negative, the field will not be added.
+// If Config.LogValues is enabled, the arguments are added and sensitive arguments are redacted.
+func (b *Base) logFields(table string, stmt string, args []interface{}, rows int64) logger.Fields {
+	fields := logger.Fields{"args": len(args)}
+	if b.Config.LogValues {
+		fields["values"] = redact(args)
+	}
+
+	if words := strings.Fields(stmt); len(words) > 0 {
+		fields["operation"] = strings.ToUpper(words[0])
+	}
+	if table != "" {
+		fields["table"] = table
+	}
+
+	if rows >= 0 {
+		fields["rows"] = rows
+	}
+	return fields
+}
+
 // addColumns is a helper to create a column map out of the value array.
 func addColumns(columns []string, values map[string]interface{}) []string {
 	if len(columns) == 0 {
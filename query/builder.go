@@ -12,6 +12,7 @@
 package query
 
 import (
+	"database/sql"
 	"fmt"
 
 	"github.com/patrickascher/gofer/logger"
@@ -24,6 +25,11 @@ const (
 	dbExpr         = "!"
 )
 
+// Error messages.
+var (
+	ErrProvider = "query: %T does not implement the query.Provider interface"
+)
+
 type providerFn func(interface{}) (Provider, error)
 
 type builder struct {
@@ -65,6 +71,16 @@ func (b *builder) SetLogger(l logger.Manager) {
 	b.provider.SetLogger(l)
 }
 
+// SetSQLRewriter to the query provider.
+func (b *builder) SetSQLRewriter(rewriter SQLRewriter) {
+	b.provider.SetSQLRewriter(rewriter)
+}
+
+// Use adds the interceptors to the query provider.
+func (b *builder) Use(interceptors ...QueryInterceptor) {
+	b.provider.Use(interceptors...)
+}
+
 // Query will return a new query interface.
 func (b *builder) Query(tx ...Tx) Query {
 	if len(tx) == 1 && tx[0] != nil {
@@ -78,11 +94,56 @@ func (b *builder) Config() Config {
 	return b.provider.Config()
 }
 
+// DB will return the underlying *sql.DB for advanced usage.
+func (b *builder) DB() *sql.DB {
+	return b.provider.DB()
+}
+
+// Stats will return the connection pool statistics of the database.
+func (b *builder) Stats() sql.DBStats {
+	return b.DB().Stats()
+}
+
 // Config will return the builder config.
 func (b *builder) QuoteIdentifier(name string) string {
 	return b.provider.QuoteIdentifier(name)
 }
 
+// ExecScript runs the given statements (DDL or DML) sequentially.
+// If the provider supports CapTransactionalDDL, all statements run in one transaction and a rollback happens on error.
+// Otherwise the already executed statements will stay.
+// Error will return with the index of the failed statement or if the query does not implement the Provider interface.
+func (b *builder) ExecScript(statements []string) error {
+	q := b.provider.Query()
+	p, ok := q.(Provider)
+	if !ok {
+		return fmt.Errorf(ErrProvider, q)
+	}
+
+	tx := b.provider.Supports(CapTransactionalDDL)
+	if tx {
+		if _, err := p.Tx(); err != nil {
+			return err
+		}
+	}
+
+	for i, stmt := range statements {
+		_, err := p.Exec([]string{stmt}, [][]interface{}{nil})
+		if err != nil {
+			// the provider rollbacks on an exec error, this is only a fallback.
+			if tx && p.HasTx() {
+				_ = p.Rollback()
+			}
+			return fmt.Errorf(ErrScript, i, err)
+		}
+	}
+
+	if tx {
+		return p.Commit()
+	}
+	return nil
+}
+
 // DbExpr expressions will not get quoted.
 func DbExpr(s string) string {
 	return "!" + s
@@ -5,6 +5,7 @@
 package query_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
@@ -13,6 +14,7 @@ import (
 	"github.com/patrickascher/gofer/query/mocks"
 	"github.com/patrickascher/gofer/registry"
 	"github.com/stretchr/testify/assert"
+	testifyMock "github.com/stretchr/testify/mock"
 )
 
 // TestBuilder tests if the Register and New works correct.
@@ -42,6 +44,12 @@ func testRegister(asserts *assert.Assertions, mock query.Provider) {
 // - error if the provider.Open() function returns one.
 // - correct set.
 // - if the logger gets added correctly.
+// - if the sql rewriter gets added correctly.
+// - Capabilities of the provider.
+// - ExecScript runs in a transaction and returns the index of the failed statement.
+// - ExecScript returns an error if the query is no provider.
+// - QueryRows returns an error if the query is no provider.
+// - ReadOnly rejects write statements, also after WithContext and inside a tx.
 // - DbExpr quote function.
 func testNew(asserts *assert.Assertions, mock *mocks.Provider) {
 
@@ -74,6 +82,16 @@ func testNew(asserts *assert.Assertions, mock *mocks.Provider) {
 	mock.On("SetLogger", nil).Once().Return(nil)
 	builder.SetLogger(nil)
 
+	// SetSQLRewriter
+	mock.On("SetSQLRewriter", testifyMock.AnythingOfType("query.SQLRewriter")).Once()
+	builder.SetSQLRewriter(func(kind string, stmt string, args []interface{}) (string, []interface{}) { return stmt, args })
+
+	// Use
+	mock.On("Use", testifyMock.AnythingOfType("query.QueryInterceptor")).Once()
+	builder.Use(func(ctx context.Context, kind string, stmt string, args []interface{}, next query.QueryHandler) (interface{}, error) {
+		return next(ctx, kind, stmt, args)
+	})
+
 	// Query
 	mock.On("Query").Once().Return(nil)
 	asserts.Nil(builder.Query())
@@ -86,6 +104,86 @@ func testNew(asserts *assert.Assertions, mock *mocks.Provider) {
 	mock.On("QuoteIdentifier", "test").Once().Return("`test`")
 	asserts.Equal("`test`", builder.QuoteIdentifier("test"))
 
+	// Capabilities
+	mock.On("Supports", query.CapILike).Once().Return(false)
+	asserts.False(builder.Capabilities().Supports(query.CapILike))
+
+	// ExecScript - ok
+	mock.On("Query").Once().Return(mock)
+	mock.On("Supports", query.CapTransactionalDDL).Once().Return(true)
+	mock.On("Tx").Once().Return(mock, nil)
+	mock.On("Exec", []string{"CREATE TABLE a"}, [][]interface{}{nil}).Once().Return(nil, nil)
+	mock.On("Commit").Once().Return(nil)
+	asserts.NoError(builder.ExecScript([]string{"CREATE TABLE a"}))
+
+	// ExecScript - error on the second statement, rollback.
+	mock.On("Query").Once().Return(mock)
+	mock.On("Supports", query.CapTransactionalDDL).Once().Return(true)
+	mock.On("Tx").Once().Return(mock, nil)
+	mock.On("Exec", []string{"CREATE TABLE a"}, [][]interface{}{nil}).Once().Return(nil, nil)
+	mock.On("Exec", []string{"CREATE TABLE b"}, [][]interface{}{nil}).Once().Return(nil, errors.New("an error"))
+	mock.On("HasTx").Once().Return(true)
+	mock.On("Rollback").Once().Return(nil)
+	err = builder.ExecScript([]string{"CREATE TABLE a", "CREATE TABLE b", "CREATE TABLE c"})
+	asserts.Error(err)
+	asserts.Equal("query: script statement 1 failed: an error", err.Error())
+
+	// ExecScript - error: query is no provider.
+	mock.On("Query").Once().Return(nil)
+	err = builder.ExecScript([]string{"CREATE TABLE a"})
+	asserts.Error(err)
+	asserts.Equal(fmt.Sprintf(query.ErrProvider, nil), err.Error())
+
+	// QueryRows - error: query is no provider.
+	mock.On("Query").Once().Return(nil)
+	rows, err := builder.QueryRows("SELECT 1")
+	asserts.Nil(rows)
+	asserts.Equal(fmt.Sprintf(query.ErrProvider, nil), err.Error())
+
+	// ReadOnly - write statements are rejected without calling the provider.
+	mock.On("Query").Once().Return(mock)
+	ro := builder.ReadOnly()
+	asserts.Equal(ro, ro.ReadOnly())
+	q := ro.Query()
+	_, err = q.Insert("test").Values([]map[string]interface{}{{"id": 1}}).Exec()
+	asserts.Equal(query.ErrReadOnly, err)
+	_, err = q.Update("test").Set(map[string]interface{}{"id": 1}).Exec()
+	asserts.Equal(query.ErrReadOnly, err)
+	_, err = q.Delete("test").Where("id = ?", 1).Exec()
+	asserts.Equal(query.ErrReadOnly, err)
+	asserts.Equal(query.ErrReadOnly, ro.ExecScript([]string{"DROP TABLE test"}))
+
+	// ReadOnly - write statements are rejected after WithContext and inside a tx.
+	mock.On("Query").Once().Return(mock)
+	mock.On("WithContext", context.Background()).Once().Return(mock)
+	mock.On("Tx").Once().Return(mock, nil)
+	q = ro.Query().WithContext(context.Background())
+	_, err = q.Insert("test").Values([]map[string]interface{}{{"id": 1}}).Exec()
+	asserts.Equal(query.ErrReadOnly, err)
+	_, err = q.Update("test").Set(map[string]interface{}{"id": 1}).Exec()
+	asserts.Equal(query.ErrReadOnly, err)
+	_, err = q.Delete("test").Where("id = ?", 1).Exec()
+	asserts.Equal(query.ErrReadOnly, err)
+	tx, err := q.Tx()
+	asserts.NoError(err)
+	_, err = tx.Insert("test").Values([]map[string]interface{}{{"id": 1}}).Exec()
+	asserts.Equal(query.ErrReadOnly, err)
+	_, err = tx.Update("test").Set(map[string]interface{}{"id": 1}).Exec()
+	asserts.Equal(query.ErrReadOnly, err)
+	_, err = tx.Delete("test").Where("id = ?", 1).Exec()
+	asserts.Equal(query.ErrReadOnly, err)
+
 	// DB Expr
 	asserts.Equal("!test", query.DbExpr("test"))
 }
+
+// TestNewTimeoutError tests if the timeout error can be identified and the driver error unwrapped.
+func TestNewTimeoutError(t *testing.T) {
+	asserts := assert.New(t)
+
+	driverErr := errors.New("dial tcp: i/o timeout")
+	err := fmt.Errorf("query: %w", query.NewTimeoutError(driverErr))
+	asserts.True(errors.Is(err, query.ErrConnectionTimeout))
+	asserts.True(errors.Is(err, driverErr))
+	asserts.Equal("query: query: connection timeout: dial tcp: i/o timeout", err.Error())
+}
@@ -0,0 +1,29 @@
+// Copyright (c) 2021 Patrick Ascher <development@fullhouse-productions.com>. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package query
+
+// Capability is a sql feature which is not supported by all providers.
+type Capability string
+
+// pre-defined capabilities.
+const (
+	CapReturning        Capability = "RETURNING"
+	CapILike            Capability = "ILIKE"
+	CapNullsLast        Capability = "NULLS LAST"
+	CapWindowFunctions  Capability = "WINDOW FUNCTIONS"
+	CapTransactionalDDL Capability = "TRANSACTIONAL DDL"
+	CapJSONContains     Capability = "JSON CONTAINS" // jsonb/array containment (@>) and ANY.
+)
+
+// Capabilities interface.
+type Capabilities interface {
+	Supports(feature Capability) bool
+}
+
+// Capabilities will return the capabilities of the query provider.
+// It can be used to decide at runtime if a native or emulated solution should be used.
+func (b *builder) Capabilities() Capabilities {
+	return b.provider
+}
@@ -19,7 +19,10 @@ var (
 	ErrCrossJoin           = errors.New("query: cross joins are not allowed to have a join condition")
 	ErrJoinType            = "query: join type %d is not allowed"
 	ErrJoinTable           = errors.New("query: join table is mandatory")
+	ErrJoinAlias           = errors.New("query: join alias is mandatory")
 	ErrPlaceholderMismatch = "query: %v placeholder(%d) and arguments(%d) does not fit"
+	ErrNamedArgument       = "query: named argument %s is missing in %v"
+	ErrWhereIn             = "query: SetWhereIn value must be a slice or array, %T given"
 )
 
 // Clause interface.
@@ -31,8 +34,13 @@ type Clause interface {
 // Condition interface.
 type Condition interface {
 	SetWhere(condition string, args ...interface{}) Condition
+	SetWhereNamed(condition string, args map[string]interface{}) Condition
+	SetWhereNullSafeEq(column string, arg interface{}) Condition
+	SetWhereOr(or Condition) Condition
+	SetWhereIn(column string, values interface{}) Condition
 	Where() []Clause
 	SetJoin(joinType int, table string, condition string, args ...interface{}) Condition
+	SetJoinAlias(joinType int, table string, alias string, condition string, args ...interface{}) Condition
 	Join() []Clause
 	SetHaving(condition string, args ...interface{}) Condition
 	Having() []Clause
@@ -174,6 +182,97 @@ func (c *condition) SetWhere(condition string, args ...interface{}) Condition {
 	return c
 }
 
+// SetWhereNamed will create a sql WHERE condition with named arguments.
+// Named arguments are defined as :name and will be converted to positional placeholders.
+// If a name is used multiple times, the argument will be added multiple times.
+// Text inside single or double quotes and type casts (::) are not handled as named arguments.
+// Error will be set if a named argument is missing in the map.
+//		c.SetWhereNamed("id = :id OR parent_id = :id",map[string]interface{}{"id":1})
+func (c *condition) SetWhereNamed(condition string, args map[string]interface{}) Condition {
+	condition, positional, err := namedManipulation(condition, args)
+	if err != nil {
+		c.error = err
+		return c
+	}
+	return c.SetWhere(condition, positional...)
+}
+
+// SetWhereNullSafeEq will create a sql WHERE condition with a null-safe equal operator.
+// The operator is database specific and will be set on render (mysql <=>, postgres IS NOT DISTINCT FROM).
+// This means NULL = NULL will be true.
+//		c.SetWhereNullSafeEq("deleted_at",nil)
+func (c *condition) SetWhereNullSafeEq(column string, arg interface{}) Condition {
+	return c.SetWhere(column+" "+tmpNullSafeEqual+" "+PLACEHOLDER, arg)
+}
+
+// SetWhereIn will create a sql WHERE IN condition for the given column.
+// Every element of the slice will be bound as own argument.
+// An empty slice will render 1=0, because an empty IN is not valid sql.
+// Error will be set if the values are nil or no slice/array.
+//		c.SetWhereIn("id",[]int{10,11,12})
+func (c *condition) SetWhereIn(column string, values interface{}) Condition {
+	if values == nil {
+		c.error = fmt.Errorf(ErrValue, "SetWhereIn")
+		return c
+	}
+	rv := reflect.ValueOf(values)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		c.error = fmt.Errorf(ErrWhereIn, values)
+		return c
+	}
+	if rv.Len() == 0 {
+		return c.SetWhere("1=0")
+	}
+	return c.SetWhere(column+" IN ("+PLACEHOLDER+")", values)
+}
+
+// SetWhereOr will add all WHERE clauses of the given condition as one group chained by the OR operator.
+// The group itself is getting chained by AND with the other WHERE clauses.
+// Nothing will be added if the given condition has no WHERE clauses.
+//		c.SetWhereOr(condition.New().SetWhere("id = ?",1).SetWhere("name = ?","John"))
+func (c *condition) SetWhereOr(or Condition) Condition {
+	if err := or.Error(); err != nil {
+		c.error = err
+		return c
+	}
+	if len(or.Where()) == 0 {
+		return c
+	}
+
+	var stmt []string
+	var args []interface{}
+	for _, w := range or.Where() {
+		stmt = append(stmt, "("+w.Condition()+")")
+		args = append(args, w.Arguments()...)
+	}
+	c.values[WHERE] = append(c.values[WHERE], &clause{condition: "(" + strings.Join(stmt, " OR ") + ")", arguments: args})
+	return c
+}
+
+// Not will return a new condition with all WHERE clauses of the given condition as one negated group chained by the AND operator.
+// The result can be added with Merge or SetWhereOr.
+// An empty condition will return if the given condition has no WHERE clauses.
+//		c.Merge(condition.Not(condition.New().SetWhere("a = ?",1).SetWhere("b = ?",2)))
+func Not(sub Condition) Condition {
+	c := New().(*condition)
+	if err := sub.Error(); err != nil {
+		c.error = err
+		return c
+	}
+	if len(sub.Where()) == 0 {
+		return c
+	}
+
+	var stmt []string
+	var args []interface{}
+	for _, w := range sub.Where() {
+		stmt = append(stmt, "("+w.Condition()+")")
+		args = append(args, w.Arguments()...)
+	}
+	c.values[WHERE] = append(c.values[WHERE], &clause{condition: "NOT (" + strings.Join(stmt, " AND ") + ")", arguments: args})
+	return c
+}
+
 // Where returns the where clause.
 func (c *condition) Where() []Clause {
 	return c.values[WHERE]
@@ -216,6 +315,20 @@ func (c *condition) SetJoin(joinType int, table string, condition string, args .
 	return c
 }
 
+// SetJoinAlias will create a sql JOIN condition with a table alias.
+// The condition must reference the alias. This way the same table can be joined multiple times.
+// If the alias is empty, an error will be set.
+// See SetJoin for the allowed join types.
+func (c *condition) SetJoinAlias(joinType int, table string, alias string, condition string, args ...interface{}) Condition {
+	if alias == "" {
+		c.error = ErrJoinAlias
+	}
+	if table != "" {
+		table += " AS " + alias
+	}
+	return c.SetJoin(joinType, table, condition, args...)
+}
+
 // Join returns the join clause.
 func (c *condition) Join() []Clause {
 	return c.values[JOIN]
@@ -415,7 +528,9 @@ func (c *condition) Render(p Placeholder) (string, []interface{}, error) {
 }
 
 // ReplacePlaceholders will replace the query placeholder with any other placeholder.
+// The null-safe equal operator will be replaced with the database specific operator.
 func ReplacePlaceholders(stmt string, p Placeholder) string {
+	stmt = strings.Replace(stmt, tmpNullSafeEqual, p.nullSafeEqual(), -1)
 	n := strings.Count(stmt, PLACEHOLDER)
 	for i := 1; i <= n; i++ {
 		stmt = strings.Replace(stmt, PLACEHOLDER, p.placeholder(), 1)
@@ -423,6 +538,75 @@ func ReplacePlaceholders(stmt string, p Placeholder) string {
 	return stmt
 }
 
+// namedManipulation converts named arguments (:name) into positional placeholders.
+// Double colons (postgres type casts) and text inside single or double quotes are skipped.
+func namedManipulation(clause string, args map[string]interface{}) (string, []interface{}, error) {
+	var rv strings.Builder
+	var positional []interface{}
+
+	for i := 0; i < len(clause); i++ {
+		// skip quoted text like '10:30', escaped quotes are respected.
+		if clause[i] == '\'' || clause[i] == '"' {
+			end := skipQuoted(clause, i)
+			rv.WriteString(clause[i:end])
+			i = end - 1
+			continue
+		}
+
+		if clause[i] != ':' {
+			rv.WriteByte(clause[i])
+			continue
+		}
+
+		// skip type casts like ::text
+		if i+1 < len(clause) && clause[i+1] == ':' {
+			rv.WriteString("::")
+			i++
+			continue
+		}
+
+		end := i + 1
+		for end < len(clause) && isNamedChar(clause[end]) {
+			end++
+		}
+		if end == i+1 {
+			rv.WriteByte(clause[i])
+			continue
+		}
+
+		name := clause[i+1 : end]
+		arg, ok := args[name]
+		if !ok {
+			return "", nil, fmt.Errorf(ErrNamedArgument, name, clause)
+		}
+		rv.WriteString(PLACEHOLDER)
+		positional = append(positional, arg)
+		i = end - 1
+	}
+
+	return rv.String(), positional, nil
+}
+
+// skipQuoted returns the index after the closing quote of the quoted text which starts at i.
+// A backslash escapes the next character. If no closing quote exists, the length of the clause will return.
+func skipQuoted(clause string, i int) int {
+	quote := clause[i]
+	for end := i + 1; end < len(clause); end++ {
+		switch clause[end] {
+		case '\\':
+			end++
+		case quote:
+			return end + 1
+		}
+	}
+	return len(clause)
+}
+
+// isNamedChar returns true if the byte is allowed in a named argument.
+func isNamedChar(b byte) bool {
+	return b == '_' || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9')
+}
+
 // clauseManipulation is a helper for array or slice arguments.
 func clauseManipulation(clause string, args []interface{}) (string, []interface{}, error) {
 	var err error
@@ -7,9 +7,11 @@ package condition_test
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/patrickascher/gofer/query/condition"
 	"github.com/patrickascher/gofer/query/mocks"
+	"github.com/patrickascher/gofer/query/types"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -170,6 +172,95 @@ func TestCondition_Where_Having(t *testing.T) {
 	}
 }
 
+// TestCondition_SetWhereNamed tests:
+// - named arguments are converted to positional placeholders.
+// - repeated names are duplicating the argument.
+// - slices are getting expanded.
+// - type casts are not handled as named argument.
+// - text inside single or double quotes is not handled as named argument.
+// - error if a named argument is missing.
+func TestCondition_SetWhereNamed(t *testing.T) {
+	asserts := assert.New(t)
+
+	// ok: single and repeated names.
+	c := condition.New()
+	c.SetWhereNamed("id = :id OR (parent_id = :id AND name = :name)", map[string]interface{}{"id": 1, "name": "John"})
+	asserts.NoError(c.Error())
+	asserts.Equal("id = ? OR (parent_id = ? AND name = ?)", c.Where()[0].Condition())
+	asserts.Equal([]interface{}{1, 1, "John"}, c.Where()[0].Arguments())
+
+	// ok: slice argument.
+	c = condition.New()
+	c.SetWhereNamed("id IN (:ids) AND name::text = :name", map[string]interface{}{"ids": []int{1, 2, 3}, "name": "John"})
+	asserts.NoError(c.Error())
+	asserts.Equal("id IN (?, ?, ?) AND name::text = ?", c.Where()[0].Condition())
+	asserts.Equal([]interface{}{1, 2, 3, "John"}, c.Where()[0].Arguments())
+
+	// ok: quoted text.
+	c = condition.New()
+	c.SetWhereNamed("time = '10:30' AND id = :id", map[string]interface{}{"id": 1})
+	asserts.NoError(c.Error())
+	asserts.Equal("time = '10:30' AND id = ?", c.Where()[0].Condition())
+	asserts.Equal([]interface{}{1}, c.Where()[0].Arguments())
+	c = condition.New()
+	c.SetWhereNamed(`note = "a:b" AND name = 'it\'s :name' AND id = :id`, map[string]interface{}{"id": 1})
+	asserts.NoError(c.Error())
+	asserts.Equal(`note = "a:b" AND name = 'it\'s :name' AND id = ?`, c.Where()[0].Condition())
+	asserts.Equal([]interface{}{1}, c.Where()[0].Arguments())
+
+	// ok: postgres casts.
+	c = condition.New()
+	c.SetWhereNamed("created_at::date = :day::date AND '2021-01-01 10:00'::timestamp < updated_at", map[string]interface{}{"day": "2021-01-01"})
+	asserts.NoError(c.Error())
+	asserts.Equal("created_at::date = ?::date AND '2021-01-01 10:00'::timestamp < updated_at", c.Where()[0].Condition())
+	asserts.Equal([]interface{}{"2021-01-01"}, c.Where()[0].Arguments())
+
+	// error: named argument is missing.
+	c = condition.New()
+	c.SetWhereNamed("id = :id AND name = :name", map[string]interface{}{"id": 1})
+	asserts.Equal(fmt.Sprintf(condition.ErrNamedArgument, "name", "id = :id AND name = :name"), c.Error().Error())
+	asserts.Equal(0, len(c.Where()))
+}
+
+// columnKind is a helper for the Validate test.
+type columnKind map[string]string
+
+func (c columnKind) ColumnKind(column string) (string, bool) {
+	kind, ok := c[column]
+	return kind, ok
+}
+
+// TestCondition_Validate tests:
+// - compatible arguments for simple predicates.
+// - slice arguments are validated per element.
+// - complex expressions and unknown columns are skipped.
+// - error if a string is bound to an integer column.
+func TestCondition_Validate(t *testing.T) {
+	asserts := assert.New(t)
+	kinds := columnKind{"id": types.INTEGER, "name": types.TEXT, "active": types.BOOL, "created_at": types.DATETIME}
+
+	// ok: compatible arguments.
+	c := condition.New()
+	c.SetWhere("`id` = ? AND t.name LIKE ? AND active = ?", 1, "%a%", true)
+	c.SetWhere("id IN (?)", []int{1, 2})
+	c.SetWhere("created_at > ?", time.Now())
+	c.SetHaving("COUNT(id) > ?", "1")
+	c.SetWhere("unknown = ?", "a")
+	asserts.NoError(condition.Validate(c, kinds))
+
+	// error: string on an integer column.
+	c = condition.New()
+	c.SetWhere("name = ? AND id = ?", "John", "1")
+	err := condition.Validate(c, kinds)
+	asserts.Error(err)
+	asserts.Equal(fmt.Sprintf(condition.ErrArgumentType, 2, "1", "name = ? AND id = ?", "id", types.INTEGER), err.Error())
+
+	// error: slice element is not compatible.
+	c = condition.New()
+	c.SetWhere("id IN (?)", []interface{}{1, "a"})
+	asserts.Error(condition.Validate(c, kinds))
+}
+
 // TestCondition_Reset tests:
 // - everything gets reset.
 // - every single reset type.
@@ -306,7 +397,9 @@ func TestCopy(t *testing.T) {
 // TestCondition_Join tests:
 // - Allowed join type LEFT,RIGHT,INNER,CROSS
 // - Reset Join.
+// - Aliased joins of the same table.
 // - error on empty table.
+// - error on empty alias.
 // - error on unknown join type.
 func TestCondition_Join(t *testing.T) {
 	asserts := assert.New(t)
@@ -324,6 +417,16 @@ func TestCondition_Join(t *testing.T) {
 	asserts.Equal("LEFT JOIN test ON e IN (?, ?) RIGHT JOIN test ON f = ? INNER JOIN test ON f = ? CROSS JOIN test", stmt)
 	asserts.Equal([]interface{}{6, 7, 3, 4}, args)
 
+	// ok - same table with aliases
+	c = condition.New()
+	c.SetJoinAlias(condition.LEFT, "test", "t1", "t1.a = b AND t1.c = ?", 1)
+	c.SetJoinAlias(condition.INNER, "test", "t2", "t2.a = b AND t2.c IN (?)", []int{2, 3})
+	provider.On("Placeholder").Once().Return(condition.Placeholder{Char: "?"})
+	stmt, args, err = c.Render(provider.Placeholder())
+	asserts.NoError(err)
+	asserts.Equal("LEFT JOIN test AS t1 ON t1.a = b AND t1.c = ? INNER JOIN test AS t2 ON t2.a = b AND t2.c IN (?, ?)", stmt)
+	asserts.Equal([]interface{}{1, 2, 3}, args)
+
 	// test reset
 	c.Reset(condition.JOIN)
 	provider.On("Placeholder").Once().Return(condition.Placeholder{Char: "?"})
@@ -348,6 +451,14 @@ func TestCondition_Join(t *testing.T) {
 	asserts.Error(err)
 	asserts.Equal(condition.ErrJoinTable.Error(), err.Error())
 
+	// test empty alias
+	c = condition.New()
+	c.SetJoinAlias(condition.LEFT, "test", "", "a = b")
+	provider.On("Placeholder").Once().Return(condition.Placeholder{Char: "?"})
+	stmt, args, err = c.Render(provider.Placeholder())
+	asserts.Error(err)
+	asserts.Equal(condition.ErrJoinAlias.Error(), err.Error())
+
 	// test wrong join type
 	c = condition.New()
 	c.SetJoin(10, "", "a = b AND c = ?", 5)
@@ -360,6 +471,120 @@ func TestCondition_Join(t *testing.T) {
 	provider.AssertExpectations(t)
 }
 
+// TestCondition_SetWhereNullSafeEq tests:
+// - If the database specific operator is rendered.
+// - If the sql standard operator is used as default.
+func TestCondition_SetWhereNullSafeEq(t *testing.T) {
+	asserts := assert.New(t)
+	c := condition.New()
+	c.SetWhereNullSafeEq("a", nil)
+	c.SetWhere("b = ?", 1)
+
+	// ok: mysql operator.
+	stmt, args, err := c.Render(condition.Placeholder{Char: "?", NullSafeEqual: "<=>"})
+	asserts.NoError(err)
+	asserts.Equal("WHERE a <=> ? AND b = ?", stmt)
+	asserts.Equal([]interface{}{nil, 1}, args)
+
+	// ok: default operator with numeric placeholder.
+	stmt, args, err = c.Render(condition.Placeholder{Char: "$", Numeric: true})
+	asserts.NoError(err)
+	asserts.Equal("WHERE a IS NOT DISTINCT FROM $1 AND b = $2", stmt)
+	asserts.Equal([]interface{}{nil, 1}, args)
+}
+
+// TestCondition_SetWhereIn tests:
+// - If every slice element is bound as argument.
+// - If an empty slice renders 1=0.
+// - If an error is set on nil or none slice values.
+func TestCondition_SetWhereIn(t *testing.T) {
+	asserts := assert.New(t)
+
+	// ok: string slice.
+	c := condition.New().SetWhereIn("name", []string{"John", "Doe"})
+	stmt, args, err := c.Render(condition.Placeholder{Char: "?"})
+	asserts.NoError(err)
+	asserts.Equal("WHERE name IN (?, ?)", stmt)
+	asserts.Equal([]interface{}{"John", "Doe"}, args)
+
+	// ok: int slice.
+	c = condition.New().SetWhereIn("id", []int{1, 2, 3})
+	stmt, args, err = c.Render(condition.Placeholder{Char: "$", Numeric: true})
+	asserts.NoError(err)
+	asserts.Equal("WHERE id IN ($1, $2, $3)", stmt)
+	asserts.Equal([]interface{}{1, 2, 3}, args)
+
+	// ok: empty slice.
+	c = condition.New().SetWhereIn("id", []int{})
+	stmt, args, err = c.Render(condition.Placeholder{Char: "?"})
+	asserts.NoError(err)
+	asserts.Equal("WHERE 1=0", stmt)
+	asserts.Nil(args)
+
+	// error: nil.
+	c = condition.New().SetWhereIn("id", nil)
+	asserts.Error(c.Error())
+	asserts.Equal(fmt.Sprintf(condition.ErrValue, "SetWhereIn"), c.Error().Error())
+
+	// error: no slice.
+	c = condition.New().SetWhereIn("id", 1)
+	asserts.Error(c.Error())
+	asserts.Equal(fmt.Sprintf(condition.ErrWhereIn, 1), c.Error().Error())
+}
+
+// TestCondition_SetWhereOr tests:
+// - If the where clauses are added as one OR group.
+// - If nothing is added on an empty condition.
+// - If the error of the given condition is passed.
+func TestCondition_SetWhereOr(t *testing.T) {
+	asserts := assert.New(t)
+	c := condition.New()
+	c.SetWhere("a = ?", 1)
+
+	// ok: empty condition.
+	c.SetWhereOr(condition.New())
+	asserts.Equal(1, len(c.Where()))
+
+	// ok: or group.
+	c.SetWhereOr(condition.New().SetWhere("b = ?", 2).SetWhere("c IN (?)", []int{3, 4}))
+	stmt, args, err := c.Render(condition.Placeholder{Char: "?"})
+	asserts.NoError(err)
+	asserts.Equal("WHERE a = ? AND ((b = ?) OR (c IN (?, ?)))", stmt)
+	asserts.Equal([]interface{}{1, 2, 3, 4}, args)
+
+	// error: placeholder mismatch.
+	c.SetWhereOr(condition.New().SetWhere("b = ?"))
+	asserts.Error(c.Error())
+}
+
+// TestNot tests:
+// - if the where clauses are negated as one group.
+// - if the arguments are kept in order and placeholders are counted.
+// - if an error of the sub condition is passed.
+func TestNot(t *testing.T) {
+	asserts := assert.New(t)
+
+	// ok: empty condition.
+	asserts.Equal(0, len(condition.Not(condition.New()).Where()))
+
+	// ok: negated group.
+	c := condition.New().SetWhere("a = ?", 1)
+	c.Merge(condition.Not(condition.New().SetWhere("b = ?", 2).SetWhere("c IN (?)", []int{3, 4})))
+	c.SetWhere("d = ?", 5)
+	stmt, args, err := c.Render(condition.Placeholder{Char: "?"})
+	asserts.NoError(err)
+	asserts.Equal("WHERE a = ? AND NOT ((b = ?) AND (c IN (?, ?))) AND d = ?", stmt)
+	asserts.Equal([]interface{}{1, 2, 3, 4, 5}, args)
+
+	// ok: numeric placeholders.
+	stmt, _, err = c.Render(condition.Placeholder{Char: ":", Numeric: true})
+	asserts.NoError(err)
+	asserts.Equal("WHERE a = :1 AND NOT ((b = :2) AND (c IN (:3, :4))) AND d = :5", stmt)
+
+	// error: placeholder mismatch.
+	asserts.Error(condition.Not(condition.New().SetWhere("b = ?")).Error())
+}
+
 // TestCondition_Render tests:
 // - every condition is rendered in the correct order.
 // - numeric placeholders
@@ -8,14 +8,21 @@ import "strconv"
 
 const tmpPlaceholder = "§$%"
 
+// tmpNullSafeEqual is replaced with the database null-safe equal operator on render.
+const tmpNullSafeEqual = "§<=>§"
+
+// defaultNullSafeEqual is the sql standard null-safe equal operator.
+const defaultNullSafeEqual = "IS NOT DISTINCT FROM"
+
 // PLACEHOLDER character.
 const PLACEHOLDER = "?"
 
 // Placeholder is used to ensure an unique placeholder for different database adapters.
 type Placeholder struct {
-	Numeric bool   // must be true if the database uses something like $1,$2,...
-	counter int    // internal counter for numeric placeholder
-	Char    string // database placeholder character
+	Numeric       bool   // must be true if the database uses something like $1,$2,...
+	counter       int    // internal counter for numeric placeholder
+	Char          string // database placeholder character
+	NullSafeEqual string // database null-safe equal operator, if empty IS NOT DISTINCT FROM is used.
 }
 
 // hasCounter returns true if the counter is numeric.
@@ -32,3 +39,11 @@ func (p *Placeholder) placeholder() string {
 	}
 	return p.Char
 }
+
+// nullSafeEqual returns the null-safe equal operator.
+func (p *Placeholder) nullSafeEqual() string {
+	if p.NullSafeEqual != "" {
+		return p.NullSafeEqual
+	}
+	return defaultNullSafeEqual
+}
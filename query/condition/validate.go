@@ -0,0 +1,94 @@
+// Copyright (c) 2021 Patrick Ascher <development@fullhouse-productions.com>. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package condition
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/patrickascher/gofer/query/types"
+)
+
+// Error messages.
+var (
+	ErrArgumentType = "query: argument %d (%v) of %s is not compatible with column %s (%s)"
+)
+
+// regSimplePredicate matches a simple predicate like `col OP ` in front of a placeholder.
+var regSimplePredicate = regexp.MustCompile("([\\w.`\"]+)\\s*(?:=|!=|<>|<=|>=|<|>|(?i:\\sLIKE)|(?i:\\sIN\\s*\\())\\s*$")
+
+// ColumnKind is used to receive the sanitized column kind (types.INTEGER,...) by the column name.
+type ColumnKind interface {
+	ColumnKind(column string) (string, bool)
+}
+
+// Validate will check the arguments of WHERE and HAVING clauses against the column kind.
+// This is a best-effort check, only simple predicates (col OP ?) are validated and complex expressions are skipped.
+// Nil values and types other than the basic go types or time.Time are skipped.
+// Error will return if an argument is not compatible with the column kind.
+//		err := condition.Validate(c, scope)
+func Validate(c Condition, kinds ColumnKind) error {
+	for _, clauses := range [][]Clause{c.Where(), c.Having()} {
+		for _, cl := range clauses {
+			segments := strings.SplitAfter(cl.Condition(), PLACEHOLDER)
+			column := ""
+			for i, arg := range cl.Arguments() {
+				if i >= len(segments) {
+					break
+				}
+				segment := strings.TrimSuffix(segments[i], PLACEHOLDER)
+				// expanded slice arguments are using the column of the previous placeholder.
+				if column == "" || strings.TrimSpace(segment) != "," {
+					column = ""
+					match := regSimplePredicate.FindStringSubmatch(segment)
+					if match == nil {
+						continue
+					}
+					column = strings.NewReplacer("`", "", "\"", "").Replace(match[1])
+					if idx := strings.LastIndex(column, "."); idx != -1 {
+						column = column[idx+1:]
+					}
+				}
+				kind, ok := kinds.ColumnKind(column)
+				if !ok {
+					continue
+				}
+				if !argumentCompatible(kind, arg) {
+					return fmt.Errorf(ErrArgumentType, i+1, arg, cl.Condition(), column, kind)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// argumentCompatible checks if the go type of the argument fits the column kind.
+func argumentCompatible(kind string, arg interface{}) bool {
+	if arg == nil {
+		return true
+	}
+	if _, ok := arg.(time.Time); ok {
+		return kind == types.DATE || kind == types.DATETIME || kind == types.TIME
+	}
+
+	v := reflect.ValueOf(arg)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return kind != types.DATE && kind != types.DATETIME && kind != types.TIME
+	case reflect.Float32, reflect.Float64:
+		return kind == types.FLOAT || kind == types.TEXT || kind == types.TEXTAREA
+	case reflect.Bool:
+		return kind == types.BOOL || kind == types.INTEGER
+	case reflect.String:
+		return kind != types.INTEGER && kind != types.FLOAT && kind != types.BOOL
+	}
+
+	// skip all other types (structs, valuer,...)
+	return true
+}
@@ -4,7 +4,10 @@
 
 package query
 
-import "time"
+import (
+	"crypto/tls"
+	"time"
+)
 
 // Config sql struct.
 type Config struct {
@@ -20,6 +23,11 @@ type Config struct {
 	MaxOpenConnections int
 	MaxConnLifetime    time.Duration
 	Timeout            string
+	PrepareCache       bool // prepared statements are cached by the rendered sql.
+	LogValues          bool // the statement arguments are added to the log fields, sensitive arguments are redacted.
 
-	PreQuery []string `mapstructure:",omitempty"`
+	PreQuery  []string       `mapstructure:",omitempty"`
+	OnConnect OnConnect      `mapstructure:"-"` // called on every new pooled connection.
+	Location  *time.Location `mapstructure:"-"` // date/time columns are scanned and bound in this location.
+	TLS       *tls.Config    `mapstructure:"-"` // the connection is encrypted with this configuration.
 }
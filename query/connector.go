@@ -0,0 +1,97 @@
+// Copyright (c) 2021 Patrick Ascher <development@fullhouse-productions.com>. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package query
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+)
+
+// OnConnect is called on every new pooled connection.
+// It can be used to set session variables (timezone, sql_mode, ...) which must apply per connection.
+type OnConnect func(ctx context.Context, conn driver.Conn) error
+
+// Open opens a *sql.DB like sql.Open.
+// If the Config.OnConnect hook is set, it will be called on every new connection of the pool.
+// Error will return if the driver does not exist or the connector can not be created.
+func Open(driverName string, dsn string, config Config) (*sql.DB, error) {
+	db, err := sql.Open(driverName, dsn)
+	if err != nil || config.OnConnect == nil {
+		return db, err
+	}
+
+	// replace the db by a connector with the hook.
+	d := db.Driver()
+	err = db.Close()
+	if err != nil {
+		return nil, err
+	}
+	var c driver.Connector
+	if dc, ok := d.(driver.DriverContext); ok {
+		c, err = dc.OpenConnector(dsn)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		c = &dsnConnector{dsn: dsn, driver: d}
+	}
+
+	return sql.OpenDB(&connector{Connector: c, onConnect: config.OnConnect}), nil
+}
+
+// ConnExec is a helper to execute a statement on a driver connection in the OnConnect hook.
+func ConnExec(ctx context.Context, conn driver.Conn, stmt string) error {
+	if e, ok := conn.(driver.ExecerContext); ok {
+		_, err := e.ExecContext(ctx, stmt, nil)
+		if err != driver.ErrSkip {
+			return err
+		}
+	}
+
+	s, err := conn.Prepare(stmt)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+	_, err = s.Exec(nil)
+	return err
+}
+
+// connector wraps the driver connector and calls the hook on every new connection.
+type connector struct {
+	driver.Connector
+	onConnect OnConnect
+}
+
+// Connect returns a new connection and calls the hook.
+// The connection will be closed if the hook returns an error.
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.onConnect(ctx, conn); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// dsnConnector is used for drivers which are not implementing the driver.DriverContext.
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+// Connect opens a new connection by dsn.
+func (c *dsnConnector) Connect(_ context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+// Driver returns the underlying driver.
+func (c *dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2021 Patrick Ascher <development@fullhouse-productions.com>. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package query_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/patrickascher/gofer/query"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestOpen tests:
+// - if the OnConnect hook is called on every new pooled connection.
+// - if the statement of the hook is executed on the connection.
+// - if an error returns if the hook returns one.
+func TestOpen(t *testing.T) {
+	asserts := assert.New(t)
+
+	d := &countDriver{}
+	sql.Register("query_connect", d)
+
+	// ok: no hook.
+	db, err := query.Open("query_connect", "", query.Config{})
+	asserts.NoError(err)
+	asserts.NoError(db.Ping())
+	asserts.NoError(db.Close())
+
+	// ok: hook is called on every new connection.
+	connections := 0
+	db, err = query.Open("query_connect", "", query.Config{OnConnect: func(ctx context.Context, conn driver.Conn) error {
+		connections++
+		return query.ConnExec(ctx, conn, "SET time_zone = '+00:00'")
+	}})
+	asserts.NoError(err)
+	db.SetMaxIdleConns(0)
+	asserts.NoError(db.Ping())
+	asserts.NoError(db.Ping())
+	asserts.Equal(2, connections)
+	asserts.Equal(2, d.prepared)
+	asserts.NoError(db.Close())
+
+	// error: hook returns an error.
+	db, err = query.Open("query_connect", "", query.Config{OnConnect: func(ctx context.Context, conn driver.Conn) error {
+		return errors.New("an error")
+	}})
+	asserts.NoError(err)
+	asserts.Equal("an error", db.Ping().Error())
+	asserts.NoError(db.Close())
+}
@@ -49,6 +49,7 @@ func (d *DeleteBase) Exec() (sql.Result, error) {
 	}
 
 	// call provider exec with data
+	setLogTable(d.Provider, d.DTable)
 	res, err := d.Provider.Exec([]string{stmt}, [][]interface{}{args})
 	if err != nil {
 		return nil, err
@@ -0,0 +1,32 @@
+// Copyright (c) 2021 Patrick Ascher <development@fullhouse-productions.com>. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package query
+
+import "github.com/patrickascher/gofer/query/condition"
+
+// Expression is a raw sql expression with its own arguments.
+// It can be used as value in Insert.Values and Update.Set.
+type Expression struct {
+	SQL  string
+	Args []interface{}
+}
+
+// Expr creates a new sql expression.
+// The expression will be rendered as it is instead of a placeholder.
+// Arguments must be defined with the placeholder character "?".
+//
+//	Set(map[string]interface{}{"views": query.Expr("views + ?", 1)})
+func Expr(sql string, args ...interface{}) Expression {
+	return Expression{SQL: sql, Args: args}
+}
+
+// valueStmt is a helper to return the placeholder and arguments of a value.
+// If the value is an Expression, the raw sql and its arguments will return.
+func valueStmt(value interface{}) (string, []interface{}) {
+	if expr, ok := value.(Expression); ok {
+		return expr.SQL, expr.Args
+	}
+	return condition.PLACEHOLDER, []interface{}{value}
+}
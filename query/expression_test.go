@@ -0,0 +1,43 @@
+// Copyright (c) 2021 Patrick Ascher <development@fullhouse-productions.com>. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package query_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/patrickascher/gofer/query"
+	"github.com/patrickascher/gofer/query/condition"
+	"github.com/patrickascher/gofer/query/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestExpr tests:
+// - if an expression is rendered as it is in an update.
+// - if an expression with arguments is rendered in an insert.
+func TestExpr(t *testing.T) {
+	asserts := assert.New(t)
+
+	provider := new(mocks.Provider)
+	provider.On("QuoteIdentifier", mock.Anything).Return(func(s ...string) string { return s[0] })
+	provider.On("QuoteIdentifier", mock.Anything, mock.Anything).Return(func(s ...string) string { return strings.Join(s, ", ") })
+	provider.On("Placeholder").Return(condition.Placeholder{Char: "?"})
+	provider.On("Config").Return(query.Config{Database: "tests"})
+
+	// ok: update
+	u := &query.UpdateBase{Provider: provider, UTable: "posts"}
+	stmt, args, err := u.Set(map[string]interface{}{"views": query.Expr("views + 1")}).Where("id = ?", 1).String()
+	asserts.NoError(err)
+	asserts.Equal("UPDATE posts SET views = views + 1 WHERE id = ?", stmt)
+	asserts.Equal([]interface{}{1}, args)
+
+	// ok: insert
+	i := &query.InsertBase{Provider: provider, ITable: "posts"}
+	stmts, iArgs, err := i.Columns("title", "views").Values([]map[string]interface{}{{"title": "a", "views": query.Expr("? + 1", 2)}, {"title": "b", "views": 3}}).String()
+	asserts.NoError(err)
+	asserts.Equal([]string{"INSERT INTO posts(title, views) VALUES (?, ? + 1), (?, ?)"}, stmts)
+	asserts.Equal([][]interface{}{{"a", 2, "b", 3}}, iArgs)
+}
@@ -21,6 +21,7 @@ var (
 	ErrValueMissing = "query: no %s value is set (%s)"
 	ErrColumn       = "query: column (%s) does not exist in (%s)"
 	ErrLastID       = errors.New("query: last id must be a ptr")
+	ErrReturning    = "query: returning requires one value set and a destination for each column (%s)"
 )
 
 // InsertBase can be embedded and changed for different providers.
@@ -34,6 +35,10 @@ type InsertBase struct {
 	IBatchSize int
 	IArguments [][]interface{}
 	ILastID    interface{}
+	ILastIDs   []interface{}
+
+	IReturning     []string
+	IReturningDest []interface{}
 }
 
 // Batch sets the batching size.
@@ -69,6 +74,19 @@ func (i *InsertBase) Exec() ([]sql.Result, error) {
 		return nil, err
 	}
 
+	// scan the returned row, if the provider supports it.
+	if i.returning() {
+		if len(i.IValues) != 1 || len(i.IReturning) != len(i.IReturningDest) {
+			return nil, fmt.Errorf(ErrReturning, i.Provider.Config().Database+"."+i.ITable)
+		}
+		setLogTable(i.Provider, i.ITable)
+		row, err := i.Provider.First(stmt[0], args[0])
+		if err != nil {
+			return nil, err
+		}
+		return nil, row.Scan(i.IReturningDest...)
+	}
+
 	// check if lastID is a ptr value
 	var id reflect.Value
 	if i.ILastID != nil {
@@ -77,8 +95,14 @@ func (i *InsertBase) Exec() ([]sql.Result, error) {
 			return nil, ErrLastID
 		}
 	}
+	for _, lastID := range i.ILastIDs {
+		if reflect.ValueOf(lastID).Kind() != reflect.Ptr {
+			return nil, ErrLastID
+		}
+	}
 
 	// call provider exec with data
+	setLogTable(i.Provider, i.ITable)
 	res, err := i.Provider.Exec(stmt, args)
 
 	// update last id
@@ -88,6 +112,25 @@ func (i *InsertBase) Exec() ([]sql.Result, error) {
 		id.Elem().SetInt(lastID)
 	}
 
+	// update last ids of a multi value insert.
+	// the ids are set consecutively from the first id of each batch statement.
+	if err == nil && len(i.ILastIDs) > 0 {
+		batchSize := len(i.IValues)
+		if i.isBatched() {
+			batchSize = i.IBatchSize
+		}
+		for n, r := range res {
+			var lastID int64
+			lastID, err = r.LastInsertId()
+			if err != nil {
+				return res, err
+			}
+			for k := 0; k < batchSize && n*batchSize+k < len(i.ILastIDs); k++ {
+				reflect.ValueOf(i.ILastIDs[n*batchSize+k]).Elem().SetInt(lastID + int64(k))
+			}
+		}
+	}
+
 	return res, err
 }
 
@@ -99,6 +142,31 @@ func (i *InsertBase) LastInsertedID(id ...interface{}) Insert {
 	return i
 }
 
+// Returning defines the columns which should be returned by the insert and scanned into dest.
+// It is only used if the provider supports query.CapReturning, otherwise it will be ignored and
+// LastInsertedID must be used. The returned []sql.Result of Exec will be nil in that case.
+// Error will return on Exec if more than one value set is inserted or the dest length does not match.
+func (i *InsertBase) Returning(columns []string, dest ...interface{}) Insert {
+	i.IReturning = columns
+	i.IReturningDest = dest
+	return i
+}
+
+// returning checks if a returning clause should be used.
+func (i *InsertBase) returning() bool {
+	return len(i.IReturning) > 0 && i.Provider.Supports(CapReturning)
+}
+
+// LastInsertedIDs gets the ids of a multi value insert.
+// The arguments must be a ptr to the value field of each value set, in the same order as the values.
+// The ids are calculated consecutively by the first id of each batch statement, which is the behavior
+// of mysql for simple inserts with auto_increment_increment=1.
+// The ids are wrong if the database does not assign consecutive ids, there is no check for it.
+func (i *InsertBase) LastInsertedIDs(ids ...interface{}) Insert {
+	i.ILastIDs = ids
+	return i
+}
+
 // Render the sql query.
 func (i *InsertBase) Render() ([]string, [][]interface{}, error) {
 
@@ -110,31 +178,53 @@ func (i *InsertBase) Render() ([]string, [][]interface{}, error) {
 	// set columns if the were not set manually.
 	i.IColumns = addColumns(i.IColumns, i.IValues[0])
 
-	// add arguments
-	var arguments []interface{}
-	for _, valueSet := range i.IValues {
-		for _, column := range i.IColumns {
-			if val, ok := valueSet[column]; ok {
-				arguments = append(arguments, val)
-			} else {
+	// add the value placeholders and arguments per row.
+	// expressions are rendered as they are.
+	rowStmts := make([]string, len(i.IValues))
+	rowArgs := make([][]interface{}, len(i.IValues))
+	for n, valueSet := range i.IValues {
+		placeholders := make([]string, len(i.IColumns))
+		for c, column := range i.IColumns {
+			val, ok := valueSet[column]
+			if !ok {
 				return nil, nil, fmt.Errorf(ErrColumn, column, i.Provider.Config().Database+"."+i.ITable)
 			}
+			var args []interface{}
+			placeholders[c], args = valueStmt(val)
+			rowArgs[n] = append(rowArgs[n], args...)
 		}
+		rowStmts[n] = "(" + strings.Join(placeholders, ", ") + ")"
 	}
 
-	i.IArguments = append(i.IArguments, arguments)
+	// render
+	selectStmt := "INSERT INTO " + i.Provider.QuoteIdentifier(i.ITable) + "(" + i.Provider.QuoteIdentifier(i.IColumns...) + ") VALUES "
 
 	// check if batching is required
+	batchSize := len(i.IValues)
 	if i.isBatched() {
-		i.batchArguments()
+		batchSize = i.IBatchSize
 	}
 
-	// render
-	selectStmt := "INSERT INTO " + i.Provider.QuoteIdentifier(i.ITable) + "(" + i.Provider.QuoteIdentifier(i.IColumns...) + ") VALUES "
-	//set the value placeholders
-	valueStmt := "(" + condition.PLACEHOLDER + strings.Repeat(", "+condition.PLACEHOLDER, len(i.IColumns)-1) + ")"
+	var stmts []string
+	i.IArguments = nil
+	for start := 0; start < len(rowStmts); start += batchSize {
+		end := start + batchSize
+		if end > len(rowStmts) {
+			end = len(rowStmts)
+		}
+		var arguments []interface{}
+		for _, args := range rowArgs[start:end] {
+			arguments = append(arguments, args...)
+		}
+		stmt := selectStmt + strings.Join(rowStmts[start:end], ", ")
+		if i.returning() {
+			stmt += " RETURNING " + i.Provider.QuoteIdentifier(i.IReturning...)
+		}
+		stmts = append(stmts, condition.ReplacePlaceholders(stmt, i.Provider.Placeholder()))
+		i.IArguments = append(i.IArguments, arguments)
+	}
 
-	return i.batchStatement(selectStmt, valueStmt), i.IArguments, nil
+	return stmts, i.IArguments, nil
 }
 
 // isBatched checks if a batching is needed.
@@ -144,23 +234,3 @@ func (i *InsertBase) isBatched() bool {
 	}
 	return len(i.IValues) > i.IBatchSize
 }
-
-// batchArguments will create argument slices depending on the batching size.
-func (i *InsertBase) batchArguments() {
-	batchCount := i.IBatchSize * len(i.IColumns)
-	var batches [][]interface{}
-	for batchCount < len(i.IArguments[0]) {
-		i.IArguments[0], batches = i.IArguments[0][batchCount:], append(batches, i.IArguments[0][0:batchCount:batchCount])
-	}
-	i.IArguments = append(batches, i.IArguments[0])
-}
-
-// batchStatement will create statement slices depending on the batching size.
-func (i *InsertBase) batchStatement(stmt string, values string) []string {
-	var rv []string
-	for _, args := range i.IArguments {
-		tmp := condition.ReplacePlaceholders(stmt+strings.Repeat(values+", ", len(args)/strings.Count(values, "?")), i.Provider.Placeholder())
-		rv = append(rv, tmp[:len(tmp)-2])
-	}
-	return rv
-}
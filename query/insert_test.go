@@ -0,0 +1,103 @@
+// Copyright (c) 2021 Patrick Ascher <development@fullhouse-productions.com>. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package query_test
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/patrickascher/gofer/query"
+	"github.com/patrickascher/gofer/query/condition"
+	"github.com/patrickascher/gofer/query/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestInsertBase_Returning tests:
+// - returning clause is rendered if the provider supports it.
+// - returning is ignored if the provider does not support it.
+// - error if more than one value set is inserted.
+func TestInsertBase_Returning(t *testing.T) {
+	asserts := assert.New(t)
+
+	var id, title interface{}
+	mockProvider := func(supported bool) *mocks.Provider {
+		provider := new(mocks.Provider)
+		provider.On("QuoteIdentifier", mock.Anything).Return(func(s ...string) string { return s[0] })
+		provider.On("QuoteIdentifier", mock.Anything, mock.Anything).Return(func(s ...string) string { return strings.Join(s, ", ") })
+		provider.On("Placeholder").Return(condition.Placeholder{Char: "$", Numeric: true})
+		provider.On("Config").Return(query.Config{Database: "tests"})
+		provider.On("Supports", query.CapReturning).Return(supported)
+		return provider
+	}
+
+	// ok: supported
+	i := &query.InsertBase{Provider: mockProvider(true), ITable: "posts"}
+	stmts, args, err := i.Columns("title").Values([]map[string]interface{}{{"title": "a"}}).Returning([]string{"id", "title"}, &id, &title).String()
+	asserts.NoError(err)
+	asserts.Equal([]string{"INSERT INTO posts(title) VALUES ($1) RETURNING id, title"}, stmts)
+	asserts.Equal([][]interface{}{{"a"}}, args)
+
+	// ok: not supported
+	i = &query.InsertBase{Provider: mockProvider(false), ITable: "posts"}
+	stmts, args, err = i.Columns("title").Values([]map[string]interface{}{{"title": "a"}}).Returning([]string{"id", "title"}, &id, &title).String()
+	asserts.NoError(err)
+	asserts.Equal([]string{"INSERT INTO posts(title) VALUES ($1)"}, stmts)
+	asserts.Equal([][]interface{}{{"a"}}, args)
+
+	// error: more than one value set
+	i = &query.InsertBase{Provider: mockProvider(true), ITable: "posts"}
+	res, err := i.Columns("title").Values([]map[string]interface{}{{"title": "a"}, {"title": "b"}}).Returning([]string{"id"}, &id).Exec()
+	asserts.Error(err)
+	asserts.Equal(fmt.Sprintf(query.ErrReturning, "tests.posts"), err.Error())
+	asserts.Nil(res)
+
+	// error: dest does not match
+	i = &query.InsertBase{Provider: mockProvider(true), ITable: "posts"}
+	res, err = i.Columns("title").Values([]map[string]interface{}{{"title": "a"}}).Returning([]string{"id", "title"}, &id).Exec()
+	asserts.Error(err)
+	asserts.Equal(fmt.Sprintf(query.ErrReturning, "tests.posts"), err.Error())
+	asserts.Nil(res)
+}
+
+// result is a helper to mock the sql.Result.
+type result struct {
+	id int64
+}
+
+func (r result) LastInsertId() (int64, error) { return r.id, nil }
+func (r result) RowsAffected() (int64, error) { return 0, nil }
+
+// TestInsertBase_LastInsertedIDs tests:
+// - ids are set consecutively by the first id of each batch.
+// - error if an id is not a ptr.
+func TestInsertBase_LastInsertedIDs(t *testing.T) {
+	asserts := assert.New(t)
+
+	provider := new(mocks.Provider)
+	provider.On("QuoteIdentifier", mock.Anything).Return(func(s ...string) string { return s[0] })
+	provider.On("Placeholder").Return(condition.Placeholder{Char: "?"})
+	provider.On("Config").Return(query.Config{Database: "tests"})
+	provider.On("Exec", mock.Anything, mock.Anything).Return([]sql.Result{result{id: 10}, result{id: 20}}, nil)
+
+	// ok
+	var a, b, c int
+	i := &query.InsertBase{Provider: provider, ITable: "posts"}
+	res, err := i.Columns("title").Values([]map[string]interface{}{{"title": "a"}, {"title": "b"}, {"title": "c"}}).Batch(2).LastInsertedIDs(&a, &b, &c).Exec()
+	asserts.NoError(err)
+	asserts.Equal(2, len(res))
+	asserts.Equal(10, a)
+	asserts.Equal(11, b)
+	asserts.Equal(20, c)
+
+	// error: no ptr
+	i = &query.InsertBase{Provider: provider, ITable: "posts"}
+	res, err = i.Columns("title").Values([]map[string]interface{}{{"title": "a"}}).LastInsertedIDs(a).Exec()
+	asserts.Error(err)
+	asserts.Equal(query.ErrLastID, err)
+	asserts.Nil(res)
+}
@@ -5,6 +5,7 @@
 package query
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/patrickascher/gofer/logger"
@@ -14,9 +15,17 @@ import (
 // Builder interface.
 type Builder interface {
 	SetLogger(logger.Manager)
+	SetSQLRewriter(SQLRewriter)
+	Use(...QueryInterceptor)
 	Query(...Tx) Query
 	Config() Config
 	QuoteIdentifier(string) string
+	ReadOnly() Builder
+	Capabilities() Capabilities
+	ExecScript(statements []string) error
+	QueryRows(stmt string, args ...interface{}) (RowScanner, error)
+	DB() *sql.DB
+	Stats() sql.DBStats
 }
 
 // Provider interface.
@@ -27,6 +36,9 @@ type Provider interface {
 	QuoteIdentifier(...string) string
 	QuoteIdentifierChar() string
 	SetLogger(logger.Manager)
+	SetSQLRewriter(SQLRewriter)
+	Use(...QueryInterceptor)
+	Supports(feature Capability) bool
 	Query
 	Tx
 	Query() Query
@@ -37,6 +49,7 @@ type Provider interface {
 
 // Query interface.
 type Query interface {
+	WithContext(context.Context) Query
 	Tx() (Tx, error)
 	HasTx() bool
 	Commit() error
@@ -72,6 +85,8 @@ type Insert interface {
 	Columns(...string) Insert
 	Values([]map[string]interface{}) Insert
 	LastInsertedID(...interface{}) Insert
+	LastInsertedIDs(...interface{}) Insert
+	Returning([]string, ...interface{}) Insert
 
 	String() ([]string, [][]interface{}, error)
 	Exec() ([]sql.Result, error)
@@ -100,24 +115,32 @@ type Delete interface {
 // Select interface.
 type Select interface {
 	Columns(...string) Select
+	ColumnExpression(expr string, alias string) Select
+	Projection() []string
 	First() (*sql.Row, error)
+	Scalar(dest interface{}) error
 	All() (*sql.Rows, error)
 	String() (string, []interface{}, error)
 
 	Condition(c condition.Condition) Select
 	Join(joinType int, table string, condition string, args ...interface{}) Select
+	JoinAlias(joinType int, table string, alias string, condition string, args ...interface{}) Select
 	Where(condition string, args ...interface{}) Select
 	Group(group ...string) Select
 	Having(condition string, args ...interface{}) Select
 	Order(order ...string) Select
 	Limit(limit int) Select
 	Offset(offset int) Select
+	Lock(lock string) Select
+	IndexHint(table string, index string, kind HintKind) Select
 }
 
 // Information interface
 type Information interface {
 	Describe(columns ...string) ([]Column, error)
 	ForeignKey() ([]ForeignKey, error)
+	Tables() ([]string, error)
+	Databases() ([]string, error)
 }
 
 // Type interface
@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	sql "database/sql"
+
 	logger "github.com/patrickascher/gofer/logger"
 	query "github.com/patrickascher/gofer/query"
 	mock "github.com/stretchr/testify/mock"
@@ -13,6 +15,22 @@ type Builder struct {
 	mock.Mock
 }
 
+// Capabilities provides a mock function with given fields:
+func (_m *Builder) Capabilities() query.Capabilities {
+	ret := _m.Called()
+
+	var r0 query.Capabilities
+	if rf, ok := ret.Get(0).(func() query.Capabilities); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(query.Capabilities)
+		}
+	}
+
+	return r0
+}
+
 // Config provides a mock function with given fields:
 func (_m *Builder) Config() query.Config {
 	ret := _m.Called()
@@ -27,6 +45,76 @@ func (_m *Builder) Config() query.Config {
 	return r0
 }
 
+// DB provides a mock function with given fields:
+func (_m *Builder) DB() *sql.DB {
+	ret := _m.Called()
+
+	var r0 *sql.DB
+	if rf, ok := ret.Get(0).(func() *sql.DB); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sql.DB)
+		}
+	}
+
+	return r0
+}
+
+// ExecScript provides a mock function with given fields: statements
+func (_m *Builder) ExecScript(statements []string) error {
+	ret := _m.Called(statements)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]string) error); ok {
+		r0 = rf(statements)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// QueryRows provides a mock function with given fields: stmt, args
+func (_m *Builder) QueryRows(stmt string, args ...interface{}) (query.RowScanner, error) {
+	var _ca []interface{}
+	_ca = append(_ca, stmt)
+	_ca = append(_ca, args...)
+	ret := _m.Called(_ca...)
+
+	var r0 query.RowScanner
+	if rf, ok := ret.Get(0).(func(string, ...interface{}) query.RowScanner); ok {
+		r0 = rf(stmt, args...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(query.RowScanner)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, ...interface{}) error); ok {
+		r1 = rf(stmt, args...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Stats provides a mock function with given fields:
+func (_m *Builder) Stats() sql.DBStats {
+	ret := _m.Called()
+
+	var r0 sql.DBStats
+	if rf, ok := ret.Get(0).(func() sql.DBStats); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(sql.DBStats)
+	}
+
+	return r0
+}
+
 // Query provides a mock function with given fields: _a0
 func (_m *Builder) Query(_a0 ...query.Tx) query.Query {
 	_va := make([]interface{}, len(_a0))
@@ -63,7 +151,39 @@ func (_m *Builder) QuoteIdentifier(_a0 string) string {
 	return r0
 }
 
+// ReadOnly provides a mock function with given fields:
+func (_m *Builder) ReadOnly() query.Builder {
+	ret := _m.Called()
+
+	var r0 query.Builder
+	if rf, ok := ret.Get(0).(func() query.Builder); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(query.Builder)
+		}
+	}
+
+	return r0
+}
+
 // SetLogger provides a mock function with given fields: _a0
 func (_m *Builder) SetLogger(_a0 logger.Manager) {
 	_m.Called(_a0)
 }
+
+// SetSQLRewriter provides a mock function with given fields: _a0
+func (_m *Builder) SetSQLRewriter(_a0 query.SQLRewriter) {
+	_m.Called(_a0)
+}
+
+// Use provides a mock function with given fields: _a0
+func (_m *Builder) Use(_a0 ...query.QueryInterceptor) {
+	_va := make([]interface{}, len(_a0))
+	for _i := range _a0 {
+		_va[_i] = _a0[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	_m.Called(_ca...)
+}
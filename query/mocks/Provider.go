@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	logger "github.com/patrickascher/gofer/logger"
 	condition "github.com/patrickascher/gofer/query/condition"
 
@@ -306,6 +308,25 @@ func (_m *Provider) SetLogger(_a0 logger.Manager) {
 	_m.Called(_a0)
 }
 
+// SetSQLRewriter provides a mock function with given fields: _a0
+func (_m *Provider) SetSQLRewriter(_a0 query.SQLRewriter) {
+	_m.Called(_a0)
+}
+
+// Supports provides a mock function with given fields: feature
+func (_m *Provider) Supports(feature query.Capability) bool {
+	ret := _m.Called(feature)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(query.Capability) bool); ok {
+		r0 = rf(feature)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // Tx provides a mock function with given fields:
 func (_m *Provider) Tx() (query.Tx, error) {
 	ret := _m.Called()
@@ -344,3 +365,30 @@ func (_m *Provider) Update(_a0 string) query.Update {
 
 	return r0
 }
+
+// Use provides a mock function with given fields: _a0
+func (_m *Provider) Use(_a0 ...query.QueryInterceptor) {
+	_va := make([]interface{}, len(_a0))
+	for _i := range _a0 {
+		_va[_i] = _a0[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	_m.Called(_ca...)
+}
+
+// WithContext provides a mock function with given fields: _a0
+func (_m *Provider) WithContext(_a0 context.Context) query.Query {
+	ret := _m.Called(_a0)
+
+	var r0 query.Query
+	if rf, ok := ret.Get(0).(func(context.Context) query.Query); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(query.Query)
+		}
+	}
+
+	return r0
+}
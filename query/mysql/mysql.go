@@ -5,11 +5,14 @@
 package mysql
 
 import (
-	"database/sql"
+	"context"
+	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"strings"
 
-	_ "github.com/go-sql-driver/mysql" // mysql driver
+	driver "github.com/go-sql-driver/mysql" // mysql driver
 	"github.com/patrickascher/gofer/query"
 	"github.com/patrickascher/gofer/query/condition"
 	"github.com/patrickascher/gofer/query/types"
@@ -48,7 +51,7 @@ func newMysql(config interface{}) (query.Provider, error) {
 
 // Placeholder returns the ? placeholder for the mysql driver.
 func (m *mysql) Placeholder() condition.Placeholder {
-	return condition.Placeholder{Char: "?"}
+	return condition.Placeholder{Char: "?", NullSafeEqual: "<=>"}
 }
 
 // Config returns the query.Config.
@@ -61,6 +64,12 @@ func (m *mysql) QuoteIdentifierChar() string {
 	return "`"
 }
 
+// Supports returns false for all pre-defined capabilities.
+// Mysql has no native RETURNING, ILIKE or NULLS LAST. Window functions are only available since version 8.
+func (m *mysql) Supports(feature query.Capability) bool {
+	return false
+}
+
 // Open creates a new *sql.DB.
 func (m *mysql) Open() error {
 
@@ -68,7 +77,21 @@ func (m *mysql) Open() error {
 		m.Base.Config.Timeout = "30s"
 	}
 
-	db, err := sql.Open("mysql", fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8&parseTime=true&timeout=%s&wait_timeout=2", m.Base.Config.Username, m.Base.Config.Password, m.Base.Config.Host, m.Base.Config.Port, m.Base.Config.Database, m.Base.Config.Timeout))
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8&parseTime=true&timeout=%s&wait_timeout=2", m.Base.Config.Username, m.Base.Config.Password, m.Base.Config.Host, m.Base.Config.Port, m.Base.Config.Database, m.Base.Config.Timeout)
+	// time.Time values are scanned and bound in the configured location.
+	if m.Base.Config.Location != nil {
+		dsn += "&loc=" + url.QueryEscape(m.Base.Config.Location.String())
+	}
+	// the tls config is registered on the driver by a unique name per connection.
+	if m.Base.Config.TLS != nil {
+		name := fmt.Sprintf("gofer_%s_%d_%s", m.Base.Config.Host, m.Base.Config.Port, m.Base.Config.Database)
+		if err := driver.RegisterTLSConfig(name, m.Base.Config.TLS.Clone()); err != nil {
+			return err
+		}
+		dsn += "&tls=" + url.QueryEscape(name)
+	}
+
+	db, err := query.Open("mysql", dsn, m.Base.Config)
 	if err != nil {
 		return err
 	}
@@ -76,7 +99,23 @@ func (m *mysql) Open() error {
 	m.SetDB(db)
 
 	// call base Open function.
-	return m.Base.Open()
+	err = m.Base.Open()
+	if isTimeout(err) {
+		return query.NewTimeoutError(err)
+	}
+	return err
+}
+
+// isTimeout checks if the driver error is a connection timeout.
+func isTimeout(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
 }
 
 // Query creates a new mysql instance.
@@ -84,13 +123,29 @@ func (m *mysql) Query() query.Query {
 	// create a new instance with a new *sql.Tx.
 	// Everything else will be copied from the parent.
 	instance := mysql{}
-	instance.Base = query.Base{Config: m.Base.Config, Logger: m.Base.Logger, TransactionBase: query.TransactionBase{}}
+	instance.Base = query.Base{Config: m.Base.Config, Logger: m.Base.Logger, Rewriter: m.Base.Rewriter, Interceptors: m.Base.Interceptors, StmtCache: m.Base.StmtCache, TransactionBase: query.TransactionBase{}}
 	instance.Base.Provider = &instance // self ref for TX
 	instance.SetDB(m.Provider.DB())
 
 	return &instance
 }
 
+// LockClause returns the mysql row lock clause.
+// LOCK IN SHARE MODE is used instead of FOR SHARE, because it is also supported before version 8.
+func (m *mysql) LockClause(lock string) (string, error) {
+	if lock == query.LockForShare {
+		return "LOCK IN SHARE MODE", nil
+	}
+	return lock, nil
+}
+
+// IndexHintClause returns the mysql index hint, which is added after the table name.
+//
+//	FORCE INDEX (`idx_name`)
+func (m *mysql) IndexHintClause(hint query.IndexHint) (string, error) {
+	return string(hint.Kind) + " INDEX (" + m.QuoteIdentifier(hint.Index) + ")", nil
+}
+
 // Select will return a query.Select.
 func (m *mysql) Select(table string) query.Select {
 	return &query.SelectBase{STable: table, Provider: m}
@@ -202,6 +257,54 @@ func (i *information) ForeignKey() ([]query.ForeignKey, error) {
 	return fKeys, nil
 }
 
+// Tables will return all table names of the configured database.
+// The table of the information is ignored.
+func (i *information) Tables() ([]string, error) {
+	sel := i.mysql.Query().Select("information_schema.TABLES").
+		Columns("TABLE_NAME").
+		Where("TABLE_SCHEMA = ?", i.mysql.Provider.Config().Database).
+		Order("TABLE_NAME")
+	return scanStrings(sel)
+}
+
+// Databases will return all database names of the server.
+// The table of the information is ignored.
+func (i *information) Databases() ([]string, error) {
+	sel := i.mysql.Query().Select("information_schema.SCHEMATA").
+		Columns("SCHEMA_NAME").
+		Order("SCHEMA_NAME")
+	return scanStrings(sel)
+}
+
+// scanStrings is a helper to scan the first column of the select into a string slice.
+func scanStrings(sel query.Select) ([]string, error) {
+	rows, err := sel.All()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rv []string
+	for rows.Next() {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			return nil, err
+		}
+		rv = append(rv, s)
+	}
+	return rv, rows.Err()
+}
+
+// ColumnTypeMapping maps the driver column type name (e.g. "UNSIGNED INT") to a query type.
+// The describe type mapping is used.
+func (m *mysql) ColumnTypeMapping(databaseTypeName string) query.Type {
+	raw := strings.ToLower(databaseTypeName)
+	if strings.HasPrefix(raw, "unsigned ") {
+		raw = strings.TrimPrefix(raw, "unsigned ") + " unsigned"
+	}
+	return (&information{mysql: m}).TypeMapping(raw, query.Column{})
+}
+
 // TypeMapping converts the database type to an unique types.Interface over different database drives.
 func (i *information) TypeMapping(raw string, col query.Column) types.Interface {
 
@@ -288,6 +391,12 @@ func (i *information) TypeMapping(raw string, col query.Column) types.Interface
 		return f
 	}
 
+	// UUID
+	// binary(16) is not mapped, because the generated uuid is a 36 char string.
+	if raw == "char(36)" {
+		return types.NewUUID(raw)
+	}
+
 	// Text
 	if strings.HasPrefix(raw, "varchar") ||
 		strings.HasPrefix(raw, "char") {
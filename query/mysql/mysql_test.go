@@ -5,11 +5,17 @@
 package mysql
 
 import (
+	"context"
+	"crypto/tls"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/guregu/null"
+	loggerPkg "github.com/patrickascher/gofer/logger"
 	"github.com/patrickascher/gofer/logger/mocks"
 	"github.com/patrickascher/gofer/query"
 	"github.com/patrickascher/gofer/query/condition"
@@ -48,7 +54,7 @@ func createTable(b query.Builder, asserts *assert.Assertions) {
 	_, err := b.Query().DB().Exec("DROP TABLE IF EXISTS `query`")
 	asserts.NoError(err)
 
-	_, err = b.Query().DB().Exec("CREATE TABLE `query` (\n`id` int(11) unsigned NOT NULL AUTO_INCREMENT,\n`int` int(11) DEFAULT NULL,\n`varchar` varchar(250) DEFAULT NULL,\n`tinyint` tinyint(4) DEFAULT '4',\n`smallint` smallint(6) DEFAULT NULL,\n`mediumint` mediumint(9) DEFAULT NULL,\n`bigint` bigint(20) DEFAULT NULL,\n`float` float DEFAULT NULL,\n`double` double DEFAULT NULL,\n`char` char(1) DEFAULT NULL,\n`tinytext` tinytext,\n`text` text,\n`mediumtext` mediumtext,\n`longtext` longtext,\n`enum` enum('JOHN','DOE') DEFAULT NULL,\n`set` set('FOO','BAR') DEFAULT NULL,\n`date` date DEFAULT NULL,\n`datetime` datetime DEFAULT NULL,\n`timestamp` timestamp NULL DEFAULT NULL,\n`bool` tinyint(1) DEFAULT NULL,`utinyint` tinyint(3) unsigned DEFAULT NULL,\n  `usmallint` smallint(5) unsigned DEFAULT NULL,\n  `umediumint` mediumint(8) unsigned DEFAULT NULL,\n  `ubigint` bigint(20) unsigned DEFAULT NULL,`time` time DEFAULT NULL,`geometry` geometry DEFAULT NULL,\nPRIMARY KEY (`id`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8;")
+	_, err = b.Query().DB().Exec("CREATE TABLE `query` (\n`id` int(11) unsigned NOT NULL AUTO_INCREMENT,\n`int` int(11) DEFAULT NULL,\n`varchar` varchar(250) DEFAULT NULL,\n`tinyint` tinyint(4) DEFAULT '4',\n`smallint` smallint(6) DEFAULT NULL,\n`mediumint` mediumint(9) DEFAULT NULL,\n`bigint` bigint(20) DEFAULT NULL,\n`float` float DEFAULT NULL,\n`double` double DEFAULT NULL,\n`char` char(1) DEFAULT NULL,\n`tinytext` tinytext,\n`text` text,\n`mediumtext` mediumtext,\n`longtext` longtext,\n`enum` enum('JOHN','DOE') DEFAULT NULL,\n`set` set('FOO','BAR') DEFAULT NULL,\n`date` date DEFAULT NULL,\n`datetime` datetime DEFAULT NULL,\n`timestamp` timestamp NULL DEFAULT NULL,\n`bool` tinyint(1) DEFAULT NULL,`utinyint` tinyint(3) unsigned DEFAULT NULL,\n  `usmallint` smallint(5) unsigned DEFAULT NULL,\n  `umediumint` mediumint(8) unsigned DEFAULT NULL,\n  `ubigint` bigint(20) unsigned DEFAULT NULL,`time` time DEFAULT NULL,`geometry` geometry DEFAULT NULL,`uuid` char(36) DEFAULT NULL,\nPRIMARY KEY (`id`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8;")
 	asserts.NoError(err)
 
 	_, err = b.Query().DB().Exec("CREATE TABLE `query_fk` (`id` int(11) unsigned NOT NULL AUTO_INCREMENT, PRIMARY KEY (`id`), CONSTRAINT `query_fk_ibfk_1` FOREIGN KEY (`id`) REFERENCES `query` (`id`)) ENGINE=InnoDB AUTO_INCREMENT=2 DEFAULT CHARSET=utf8")
@@ -115,6 +121,69 @@ func TestBase_CommitRollback_Without_TX(t *testing.T) {
 	asserts.Equal(query.ErrNoTx.Error(), err.Error())
 }
 
+// TestMysql_Supports checks that no pre-defined capability is reported.
+func TestMysql_Supports(t *testing.T) {
+	asserts := assert.New(t)
+	mysql := mysql{}
+	asserts.False(mysql.Supports(query.CapReturning))
+	asserts.False(mysql.Supports(query.CapILike))
+	asserts.False(mysql.Supports(query.CapNullsLast))
+	asserts.False(mysql.Supports(query.CapWindowFunctions))
+}
+
+// TestMysql_Lock checks the rendered row lock clauses.
+func TestMysql_Lock(t *testing.T) {
+	asserts := assert.New(t)
+	mysql := &mysql{}
+	mysql.Base.Provider = mysql
+
+	stmt, args, err := mysql.Select("users").Where("id = ?", 1).Lock(query.LockForUpdate).String()
+	asserts.NoError(err)
+	asserts.Equal("SELECT * FROM `users` WHERE id = ? FOR UPDATE", stmt)
+	asserts.Equal([]interface{}{1}, args)
+
+	stmt, _, err = mysql.Select("users").Where("id = ?", 1).Lock(query.LockForShare).String()
+	asserts.NoError(err)
+	asserts.Equal("SELECT * FROM `users` WHERE id = ? LOCK IN SHARE MODE", stmt)
+}
+
+// TestMysql_IndexHint checks the rendered index hints.
+func TestMysql_IndexHint(t *testing.T) {
+	asserts := assert.New(t)
+	mysql := &mysql{}
+	mysql.Base.Provider = mysql
+
+	// ok
+	stmt, args, err := mysql.Select("users").IndexHint("", "idx_name", query.HintForce).Where("id = ?", 1).String()
+	asserts.NoError(err)
+	asserts.Equal("SELECT * FROM `users` FORCE INDEX (`idx_name`) WHERE id = ?", stmt)
+	asserts.Equal([]interface{}{1}, args)
+
+	stmt, _, err = mysql.Select("users").IndexHint("users", "idx_name", query.HintUse).IndexHint("users", "idx_email", query.HintIgnore).String()
+	asserts.NoError(err)
+	asserts.Equal("SELECT * FROM `users` USE INDEX (`idx_name`) IGNORE INDEX (`idx_email`)", stmt)
+
+	// error: table is not the select table.
+	_, _, err = mysql.Select("users").IndexHint("roles", "idx_name", query.HintForce).String()
+	asserts.Error(err)
+	asserts.Equal(fmt.Sprintf(query.ErrIndexHint, "roles", "users"), err.Error())
+}
+
+// TestMysql_JoinAlias checks the rendered aliased joins of the same table.
+func TestMysql_JoinAlias(t *testing.T) {
+	asserts := assert.New(t)
+	mysql := &mysql{}
+	mysql.Base.Provider = mysql
+
+	stmt, args, err := mysql.Select("users").
+		JoinAlias(condition.LEFT, "roles", "creator", "creator.id = users.created_by AND creator.active = ?", 1).
+		JoinAlias(condition.LEFT, "roles", "editor", "editor.id = users.updated_by AND editor.active = ?", 2).
+		Where("creator.name = ?", "admin").String()
+	asserts.NoError(err)
+	asserts.Equal("SELECT * FROM `users` LEFT JOIN `roles` AS `creator` ON creator.id = users.created_by AND creator.active = ? LEFT JOIN `roles` AS `editor` ON editor.id = users.updated_by AND editor.active = ? WHERE creator.name = ?", stmt)
+	asserts.Equal([]interface{}{1, 2, "admin"}, args)
+}
+
 // TestMysql_Timeout_Config checks the mysql timeout dns param.
 func TestMysql_Timeout_Config(t *testing.T) {
 	asserts := assert.New(t)
@@ -126,7 +195,7 @@ func TestMysql_Timeout_Config(t *testing.T) {
 	b, err := query.New("mysql", cfg)
 	asserts.Error(err)
 	asserts.Nil(b)
-	//asserts.True(strings.Contains(err.Error(), "timeout")) //TODO better solution to test a timeout.
+	asserts.True(errors.Is(err, query.ErrConnectionTimeout))
 }
 
 // TestMysql_PreQuery_Config checks if the config pre-queries are executed.
@@ -190,6 +259,383 @@ func TestMysql_Query(t *testing.T) {
 	asserts.Equal(tx.DB(), tx2.DB())
 }
 
+// TestMysql_SQLRewriter tests:
+// - If the rewriter is applied on selects and inserts.
+// - If the arguments are passed.
+func TestMysql_SQLRewriter(t *testing.T) {
+	asserts := assert.New(t)
+	createDatabase(asserts)
+
+	cfg := testConfig().DB
+	cfg.Database = "tests"
+	b, err := query.New("mysql", cfg)
+	asserts.NoError(err)
+	createTable(b, asserts)
+
+	var kinds []string
+	var stmts []string
+	b.SetSQLRewriter(func(kind string, stmt string, args []interface{}) (string, []interface{}) {
+		kinds = append(kinds, kind)
+		stmts = append(stmts, stmt+" /* app:grid */")
+		return stmt + " /* app:grid */", args
+	})
+
+	_, err = b.Query().Insert("query").Values([]map[string]interface{}{{"int": 1}}).Exec()
+	asserts.NoError(err)
+	row, err := b.Query().Select("query").Columns("int").Where("id > ?", 0).First()
+	asserts.NoError(err)
+	var i int
+	asserts.NoError(row.Scan(&i))
+	asserts.Equal(1, i)
+
+	asserts.Equal([]string{query.RewriteExec, query.RewriteQuery}, kinds)
+	asserts.Equal([]string{"INSERT INTO `query`(`int`) VALUES (?) /* app:grid */", "SELECT `int` FROM `query` WHERE id > ? /* app:grid */"}, stmts)
+}
+
+// TestMysql_Use tests:
+// - If the interceptors are called in registration order around the execution.
+// - If an interceptor can record the start and end of the execution.
+// - If an interceptor can modify the statement or short-circuit the execution.
+func TestMysql_Use(t *testing.T) {
+	asserts := assert.New(t)
+	createDatabase(asserts)
+
+	cfg := testConfig().DB
+	cfg.Database = "tests"
+	b, err := query.New("mysql", cfg)
+	asserts.NoError(err)
+	createTable(b, asserts)
+
+	var calls []string
+	b.Use(func(ctx context.Context, kind string, stmt string, args []interface{}, next query.QueryHandler) (interface{}, error) {
+		calls = append(calls, "trace:start:"+kind)
+		res, err := next(ctx, kind, stmt, args)
+		calls = append(calls, "trace:end:"+kind)
+		return res, err
+	}, func(ctx context.Context, kind string, stmt string, args []interface{}, next query.QueryHandler) (interface{}, error) {
+		calls = append(calls, "tenant:"+kind)
+		if kind == query.RewriteQuery {
+			stmt += " AND `int` = ?"
+			args = append(args, 2)
+		}
+		return next(ctx, kind, stmt, args)
+	})
+
+	_, err = b.Query().Insert("query").Values([]map[string]interface{}{{"int": 1}, {"int": 2}}).Exec()
+	asserts.NoError(err)
+	asserts.Equal([]string{"trace:start:exec", "tenant:exec", "trace:end:exec"}, calls)
+
+	// ok: statement was modified by the interceptor.
+	calls = nil
+	rows, err := b.Query().Select("query").Columns("int").Where("id > ?", 0).All()
+	asserts.NoError(err)
+	var ints []int
+	for rows.Next() {
+		var i int
+		asserts.NoError(rows.Scan(&i))
+		ints = append(ints, i)
+	}
+	asserts.NoError(rows.Close())
+	asserts.Equal([]int{2}, ints)
+	asserts.Equal([]string{"trace:start:query", "tenant:query", "trace:end:query"}, calls)
+
+	// ok: short-circuit
+	errShort := errors.New("short-circuit")
+	b.Use(func(ctx context.Context, kind string, stmt string, args []interface{}, next query.QueryHandler) (interface{}, error) {
+		return nil, errShort
+	})
+	_, err = b.Query().Select("query").Columns("int").First()
+	asserts.Equal(errShort, err)
+}
+
+// TestMysql_UseContext tests:
+// - If the context of the query is passed to the interceptors.
+// - If context.Background() is passed if no context is set.
+// - If an error returns when an interceptor returns an unexpected result type on First, All and Exec.
+func TestMysql_UseContext(t *testing.T) {
+	asserts := assert.New(t)
+	createDatabase(asserts)
+
+	cfg := testConfig().DB
+	cfg.Database = "tests"
+	b, err := query.New("mysql", cfg)
+	asserts.NoError(err)
+	createTable(b, asserts)
+
+	type ctxKey string
+	var tenants []interface{}
+	b.Use(func(ctx context.Context, kind string, stmt string, args []interface{}, next query.QueryHandler) (interface{}, error) {
+		tenants = append(tenants, ctx.Value(ctxKey("tenant")))
+		return next(ctx, kind, stmt, args)
+	})
+
+	// ok: context of the query.
+	ctx := context.WithValue(context.Background(), ctxKey("tenant"), "acme")
+	_, err = b.Query().WithContext(ctx).Insert("query").Values([]map[string]interface{}{{"int": 1}}).Exec()
+	asserts.NoError(err)
+	row, err := b.Query().WithContext(ctx).Select("query").Columns("int").First()
+	asserts.NoError(err)
+	var i int
+	asserts.NoError(row.Scan(&i))
+	asserts.Equal(1, i)
+
+	// ok: no context set.
+	_, err = b.Query().Select("query").Columns("int").First()
+	asserts.NoError(err)
+	asserts.Equal([]interface{}{"acme", "acme", nil}, tenants)
+
+	// error: short-circuit with a wrong result type.
+	b.Use(func(ctx context.Context, kind string, stmt string, args []interface{}, next query.QueryHandler) (interface{}, error) {
+		return "cached", nil
+	})
+	row, err = b.Query().Select("query").Columns("int").First()
+	asserts.Nil(row)
+	asserts.Equal(fmt.Sprintf(query.ErrInterceptResult, "cached", "*sql.Row"), err.Error())
+	rows, err := b.Query().Select("query").Columns("int").All()
+	asserts.Nil(rows)
+	asserts.Equal(fmt.Sprintf(query.ErrInterceptResult, "cached", "*sql.Rows"), err.Error())
+	res, err := b.Query().Insert("query").Values([]map[string]interface{}{{"int": 2}}).Exec()
+	asserts.Nil(res)
+	asserts.Equal(fmt.Sprintf(query.ErrInterceptResult, "cached", "sql.Result"), err.Error())
+}
+
+// TestMysql_OnConnect tests if the session variable is set on every new pooled connection.
+func TestMysql_OnConnect(t *testing.T) {
+	asserts := assert.New(t)
+	createDatabase(asserts)
+
+	cfg := testConfig().DB
+	cfg.Database = "tests"
+	cfg.OnConnect = func(ctx context.Context, conn driver.Conn) error {
+		return query.ConnExec(ctx, conn, "SET time_zone = '+02:00'")
+	}
+	b, err := query.New("mysql", cfg)
+	asserts.NoError(err)
+
+	// force new connections.
+	b.Query().DB().SetMaxIdleConns(0)
+	for i := 0; i < 2; i++ {
+		var tz string
+		err = b.Query().DB().QueryRow("SELECT @@session.time_zone").Scan(&tz)
+		asserts.NoError(err)
+		asserts.Equal("+02:00", tz)
+	}
+}
+
+// TestMysql_TLS tests:
+// - if a user which requires ssl can not connect without tls.
+// - if the connection is encrypted with the tls config.
+func TestMysql_TLS(t *testing.T) {
+	asserts := assert.New(t)
+	createDatabase(asserts)
+
+	b, err := query.New("mysql", testConfig().DB)
+	asserts.NoError(err)
+	_, err = b.Query().DB().Exec("DROP USER IF EXISTS 'tls'@'%'")
+	asserts.NoError(err)
+	_, err = b.Query().DB().Exec("CREATE USER 'tls'@'%' IDENTIFIED BY 'tls' REQUIRE SSL")
+	asserts.NoError(err)
+
+	// error: plaintext connection is rejected.
+	cfg := testConfig().DB
+	cfg.Username = "tls"
+	cfg.Password = "tls"
+	b, err = query.New("mysql", cfg)
+	asserts.Error(err)
+	asserts.Nil(b)
+
+	// ok: encrypted connection.
+	cfg.TLS = &tls.Config{InsecureSkipVerify: true}
+	b, err = query.New("mysql", cfg)
+	asserts.NoError(err)
+	var name, cipher string
+	err = b.Query().DB().QueryRow("SHOW SESSION STATUS LIKE 'Ssl_cipher'").Scan(&name, &cipher)
+	asserts.NoError(err)
+	asserts.NotEmpty(cipher)
+}
+
+// TestMysql_Location tests if a datetime written and read back preserves the wall-clock value in the configured location.
+func TestMysql_Location(t *testing.T) {
+	asserts := assert.New(t)
+	createDatabase(asserts)
+
+	loc, err := time.LoadLocation("America/New_York")
+	asserts.NoError(err)
+
+	cfg := testConfig().DB
+	cfg.Database = "tests"
+	cfg.Location = loc
+	b, err := query.New("mysql", cfg)
+	asserts.NoError(err)
+
+	_, err = b.Query().DB().Exec("DROP TABLE IF EXISTS `location`")
+	asserts.NoError(err)
+	_, err = b.Query().DB().Exec("CREATE TABLE `location` (`id` int(11) unsigned NOT NULL AUTO_INCREMENT, `at` datetime DEFAULT NULL, PRIMARY KEY (`id`))")
+	asserts.NoError(err)
+
+	at := time.Date(2021, 6, 1, 10, 30, 0, 0, loc)
+	_, err = b.Query().Insert("location").Values([]map[string]interface{}{{"at": at}}).Exec()
+	asserts.NoError(err)
+
+	var rv time.Time
+	row, err := b.Query().Select("location").Columns("at").First()
+	asserts.NoError(err)
+	asserts.NoError(row.Scan(&rv))
+	asserts.Equal(loc, rv.Location())
+	asserts.True(at.Equal(rv))
+	asserts.Equal("2021-06-01 10:30:00", rv.Format("2006-01-02 15:04:05"))
+
+	// ok: the wall-clock value is stored.
+	var raw string
+	err = b.Query().DB().QueryRow("SELECT CAST(`at` AS CHAR) FROM `location`").Scan(&raw)
+	asserts.NoError(err)
+	asserts.Equal("2021-06-01 10:30:00", raw)
+}
+
+// TestMysql_Scalar tests:
+// - if a single value is scanned.
+// - if an error returns if more than one column is selected.
+func TestMysql_Scalar(t *testing.T) {
+	asserts := assert.New(t)
+	createDatabase(asserts)
+
+	cfg := testConfig().DB
+	cfg.Database = "tests"
+	b, err := query.New("mysql", cfg)
+	asserts.NoError(err)
+	createTable(b, asserts)
+
+	_, err = b.Query().Insert("query").Values([]map[string]interface{}{{"int": 1}, {"int": 2}, {"int": 3}}).Exec()
+	asserts.NoError(err)
+
+	// ok
+	var maxID int
+	err = b.Query().Select("query").ColumnExpression("MAX(id)", "m").Scalar(&maxID)
+	asserts.NoError(err)
+	asserts.Equal(3, maxID)
+
+	// error: more than one column
+	err = b.Query().Select("query").Columns("id", "int").Scalar(&maxID)
+	asserts.Error(err)
+	asserts.Equal(fmt.Sprintf(query.ErrScalar, "query"), err.Error())
+}
+
+// TestMysql_Stats tests:
+// - if the raw *sql.DB is returned.
+// - if the pool statistics reflect the open and idle connections after queries.
+func TestMysql_Stats(t *testing.T) {
+	asserts := assert.New(t)
+	createDatabase(asserts)
+
+	cfg := testConfig().DB
+	cfg.Database = "tests"
+	b, err := query.New("mysql", cfg)
+	asserts.NoError(err)
+	createTable(b, asserts)
+
+	// ok: same db as the query.
+	asserts.Equal(b.Query().DB(), b.DB())
+
+	// ok: connections are open and idle after the queries.
+	var count int
+	err = b.Query().Select("query").ColumnExpression("COUNT(*)", "c").Scalar(&count)
+	asserts.NoError(err)
+	stats := b.Stats()
+	asserts.True(stats.OpenConnections > 0)
+	asserts.Equal(stats.OpenConnections, stats.Idle+stats.InUse)
+	asserts.True(stats.Idle > 0)
+}
+
+// TestMysql_ExecScript tests:
+// - if the statements run sequentially.
+// - if the index of the failed statement is returned and the previous statements are not rolled back (no transactional DDL).
+func TestMysql_ExecScript(t *testing.T) {
+	asserts := assert.New(t)
+	createDatabase(asserts)
+
+	cfg := testConfig().DB
+	cfg.Database = "tests"
+	b, err := query.New("mysql", cfg)
+	asserts.NoError(err)
+	asserts.False(b.Capabilities().Supports(query.CapTransactionalDDL))
+
+	// ok
+	err = b.ExecScript([]string{
+		"DROP TABLE IF EXISTS `script`",
+		"CREATE TABLE `script` (`id` int(11) unsigned NOT NULL AUTO_INCREMENT, PRIMARY KEY (`id`))",
+		"INSERT INTO `script` (`id`) VALUES (1)",
+	})
+	asserts.NoError(err)
+
+	// error - second statement fails, first statement stays.
+	err = b.ExecScript([]string{
+		"INSERT INTO `script` (`id`) VALUES (2)",
+		"INSERT INTO `not_existing` (`id`) VALUES (1)",
+		"INSERT INTO `script` (`id`) VALUES (3)",
+	})
+	asserts.Error(err)
+	asserts.Contains(err.Error(), "query: script statement 1 failed:")
+
+	row, err := b.Query().Select("script").Columns(query.DbExpr("COUNT(*)")).First()
+	asserts.NoError(err)
+	var count int
+	asserts.NoError(row.Scan(&count))
+	asserts.Equal(2, count)
+}
+
+// TestMysql_QueryRows tests:
+// - if a raw join is scanned into maps.
+// - if the values are converted by the type mapping (int, string, time.Time, nil).
+// - error: invalid statement.
+func TestMysql_QueryRows(t *testing.T) {
+	asserts := assert.New(t)
+	createDatabase(asserts)
+
+	cfg := testConfig().DB
+	cfg.Database = "tests"
+	b, err := query.New("mysql", cfg)
+	asserts.NoError(err)
+
+	err = b.ExecScript([]string{
+		"DROP TABLE IF EXISTS `rows_child`",
+		"DROP TABLE IF EXISTS `rows_parent`",
+		"CREATE TABLE `rows_parent` (`id` int(11) unsigned NOT NULL AUTO_INCREMENT, `name` varchar(250) DEFAULT NULL, `birthday` date DEFAULT NULL, PRIMARY KEY (`id`))",
+		"CREATE TABLE `rows_child` (`id` int(11) NOT NULL AUTO_INCREMENT, `parent_id` int(11) unsigned NOT NULL, `created_at` datetime DEFAULT NULL, PRIMARY KEY (`id`))",
+		"INSERT INTO `rows_parent` (`id`, `name`, `birthday`) VALUES (1, 'John', '1980-02-03')",
+		"INSERT INTO `rows_child` (`id`, `parent_id`, `created_at`) VALUES (10, 1, '2021-04-05 06:07:08'), (11, 1, NULL)",
+	})
+	asserts.NoError(err)
+
+	// ok
+	rows, err := b.QueryRows("SELECT p.id, p.name, p.birthday, c.id AS child_id, c.created_at FROM rows_parent p JOIN rows_child c ON c.parent_id = p.id WHERE p.id = ? ORDER BY c.id", 1)
+	asserts.NoError(err)
+
+	var result []map[string]interface{}
+	for rows.Next() {
+		row, err := rows.Scan()
+		asserts.NoError(err)
+		result = append(result, row)
+	}
+	asserts.NoError(rows.Err())
+	asserts.NoError(rows.Close())
+
+	asserts.Equal(2, len(result))
+	asserts.Equal(1, result[0]["id"])
+	asserts.Equal("John", result[0]["name"])
+	asserts.Equal(10, result[0]["child_id"])
+	asserts.IsType(time.Time{}, result[0]["birthday"])
+	asserts.Equal("1980-02-03", result[0]["birthday"].(time.Time).Format("2006-01-02"))
+	asserts.IsType(time.Time{}, result[0]["created_at"])
+	asserts.Equal("2021-04-05 06:07:08", result[0]["created_at"].(time.Time).Format("2006-01-02 15:04:05"))
+	asserts.Equal(11, result[1]["child_id"])
+	asserts.Nil(result[1]["created_at"])
+
+	// error: invalid statement
+	rows, err = b.QueryRows("SELECT * FROM not_existing")
+	asserts.Error(err)
+	asserts.Nil(rows)
+}
+
 // TestMysql tests:
 // - logger
 // - insert
@@ -217,30 +663,46 @@ func TestMysql(t *testing.T) {
 	testDelete(b, asserts)
 	testInformationDescribe(b, t, asserts)
 	testInformationForeignKey(b, asserts)
+	testInformationTables(b, asserts)
 }
 
 // testLogger tests:
 // - if the logger is called on First
 // - if the logger is called on All
 // - if the logger is called on Exec
+// - if the structured fields are added, the table is passed by the builder.
 func testLogger(b query.Builder, t *testing.T) {
 	logger := new(mocks.Manager)
 	b.SetLogger(logger)
 
 	logger.On("WithTimer").Once().Return(logger)
+	logger.On("WithFields", loggerPkg.Fields{"args": 0, "operation": "SELECT", "table": "query"}).Once().Return(logger)
 	logger.On("Debug", "SELECT `*` FROM `query`").Once().Return()
 	_, _ = b.Query().Select("query").First()
 
 	logger.On("WithTimer").Once().Return(logger)
+	logger.On("WithFields", loggerPkg.Fields{"args": 0, "operation": "SELECT", "table": "query"}).Once().Return(logger)
 	logger.On("Debug", "SELECT `*` FROM `query`").Once().Return()
 	_, _ = b.Query().Select("query").All()
 
 	logger.On("WithTimer").Once().Return(logger)
+	logger.On("WithFields", loggerPkg.Fields{"args": 2, "operation": "UPDATE", "table": "query", "rows": int64(0)}).Once().Return(logger)
+	logger.On("Debug", "UPDATE `query` SET `varchar` = ? WHERE id = ?").Once().Return()
+	_, _ = b.Query().Update("query").Set(map[string]interface{}{"varchar": "John"}).Where("id = ?", 1).Exec()
+
+	logger.On("WithTimer").Once().Return(logger)
+	logger.On("WithFields", loggerPkg.Fields{"args": 0, "operation": "DELETE", "table": "query", "rows": int64(0)}).Once().Return(logger)
 	logger.On("Debug", "DELETE FROM `query`").Once().Return()
 	_, _ = b.Query().Delete("query").Exec()
 
+	logger.On("WithTimer").Once().Return(logger)
+	logger.On("WithFields", loggerPkg.Fields{"args": 1, "operation": "INSERT", "table": "query", "rows": int64(1)}).Once().Return(logger)
+	logger.On("Debug", "INSERT INTO `query`(`int`) VALUES (?)").Once().Return()
+	_, _ = b.Query().Insert("query").Values([]map[string]interface{}{{"int": 1}}).Exec()
+
 	b.SetLogger(nil)
 	logger.AssertExpectations(t)
+	_, _ = b.Query().Delete("query").Exec()
 }
 
 // testInsert tests:
@@ -904,7 +1366,7 @@ func testInformationDescribe(b query.Builder, t *testing.T, asserts *assert.Asse
 	// ok: getting all types
 	cols, err = b.Query().Information("query").Describe()
 	asserts.NoError(err)
-	asserts.Equal(26, len(cols))
+	asserts.Equal(27, len(cols))
 	var tests = []struct {
 		Table         string
 		TypeKind      string
@@ -945,6 +1407,7 @@ func testInformationDescribe(b query.Builder, t *testing.T, asserts *assert.Asse
 		{Table: "query", Name: "ubigint", TypeKind: "Integer", TypeRaw: "bigint(20) unsigned", Position: 24, NullAble: true, PrimaryKey: false, Unique: false, Autoincrement: false},
 		{Table: "query", Name: "time", TypeKind: "Time", TypeRaw: "time", Position: 25, NullAble: true, PrimaryKey: false, Unique: false, Autoincrement: false},
 		{Table: "query", Name: "geometry", TypeKind: "", TypeRaw: "", Position: 26, NullAble: true, PrimaryKey: false, Unique: false, Autoincrement: false},
+		{Table: "query", Name: "uuid", TypeKind: "UUID", TypeRaw: "char(36)", Position: 27, NullAble: true, PrimaryKey: false, Unique: false, Length: query.NewNullInt(36, true), Autoincrement: false},
 	}
 
 	for i, test := range tests {
@@ -977,6 +1440,20 @@ func testInformationDescribe(b query.Builder, t *testing.T, asserts *assert.Asse
 	}
 }
 
+// testInformationTables tests:
+// - if all tables of the database are listed.
+// - if the test database is listed.
+func testInformationTables(b query.Builder, asserts *assert.Assertions) {
+	tables, err := b.Query().Information("").Tables()
+	asserts.NoError(err)
+	asserts.Contains(tables, "query")
+	asserts.Contains(tables, "query_fk")
+
+	databases, err := b.Query().Information("").Databases()
+	asserts.NoError(err)
+	asserts.Contains(databases, "tests")
+}
+
 // testInformationForeignKey tests:
 // - error: relation does not exist
 // - error: table does not exist
@@ -26,12 +26,16 @@ const (
 	ORACLEDATE = "ORACLEDATE"
 	MYSQLDATE  = "MYSQLDATE"
 	SANITIZE   = "SANITIZE"
+
+	// JSONCONTAINS and ANY require the CapJSONContains capability.
+	JSONCONTAINS = "@> ?"
+	ANY          = "= ANY"
 )
 
 // IsOperatorAllowed will return false if the operator is not implemented.
 func IsOperatorAllowed(s string) bool {
 	switch s {
-	case EQ, NEQ, NULL, NOTNULL, GT, GTE, LT, LTE, LIKE, NOTLIKE, IN, NOTIN, RIN, RNOTIN, CUSTOM, CUSTOMLIKE, SANITIZE, ORACLEDATE, MYSQLDATE:
+	case EQ, NEQ, NULL, NOTNULL, GT, GTE, LT, LTE, LIKE, NOTLIKE, IN, NOTIN, RIN, RNOTIN, CUSTOM, CUSTOMLIKE, SANITIZE, ORACLEDATE, MYSQLDATE, JSONCONTAINS, ANY:
 		return true
 	default:
 		return false
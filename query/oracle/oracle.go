@@ -5,9 +5,13 @@
 package oracle
 
 import (
-	"database/sql"
+	"context"
+	"database/sql/driver"
 	"errors"
 	"fmt"
+	"regexp"
+	"time"
+
 	"github.com/patrickascher/gofer/query"
 	"github.com/patrickascher/gofer/query/condition"
 	"github.com/patrickascher/gofer/query/types"
@@ -18,8 +22,13 @@ import (
 var (
 	ErrTableDoesNotExist = "oracle: table %s or column does not exist %s"
 	ErrTableRelation     = "oracle: table %s or relation does not exist"
+	ErrTLS               = "oracle: tls config is not supported by the driver"
+	ErrTimeZone          = "oracle: time zone %s is not supported, use an IANA name or a fixed offset"
 )
 
+// regionName is the allowed format of an IANA time zone name (Europe/Vienna, America/Argentina/Buenos_Aires, Etc/GMT+2).
+var regionName = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_+\-]*(/[A-Za-z0-9_+\-]+)*$`)
+
 type oracle struct {
 	query.Base
 
@@ -60,6 +69,16 @@ func (m *oracle) QuoteIdentifierChar() string {
 	return ""
 }
 
+// Supports returns true for NULLS LAST and window functions.
+// RETURNING is only available as RETURNING INTO with output binds and is therefore not reported.
+func (m *oracle) Supports(feature query.Capability) bool {
+	switch feature {
+	case query.CapNullsLast, query.CapWindowFunctions:
+		return true
+	}
+	return false
+}
+
 // Open creates a new *sql.DB.
 func (m *oracle) Open() error {
 
@@ -67,7 +86,31 @@ func (m *oracle) Open() error {
 		m.Base.Config.Timeout = "30s"
 	}
 
-	db, err := sql.Open("ora", fmt.Sprintf("%s/%s@%s:%d/%s", m.Base.Config.Username, m.Base.Config.Password, m.Base.Config.Host, m.Base.Config.Port, m.Base.Config.Database))
+	// the driver has no tls option, a plaintext connection is not silently used.
+	if m.Base.Config.TLS != nil {
+		return errors.New(ErrTLS)
+	}
+
+	// the session time zone is set on every new connection.
+	cfg := m.Base.Config
+	if cfg.Location != nil {
+		tz, err := sessionTimeZone(cfg.Location)
+		if err != nil {
+			return err
+		}
+		onConnect := cfg.OnConnect
+		cfg.OnConnect = func(ctx context.Context, conn driver.Conn) error {
+			if err := query.ConnExec(ctx, conn, fmt.Sprintf("ALTER SESSION SET TIME_ZONE = '%s'", tz)); err != nil {
+				return err
+			}
+			if onConnect != nil {
+				return onConnect(ctx, conn)
+			}
+			return nil
+		}
+	}
+
+	db, err := query.Open("ora", fmt.Sprintf("%s/%s@%s:%d/%s", m.Base.Config.Username, m.Base.Config.Password, m.Base.Config.Host, m.Base.Config.Port, m.Base.Config.Database), cfg)
 	if err != nil {
 		return err
 	}
@@ -78,18 +121,56 @@ func (m *oracle) Open() error {
 	return m.Base.Open()
 }
 
+// sessionTimeZone returns the oracle session time zone of the location.
+// The ALTER SESSION statement can not bind arguments, therefore only offsets and IANA names are returned.
+// Locations with the same offset all over the year are resolved to the offset (+01:00), others to their IANA name.
+// time.Local is resolved to the actual offset, a daylight saving time change is not taken into account on open connections.
+// Error will return if the location has no fixed offset and its name is no IANA name.
+func sessionTimeZone(loc *time.Location) (string, error) {
+	now := time.Now().In(loc)
+	_, offset := now.Zone()
+	_, winter := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, loc).Zone()
+	_, summer := time.Date(now.Year(), time.July, 1, 0, 0, 0, 0, loc).Zone()
+
+	if loc != time.Local && winter != summer {
+		if !regionName.MatchString(loc.String()) {
+			return "", fmt.Errorf(ErrTimeZone, loc.String())
+		}
+		if _, err := time.LoadLocation(loc.String()); err != nil {
+			return "", fmt.Errorf(ErrTimeZone, loc.String())
+		}
+		return loc.String(), nil
+	}
+
+	sign := "+"
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+	return fmt.Sprintf("%s%02d:%02d", sign, offset/3600, offset%3600/60), nil
+}
+
 // Query creates a new mysql instance.
 func (m *oracle) Query() query.Query {
 	// create a new instance with a new *sql.Tx.
 	// Everything else will be copied from the parent.
 	instance := oracle{}
-	instance.Base = query.Base{Config: m.Base.Config, Logger: m.Base.Logger, TransactionBase: query.TransactionBase{}}
+	instance.Base = query.Base{Config: m.Base.Config, Logger: m.Base.Logger, Rewriter: m.Base.Rewriter, Interceptors: m.Base.Interceptors, StmtCache: m.Base.StmtCache, TransactionBase: query.TransactionBase{}}
 	instance.Base.Provider = &instance // self ref for TX
 	instance.SetDB(m.Provider.DB())
 
 	return &instance
 }
 
+// LockClause returns the oracle row lock clause.
+// Oracle has no shared row lock, an error will return.
+func (m *oracle) LockClause(lock string) (string, error) {
+	if lock == query.LockForShare {
+		return "", fmt.Errorf(query.ErrLock, lock)
+	}
+	return lock, nil
+}
+
 // Select will return a query.Select.
 func (m *oracle) Select(table string) query.Select {
 	return &query.SelectBase{STable: table, Provider: m}
@@ -182,6 +263,43 @@ func (i *information) ForeignKey() ([]query.ForeignKey, error) {
 	return nil, errors.New("oracle: foreign keys are not implemented yet")
 }
 
+// Tables will return all table names of the current user.
+// The table of the information is ignored.
+func (i *information) Tables() ([]string, error) {
+	return scanStrings(i.oracle.Query().Select("USER_TABLES").Columns("TABLE_NAME").Order("TABLE_NAME"))
+}
+
+// Databases will return all schema names (users) of the database.
+// The table of the information is ignored.
+func (i *information) Databases() ([]string, error) {
+	return scanStrings(i.oracle.Query().Select("ALL_USERS").Columns("USERNAME").Order("USERNAME"))
+}
+
+// scanStrings is a helper to scan the first column of the select into a string slice.
+func scanStrings(sel query.Select) ([]string, error) {
+	rows, err := sel.All()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rv []string
+	for rows.Next() {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			return nil, err
+		}
+		rv = append(rv, s)
+	}
+	return rv, rows.Err()
+}
+
+// ColumnTypeMapping maps the driver column type name to a query type.
+// The describe type mapping is used.
+func (m *oracle) ColumnTypeMapping(databaseTypeName string) query.Type {
+	return (&information{oracle: m}).TypeMapping(databaseTypeName, query.Column{})
+}
+
 // TypeMapping converts the database type to an unique sqlquery type over different database drives.
 func (i *information) TypeMapping(raw string, col query.Column) types.Interface {
 	//TODO oracle types
@@ -15,6 +15,7 @@ import (
 	"github.com/patrickascher/gofer/query/condition"
 	"github.com/patrickascher/gofer/query/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 type Config struct {
@@ -228,14 +229,17 @@ func testLogger(b query.Builder, t *testing.T) {
 	b.SetLogger(logger)
 
 	logger.On("WithTimer").Once().Return(logger)
+	logger.On("WithFields", mock.Anything).Once().Return(logger)
 	logger.On("Debug", "SELECT `*` FROM `query`").Once().Return()
 	_, _ = b.Query().Select("query").First()
 
 	logger.On("WithTimer").Once().Return(logger)
+	logger.On("WithFields", mock.Anything).Once().Return(logger)
 	logger.On("Debug", "SELECT `*` FROM `query`").Once().Return()
 	_, _ = b.Query().Select("query").All()
 
 	logger.On("WithTimer").Once().Return(logger)
+	logger.On("WithFields", mock.Anything).Once().Return(logger)
 	logger.On("Debug", "DELETE FROM `query`").Once().Return()
 	_, _ = b.Query().Delete("query").Exec()
 
@@ -0,0 +1,77 @@
+// Copyright (c) 2021 Patrick Ascher <development@fullhouse-productions.com>. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package query
+
+import (
+	"database/sql"
+	"sync"
+)
+
+// StmtCache is caching the prepared statements by the rendered sql.
+// The sql.Stmt is connection independent, the database/sql package is re-preparing it on a new connection if needed.
+type StmtCache struct {
+	mu       sync.Mutex
+	stmts    map[string]*sql.Stmt
+	prepared int
+}
+
+// NewStmtCache creates a new prepared statement cache.
+func NewStmtCache() *StmtCache {
+	return &StmtCache{stmts: make(map[string]*sql.Stmt)}
+}
+
+// Stmt will return the cached prepared statement.
+// If it does not exist yet, the statement will be prepared and cached.
+func (c *StmtCache) Stmt(db *sql.DB, query string) (*sql.Stmt, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if stmt, ok := c.stmts[query]; ok {
+		return stmt, nil
+	}
+
+	stmt, err := db.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	c.prepared++
+	c.stmts[query] = stmt
+
+	return stmt, nil
+}
+
+// Evict will close and remove the prepared statement of the cache.
+// It is used if a statement got invalid, it will be prepared again on the next request.
+func (c *StmtCache) Evict(query string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if stmt, ok := c.stmts[query]; ok {
+		_ = stmt.Close()
+		delete(c.stmts, query)
+	}
+}
+
+// Prepared will return the number of prepared statements.
+func (c *StmtCache) Prepared() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.prepared
+}
+
+// Close will close all cached statements.
+func (c *StmtCache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var err error
+	for query, stmt := range c.stmts {
+		if cErr := stmt.Close(); cErr != nil && err == nil {
+			err = cErr
+		}
+		delete(c.stmts, query)
+	}
+	return err
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2021 Patrick Ascher <development@fullhouse-productions.com>. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package query_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/patrickascher/gofer/query"
+	"github.com/patrickascher/gofer/query/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+// countDriver is a fake sql driver which counts the prepared statements.
+type countDriver struct {
+	prepared int
+	fail     bool
+}
+type countConn struct{ d *countDriver }
+type countStmt struct{ d *countDriver }
+type countRows struct{}
+
+func (d *countDriver) Open(string) (driver.Conn, error) { return &countConn{d: d}, nil }
+func (c *countConn) Prepare(string) (driver.Stmt, error) {
+	c.d.prepared++
+	return &countStmt{d: c.d}, nil
+}
+func (c *countConn) Close() error                               { return nil }
+func (c *countConn) Begin() (driver.Tx, error)                  { return nil, driver.ErrSkip }
+func (s *countStmt) Close() error                               { return nil }
+func (s *countStmt) NumInput() int                              { return -1 }
+func (s *countStmt) Exec([]driver.Value) (driver.Result, error) { return driver.RowsAffected(1), nil }
+func (s *countStmt) Query([]driver.Value) (driver.Rows, error) {
+	if s.d.fail {
+		return nil, errors.New("query failed")
+	}
+	return &countRows{}, nil
+}
+func (r *countRows) Columns() []string         { return []string{} }
+func (r *countRows) Close() error              { return nil }
+func (r *countRows) Next([]driver.Value) error { return io.EOF }
+
+// TestStmtCache tests:
+// - if the same statement is only prepared once.
+// - if an evicted statement gets prepared again.
+// - if the statement gets evicted on a First error.
+func TestStmtCache(t *testing.T) {
+	asserts := assert.New(t)
+
+	d := &countDriver{}
+	sql.Register("query_count", d)
+	db, err := sql.Open("query_count", "")
+	asserts.NoError(err)
+
+	provider := new(mocks.Provider)
+	provider.On("DB").Return(db)
+	b := query.Base{Config: query.Config{PrepareCache: true, MaxIdleConnections: 1}, Provider: provider}
+	b.SetDB(db)
+	err = b.Open()
+	asserts.NoError(err)
+	asserts.NotNil(b.StmtCache)
+
+	// ok: prepared once.
+	_, err = b.Exec([]string{"UPDATE test SET a = ?"}, [][]interface{}{{1}})
+	asserts.NoError(err)
+	_, err = b.Exec([]string{"UPDATE test SET a = ?"}, [][]interface{}{{2}})
+	asserts.NoError(err)
+	rows, err := b.All("SELECT a FROM test", nil)
+	asserts.NoError(err)
+	asserts.NoError(rows.Close())
+	rows, err = b.All("SELECT a FROM test", nil)
+	asserts.NoError(err)
+	asserts.NoError(rows.Close())
+	asserts.Equal(2, b.StmtCache.Prepared())
+	asserts.Equal(2, d.prepared)
+
+	// ok: evicted statement is prepared again.
+	b.StmtCache.Evict("SELECT a FROM test")
+	rows, err = b.All("SELECT a FROM test", nil)
+	asserts.NoError(err)
+	asserts.NoError(rows.Close())
+	asserts.Equal(3, b.StmtCache.Prepared())
+
+	// ok: First error evicts the statement.
+	d.fail = true
+	row, err := b.First("SELECT a FROM test", nil)
+	asserts.NoError(err)
+	asserts.Error(row.Err())
+	d.fail = false
+	row, err = b.First("SELECT a FROM test", nil)
+	asserts.NoError(err)
+	asserts.NoError(row.Err())
+	asserts.Equal(4, b.StmtCache.Prepared())
+
+	asserts.NoError(b.StmtCache.Close())
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2021 Patrick Ascher <development@fullhouse-productions.com>. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package query
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/patrickascher/gofer/query/condition"
+)
+
+// Error messages.
+var (
+	ErrReadOnly = errors.New("query: builder is read-only")
+)
+
+// readOnlyBuilder wraps a builder and rejects all write statements.
+type readOnlyBuilder struct {
+	Builder
+}
+
+// ReadOnly will return a builder which rejects Insert, Update and Delete statements with an ErrReadOnly.
+// Select and Information will work as usual.
+func (b *builder) ReadOnly() Builder {
+	return &readOnlyBuilder{Builder: b}
+}
+
+// ReadOnly will return itself.
+func (b *readOnlyBuilder) ReadOnly() Builder {
+	return b
+}
+
+// Query will return a new read-only query interface.
+func (b *readOnlyBuilder) Query(tx ...Tx) Query {
+	return &readOnlyQuery{Query: b.Builder.Query(tx...)}
+}
+
+// ExecScript will return an ErrReadOnly.
+func (b *readOnlyBuilder) ExecScript(statements []string) error {
+	return ErrReadOnly
+}
+
+// readOnlyQuery wraps a query and rejects all write statements.
+type readOnlyQuery struct {
+	Query
+}
+
+// WithContext will return a read-only query with the given context.
+func (q *readOnlyQuery) WithContext(ctx context.Context) Query {
+	return &readOnlyQuery{Query: q.Query.WithContext(ctx)}
+}
+
+// Tx will return a read-only tx.
+func (q *readOnlyQuery) Tx() (Tx, error) {
+	tx, err := q.Query.Tx()
+	if err != nil {
+		return nil, err
+	}
+	return &readOnlyQuery{Query: tx.(Query)}, nil
+}
+
+// Insert will return an insert which always returns ErrReadOnly.
+func (q *readOnlyQuery) Insert(string) Insert {
+	return &readOnlyInsert{}
+}
+
+// Update will return an update which always returns ErrReadOnly.
+func (q *readOnlyQuery) Update(string) Update {
+	return &readOnlyUpdate{}
+}
+
+// Delete will return a delete which always returns ErrReadOnly.
+func (q *readOnlyQuery) Delete(string) Delete {
+	return &readOnlyDelete{}
+}
+
+// readOnlyInsert rejects the insert statement.
+type readOnlyInsert struct{}
+
+func (i *readOnlyInsert) Batch(int) Insert                           { return i }
+func (i *readOnlyInsert) Columns(...string) Insert                   { return i }
+func (i *readOnlyInsert) Values([]map[string]interface{}) Insert     { return i }
+func (i *readOnlyInsert) LastInsertedID(...interface{}) Insert       { return i }
+func (i *readOnlyInsert) LastInsertedIDs(...interface{}) Insert      { return i }
+func (i *readOnlyInsert) Returning([]string, ...interface{}) Insert  { return i }
+func (i *readOnlyInsert) String() ([]string, [][]interface{}, error) { return nil, nil, ErrReadOnly }
+func (i *readOnlyInsert) Exec() ([]sql.Result, error)                { return nil, ErrReadOnly }
+
+// readOnlyUpdate rejects the update statement.
+type readOnlyUpdate struct{}
+
+func (u *readOnlyUpdate) Set(map[string]interface{}) Update      { return u }
+func (u *readOnlyUpdate) Columns(...string) Update               { return u }
+func (u *readOnlyUpdate) Condition(condition.Condition) Update   { return u }
+func (u *readOnlyUpdate) Where(string, ...interface{}) Update    { return u }
+func (u *readOnlyUpdate) String() (string, []interface{}, error) { return "", nil, ErrReadOnly }
+func (u *readOnlyUpdate) Exec() (sql.Result, error)              { return nil, ErrReadOnly }
+
+// readOnlyDelete rejects the delete statement.
+type readOnlyDelete struct{}
+
+func (d *readOnlyDelete) Condition(condition.Condition) Delete   { return d }
+func (d *readOnlyDelete) Where(string, ...interface{}) Delete    { return d }
+func (d *readOnlyDelete) String() (string, []interface{}, error) { return "", nil, ErrReadOnly }
+func (d *readOnlyDelete) Exec() (sql.Result, error)              { return nil, ErrReadOnly }
@@ -0,0 +1,164 @@
+// Copyright (c) 2021 Patrick Ascher <development@fullhouse-productions.com>. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package query
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/patrickascher/gofer/query/types"
+)
+
+// Error messages.
+var (
+	ErrRowConversion = "query: column %s (%s) can not be converted: %w"
+)
+
+// date layouts which are used if the driver returns dates as text.
+var dateLayouts = []string{"2006-01-02 15:04:05", "2006-01-02"}
+
+// ColumnTypeMapper can be implemented by a provider to map a driver column type name to a query type.
+// If the provider does not implement it, the raw driver values are returned.
+type ColumnTypeMapper interface {
+	ColumnTypeMapping(databaseTypeName string) Type
+}
+
+// RowScanner interface.
+type RowScanner interface {
+	Next() bool
+	Scan() (map[string]interface{}, error)
+	Err() error
+	Close() error
+}
+
+// rowScanner is the default RowScanner implementation.
+type rowScanner struct {
+	rows    *sql.Rows
+	columns []*sql.ColumnType
+	mapper  ColumnTypeMapper
+}
+
+// QueryRows executes a raw sql statement and returns a RowScanner.
+// Each row is scanned into a map[string]interface{}, the values are converted by the provider type mapping.
+// The RowScanner must be closed by the caller.
+// Error will return if the query does not implement the Provider interface.
+func (b *builder) QueryRows(stmt string, args ...interface{}) (RowScanner, error) {
+	q := b.provider.Query()
+	p, ok := q.(Provider)
+	if !ok {
+		return nil, fmt.Errorf(ErrProvider, q)
+	}
+	rows, err := p.All(stmt, args)
+	if err != nil {
+		return nil, err
+	}
+
+	columns, err := rows.ColumnTypes()
+	if err != nil {
+		_ = rows.Close()
+		return nil, err
+	}
+
+	r := &rowScanner{rows: rows, columns: columns}
+	if mapper, ok := b.provider.(ColumnTypeMapper); ok {
+		r.mapper = mapper
+	}
+	return r, nil
+}
+
+// Next prepares the next row.
+func (r *rowScanner) Next() bool {
+	return r.rows.Next()
+}
+
+// Err returns the error, if any, that was encountered during iteration.
+func (r *rowScanner) Err() error {
+	return r.rows.Err()
+}
+
+// Close the underlying rows.
+func (r *rowScanner) Close() error {
+	return r.rows.Close()
+}
+
+// Scan the current row into a map with the column names as key.
+// Error will return if the row can not be scanned or a value can not be converted.
+func (r *rowScanner) Scan() (map[string]interface{}, error) {
+	values := make([]interface{}, len(r.columns))
+	ptr := make([]interface{}, len(r.columns))
+	for i := range values {
+		ptr[i] = &values[i]
+	}
+
+	if err := r.rows.Scan(ptr...); err != nil {
+		return nil, err
+	}
+
+	row := make(map[string]interface{}, len(r.columns))
+	for i, col := range r.columns {
+		v, err := r.convert(col, values[i])
+		if err != nil {
+			return nil, fmt.Errorf(ErrRowConversion, col.Name(), col.DatabaseTypeName(), err)
+		}
+		row[col.Name()] = v
+	}
+	return row, nil
+}
+
+// convert the driver value by the kind of the provider type mapping.
+func (r *rowScanner) convert(col *sql.ColumnType, v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	kind := ""
+	if r.mapper != nil {
+		if t := r.mapper.ColumnTypeMapping(col.DatabaseTypeName()); t != nil {
+			kind = t.Kind()
+		}
+	}
+
+	switch kind {
+	case types.INTEGER:
+		switch val := v.(type) {
+		case int64:
+			return int(val), nil
+		case []byte:
+			return strconv.Atoi(string(val))
+		}
+	case types.FLOAT:
+		switch val := v.(type) {
+		case float32:
+			return float64(val), nil
+		case []byte:
+			return strconv.ParseFloat(string(val), 64)
+		}
+	case types.BOOL:
+		switch val := v.(type) {
+		case int64:
+			return val != 0, nil
+		case []byte:
+			return strconv.ParseBool(string(val))
+		}
+	case types.DATE, types.DATETIME:
+		if val, ok := v.([]byte); ok {
+			var err error
+			for _, layout := range dateLayouts {
+				var t time.Time
+				if t, err = time.Parse(layout, string(val)); err == nil {
+					return t, nil
+				}
+			}
+			return nil, err
+		}
+	}
+
+	if val, ok := v.([]byte); ok {
+		return string(val), nil
+	}
+	return v, nil
+}
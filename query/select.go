@@ -6,9 +6,53 @@ package query
 
 import (
 	"database/sql"
+	"fmt"
+	"strings"
+
 	"github.com/patrickascher/gofer/query/condition"
 )
 
+// Error messages.
+var (
+	ErrScalar        = "query: scalar requires exactly one column (%s)"
+	ErrIndexHint     = "query: index hint table %s is not the select table %s"
+	ErrIndexHintKind = "query: index hint kind %s is not supported"
+)
+
+// Row lock modes.
+const (
+	LockForUpdate = "FOR UPDATE"
+	LockForShare  = "FOR SHARE"
+)
+
+// LockClause can be implemented by a provider if the row lock clause differs from the sql standard.
+type LockClause interface {
+	LockClause(lock string) (string, error)
+}
+
+// HintKind of an index hint.
+type HintKind string
+
+// Index hint kinds.
+const (
+	HintUse    HintKind = "USE"
+	HintForce  HintKind = "FORCE"
+	HintIgnore HintKind = "IGNORE"
+)
+
+// IndexHint of a select.
+type IndexHint struct {
+	Table string
+	Index string
+	Kind  HintKind
+}
+
+// IndexHintClause can be implemented by a provider if the index hints are rendered after the table name (mysql).
+// By default, the hints are rendered as optimizer comment after SELECT.
+type IndexHintClause interface {
+	IndexHintClause(hint IndexHint) (string, error)
+}
+
 // SelectBase can be embedded and changed for different providers.
 // All functions and variables are therefore exported.
 type SelectBase struct {
@@ -17,6 +61,8 @@ type SelectBase struct {
 	STable     string
 	SColumns   []string
 	SCondition condition.Condition
+	SLock      string
+	SHints     []IndexHint
 }
 
 // Columns define a fixed column order for the insert.
@@ -27,6 +73,24 @@ func (s *SelectBase) Columns(columns ...string) Select {
 	return s
 }
 
+// ColumnExpression adds an expression with an alias to the columns.
+// The expression will not get quoted.
+//
+//	Select("users").ColumnExpression("MAX(id)","m")
+func (s *SelectBase) ColumnExpression(expr string, alias string) Select {
+	s.SColumns = append(s.SColumns, DbExpr(expr+" AS "+alias))
+	return s
+}
+
+// Projection will return the columns in the exact order they are selected.
+// It should be used to build the scan targets, to avoid a drift between the columns and the scan fields.
+func (s *SelectBase) Projection() []string {
+	if len(s.SColumns) == 0 {
+		return []string{dbExpr + "*"}
+	}
+	return s.SColumns
+}
+
 // First will return a sql.Row.
 // condition.LIMIT and condition.OFFSET will be removed - if set.
 func (s *SelectBase) First() (*sql.Row, error) {
@@ -38,9 +102,23 @@ func (s *SelectBase) First() (*sql.Row, error) {
 		return nil, err
 	}
 
+	setLogTable(s.Provider, s.STable)
 	return s.Provider.First(stmt, args)
 }
 
+// Scalar will scan the single column of the first row into the given pointer.
+// Error will return if not exactly one column is selected or no row was found (sql.ErrNoRows).
+func (s *SelectBase) Scalar(dest interface{}) error {
+	if columns := s.Projection(); len(columns) != 1 || columns[0] == DbExpr("*") {
+		return fmt.Errorf(ErrScalar, s.STable)
+	}
+	row, err := s.First()
+	if err != nil {
+		return err
+	}
+	return row.Scan(dest)
+}
+
 // All will return sql.Rows.
 func (s *SelectBase) All() (*sql.Rows, error) {
 	stmt, args, err := s.Render()
@@ -48,6 +126,7 @@ func (s *SelectBase) All() (*sql.Rows, error) {
 		return nil, err
 	}
 
+	setLogTable(s.Provider, s.STable)
 	return s.Provider.All(stmt, args)
 }
 
@@ -59,7 +138,12 @@ func (s *SelectBase) Render() (string, []interface{}, error) {
 		columns = append(columns, dbExpr+"*")
 	}
 
-	selectStmt := "SELECT " + s.Provider.QuoteIdentifier(columns...) + " FROM " + s.Provider.QuoteIdentifier(s.STable)
+	hintPrefix, hintSuffix, err := s.renderIndexHints()
+	if err != nil {
+		return "", nil, err
+	}
+
+	selectStmt := "SELECT " + hintPrefix + s.Provider.QuoteIdentifier(columns...) + " FROM " + s.Provider.QuoteIdentifier(s.STable) + hintSuffix
 	var args []interface{}
 	if s.SCondition != nil {
 		conditionStmt, arg, err := s.SCondition.Render(s.Provider.Placeholder())
@@ -70,6 +154,18 @@ func (s *SelectBase) Render() (string, []interface{}, error) {
 		args = arg
 	}
 
+	if s.SLock != "" {
+		lock := s.SLock
+		if l, ok := s.Provider.(LockClause); ok {
+			var err error
+			lock, err = l.LockClause(lock)
+			if err != nil {
+				return "", nil, err
+			}
+		}
+		selectStmt += " " + lock
+	}
+
 	return selectStmt, args, nil
 }
 
@@ -91,6 +187,13 @@ func (s *SelectBase) Join(joinType int, table string, condition string, args ...
 	return s
 }
 
+// JoinAlias - please see the condition.SetJoinAlias documentation.
+func (s *SelectBase) JoinAlias(joinType int, table string, alias string, condition string, args ...interface{}) Select {
+	s.createCondition()
+	s.SCondition.SetJoinAlias(joinType, s.Provider.QuoteIdentifier(table), s.Provider.QuoteIdentifier(alias), condition, args...)
+	return s
+}
+
 // Where - please see the condition.Where documentation.
 func (s *SelectBase) Where(condition string, args ...interface{}) Select {
 	s.createCondition()
@@ -133,9 +236,83 @@ func (s *SelectBase) Offset(offset int) Select {
 	return s
 }
 
+// Lock adds a row lock (LockForUpdate, LockForShare) to the select.
+// It should only be used inside a transaction.
+func (s *SelectBase) Lock(lock string) Select {
+	s.SLock = lock
+	return s
+}
+
+// IndexHint adds an index hint (HintUse, HintForce, HintIgnore) for the given table.
+// If the table is empty, the select table is used.
+//
+//	Select("users").IndexHint("", "idx_name", query.HintForce)
+func (s *SelectBase) IndexHint(table string, index string, kind HintKind) Select {
+	if table == "" {
+		table = s.STable
+	}
+	s.SHints = append(s.SHints, IndexHint{Table: table, Index: index, Kind: kind})
+	return s
+}
+
+// renderIndexHints is a helper to render the index hints.
+// If the provider implements IndexHintClause, the hints are added after the table (suffix), otherwise an optimizer comment is returned (prefix).
+// Error will return if the kind is unknown or the provider only supports hints on the select table.
+func (s *SelectBase) renderIndexHints() (string, string, error) {
+	if len(s.SHints) == 0 {
+		return "", "", nil
+	}
+
+	var hints []string
+	clause, ok := s.Provider.(IndexHintClause)
+	for _, hint := range s.SHints {
+		if hint.Kind != HintUse && hint.Kind != HintForce && hint.Kind != HintIgnore {
+			return "", "", fmt.Errorf(ErrIndexHintKind, hint.Kind)
+		}
+
+		if ok {
+			if hint.Table != s.STable {
+				return "", "", fmt.Errorf(ErrIndexHint, hint.Table, s.STable)
+			}
+			h, err := clause.IndexHintClause(hint)
+			if err != nil {
+				return "", "", err
+			}
+			hints = append(hints, h)
+			continue
+		}
+
+		fn := "INDEX"
+		if hint.Kind == HintIgnore {
+			fn = "NO_INDEX"
+		}
+		hints = append(hints, fn+"("+hint.Table+" "+hint.Index+")")
+	}
+
+	if ok {
+		return "", " " + strings.Join(hints, " "), nil
+	}
+	return "/*+ " + strings.Join(hints, " ") + " */ ", "", nil
+}
+
 // createCondition helper to create a condition if none was set yet.
 func (s *SelectBase) createCondition() {
 	if s.SCondition == nil {
 		s.SCondition = condition.New()
 	}
 }
+
+// ColumnName will return the result column name of a projected column.
+// Expressions, quote characters and table prefixes are removed and an alias will be returned if defined.
+// "go.users AS u" will be converted to "u", "users.name" to "name".
+func ColumnName(column string) string {
+	column = strings.TrimPrefix(column, dbExpr)
+	if fields := strings.Fields(column); len(fields) > 1 {
+		column = fields[len(fields)-1]
+	}
+	column = strings.Trim(column, "`\"")
+	if i := strings.LastIndex(column, "."); i != -1 {
+		column = column[i+1:]
+	}
+	return strings.Trim(column, "`\"")
+}
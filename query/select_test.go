@@ -0,0 +1,84 @@
+// Copyright (c) 2021 Patrick Ascher <development@fullhouse-productions.com>. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package query_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/patrickascher/gofer/query"
+	"github.com/patrickascher/gofer/query/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSelectBase_Projection tests:
+// - if * is projected if no columns are set.
+// - if the columns are projected in the exact order.
+// - if column expressions are added with the alias.
+func TestSelectBase_Projection(t *testing.T) {
+	asserts := assert.New(t)
+
+	s := &query.SelectBase{STable: "users"}
+	asserts.Equal([]string{query.DbExpr("*")}, s.Projection())
+
+	s.Columns("id", query.DbExpr("COUNT(*) AS total"), "name")
+	asserts.Equal([]string{"id", query.DbExpr("COUNT(*) AS total"), "name"}, s.Projection())
+
+	s.ColumnExpression("MAX(id)", "m")
+	asserts.Equal([]string{"id", query.DbExpr("COUNT(*) AS total"), "name", query.DbExpr("MAX(id) AS m")}, s.Projection())
+}
+
+// TestSelectBase_Scalar tests:
+// - if an error returns if not exactly one column is selected.
+func TestSelectBase_Scalar(t *testing.T) {
+	asserts := assert.New(t)
+
+	var v int
+	s := &query.SelectBase{STable: "users"}
+	err := s.Scalar(&v)
+	asserts.Error(err)
+	asserts.Equal(fmt.Sprintf(query.ErrScalar, "users"), err.Error())
+
+	s.Columns("id", "name")
+	err = s.Scalar(&v)
+	asserts.Error(err)
+}
+
+// TestSelectBase_IndexHint tests:
+// - if the hints are rendered as optimizer comment if the provider does not implement IndexHintClause.
+// - if the select table is used if no table is given.
+// - error: unknown hint kind.
+func TestSelectBase_IndexHint(t *testing.T) {
+	asserts := assert.New(t)
+
+	provider := new(mocks.Provider)
+	provider.On("QuoteIdentifier", query.DbExpr("*")).Return("*")
+	provider.On("QuoteIdentifier", "users").Return(`"users"`)
+
+	// ok
+	s := &query.SelectBase{STable: "users", Provider: provider}
+	stmt, _, err := s.IndexHint("", "idx_name", query.HintForce).IndexHint("users", "idx_email", query.HintIgnore).String()
+	asserts.NoError(err)
+	asserts.Equal(`SELECT /*+ INDEX(users idx_name) NO_INDEX(users idx_email) */ * FROM "users"`, stmt)
+
+	// error: unknown kind
+	s = &query.SelectBase{STable: "users", Provider: provider}
+	_, _, err = s.IndexHint("", "idx_name", "STRAIGHT").String()
+	asserts.Error(err)
+	asserts.Equal(fmt.Sprintf(query.ErrIndexHintKind, "STRAIGHT"), err.Error())
+}
+
+// TestColumnName tests:
+// - if the result column name is returned for columns, aliases and expressions.
+func TestColumnName(t *testing.T) {
+	asserts := assert.New(t)
+
+	asserts.Equal("id", query.ColumnName("id"))
+	asserts.Equal("name", query.ColumnName("users.name"))
+	asserts.Equal("name", query.ColumnName("`go`.`users`.`name`"))
+	asserts.Equal("u", query.ColumnName("users.name AS u"))
+	asserts.Equal("total", query.ColumnName(query.DbExpr("COUNT(*) AS total")))
+	asserts.Equal("*", query.ColumnName(query.DbExpr("*")))
+}
@@ -0,0 +1,39 @@
+// Copyright (c) 2021 Patrick Ascher <development@fullhouse-productions.com>. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package query
+
+import "database/sql/driver"
+
+// Redacted is logged instead of a sensitive value.
+const Redacted = "***"
+
+// sensitive wraps a statement argument which should not be logged.
+type sensitive struct {
+	value interface{}
+}
+
+// Sensitive marks a statement argument as sensitive.
+// The value is passed unchanged to the database but will be logged as Redacted.
+func Sensitive(value interface{}) interface{} {
+	return sensitive{value: value}
+}
+
+// Value implements the driver.Valuer interface.
+func (s sensitive) Value() (driver.Value, error) {
+	return driver.DefaultParameterConverter.ConvertValue(s.value)
+}
+
+// redact is a helper to replace all sensitive arguments by Redacted.
+func redact(args []interface{}) []interface{} {
+	values := make([]interface{}, len(args))
+	for i, arg := range args {
+		if _, ok := arg.(sensitive); ok {
+			values[i] = Redacted
+			continue
+		}
+		values[i] = arg
+	}
+	return values
+}
@@ -16,6 +16,7 @@ const (
 	DATETIME    = "DateTime"
 	SELECT      = "Select"
 	MULTISELECT = "MultiSelect"
+	UUID        = "UUID"
 )
 
 // Interface of the types to access the sanitized kind and the raw sql data.
@@ -84,6 +85,12 @@ func NewMultiSelect(raw string) *Select {
 	return &Select{common: common{name: MULTISELECT, raw: raw}}
 }
 
+// NewUUID returns a ptr to a UUID.
+// It also defines the name and raw.
+func NewUUID(raw string) *UUIDType {
+	return &UUIDType{common: common{name: UUID, raw: raw}}
+}
+
 type common struct {
 	raw  string
 	name string
@@ -137,6 +144,11 @@ type DateTime struct {
 	common
 }
 
+// UUIDType represents sql uuid columns.
+type UUIDType struct {
+	common
+}
+
 // Float represents all kind of sql floats
 type Float struct {
 	common
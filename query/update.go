@@ -67,6 +67,7 @@ func (u *UpdateBase) Exec() (sql.Result, error) {
 	}
 
 	// call provider exec with data
+	setLogTable(u.Provider, u.UTable)
 	res, err := u.Provider.Exec([]string{stmt}, [][]interface{}{args})
 	if err != nil {
 		return nil, err
@@ -86,21 +87,19 @@ func (u *UpdateBase) Render() (stmt string, args []interface{}, err error) {
 	u.UColumns = addColumns(u.UColumns, u.UValues)
 
 	// add arguments, remove table name
+	// columns to string, expressions are rendered as they are.
 	var arguments []interface{}
-	for _, column := range u.UColumns {
+	sqlColumns := make([]string, len(u.UColumns))
+	for i, column := range u.UColumns {
 		if val, ok := u.UValues[strings.Replace(column, u.UTable+".", "", 1)]; ok {
-			arguments = append(arguments, val)
+			placeholder, args := valueStmt(val)
+			sqlColumns[i] = u.Provider.QuoteIdentifier(column) + " = " + placeholder
+			arguments = append(arguments, args...)
 		} else {
 			return "", nil, fmt.Errorf(ErrColumn, column, u.UTable)
 		}
 	}
 
-	//columns to string
-	sqlColumns := make([]string, len(u.UColumns))
-	for i, col := range u.UColumns {
-		sqlColumns[i] = u.Provider.QuoteIdentifier(col) + " = " + condition.PLACEHOLDER
-	}
-
 	// render sql
 	selectStmt := "UPDATE " + u.Provider.QuoteIdentifier(u.UTable) + " SET " + strings.Join(sqlColumns, ", ")
 	if u.UCondition != nil {